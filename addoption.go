@@ -0,0 +1,41 @@
+package sdi
+
+// AddOption configures registration of objects added by Add, AddIf,
+// AddGrouped or AddForProfile. It's passed among the objects and applies
+// to all objects of the call:
+//
+//	c.Add(rawDB, sdi.NotInjectable())
+type AddOption func(*registration)
+
+// NotInjectable excludes objects from injection candidates: they're
+// initialized, started and stopped as other objects, but never injected
+// into fields, slices, maps or setters, nor resolved by Resolve or
+// matched by types returned by DependsOn. Objects can still be bound
+// explicitly by Bind or listed by DependsOn.
+func NotInjectable() AddOption {
+	return func(r *registration) {
+		r.notInjectable = true
+	}
+}
+
+// withAddOptions returns objects of o without options and registration r
+// configured by options of o.
+func withAddOptions(o []interface{}, r registration) ([]interface{}, registration) {
+	var objects []interface{}
+	for k := range o {
+		if opt, ok := o[k].(AddOption); ok {
+			if objects == nil {
+				objects = append(make([]interface{}, 0, len(o)), o[:k]...)
+			}
+			opt(&r)
+			continue
+		}
+		if objects != nil {
+			objects = append(objects, o[k])
+		}
+	}
+	if objects == nil {
+		return o, r
+	}
+	return objects, r
+}
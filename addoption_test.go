@@ -0,0 +1,44 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestNotInjectable(t *testing.T) {
+	raw := &A{}
+	b := &B{}
+	cs := sdi.New()
+	cs.Add(raw, sdi.NotInjectable())
+	cs.Add(b, &C{})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if b.AService != nil {
+		t.Errorf("expected not injectable object not to be injected, got %v", b.AService)
+	}
+	if _, err := sdi.Resolve[AI](cs); !errors.Is(err, sdi.ErrUnresolvedDependency) {
+		t.Errorf("expected %v, got %v", sdi.ErrUnresolvedDependency, err)
+	}
+
+	if err := cs.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if raw.Age() != 20 {
+		t.Error("expected not injectable object to be initialized")
+	}
+}
+
+func TestNotInjectableNearMiss(t *testing.T) {
+	cs := sdi.New(sdi.WithUnresolvedPolicy(sdi.FailOnUnresolved))
+	cs.Add(&C{}, sdi.NotInjectable())
+	cs.Add(&B{}, &A{})
+	err := cs.Build()
+	if err == nil || !strings.Contains(err.Error(), "*sdi_test.C (not injectable)") {
+		t.Errorf("expected near miss of not injectable object, got %v", err)
+	}
+}
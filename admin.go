@@ -0,0 +1,139 @@
+package sdi
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// AdminResponse is the JSON document written by AdminHandler.
+type AdminResponse struct {
+	// State is the lifecycle state of the container.
+	State string `json:"state"`
+
+	// Objects are listed in the order of Init calls.
+	Objects []AdminObject `json:"objects"`
+
+	// Graph is the wiring graph in Graphviz DOT format, see GraphDOT.
+	Graph string `json:"graph"`
+}
+
+// AdminObject describes a containered object in AdminResponse.
+type AdminObject struct {
+	Name   string `json:"name"`
+	Module string `json:"module,omitempty"`
+	Type   string `json:"type"`
+
+	// Durations of the last Init and Start calls.
+	Init  string `json:"init"`
+	Start string `json:"start"`
+
+	// Supervision state of the runner, see WithSupervision.
+	Running   bool   `json:"running"`
+	Restarts  int    `json:"restarts"`
+	LastError string `json:"last_error,omitempty"`
+
+	// Health is HealthStatusOK or the error returned by Health of objects
+	// implementing Healther, empty otherwise.
+	Health string `json:"health,omitempty"`
+
+	// Dependencies lists objects injected into fields, a slice or map
+	// field is listed once per injected object.
+	Dependencies []AdminDependency `json:"dependencies,omitempty"`
+
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// AdminDependency describes the object Object injected into the field
+// Field in AdminObject.
+type AdminDependency struct {
+	Field  string `json:"field"`
+	Object string `json:"object"`
+}
+
+// AdminHandler returns http.Handler serving state of the container:
+// containered objects, their dependencies, timings of Init and Start,
+// supervision state and health. The state is written as AdminResponse
+// JSON or, if the client accepts text/html, as HTML page.
+//
+// The handler exposes internals of the application and should not be
+// reachable from public networks.
+func (c *SimpleContainer) AdminHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := c.admin(r)
+		if strings.Contains(r.Header.Get("Accept"), "text/html") {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_ = adminPage.Execute(w, resp)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
+
+// admin collects state of the container.
+func (c *SimpleContainer) admin(r *http.Request) AdminResponse {
+	objects := c.Objects()
+	report := c.StartupReport()
+	health := c.Health(r.Context())
+
+	var graph strings.Builder
+	_ = c.GraphDOT(&graph)
+
+	c.mux.RLock()
+	seq := c.sequence()
+	c.mux.RUnlock()
+
+	resp := AdminResponse{
+		State:   c.State().String(),
+		Objects: make([]AdminObject, 0, len(seq)),
+		Graph:   graph.String(),
+	}
+	for k, i := range seq {
+		oi, or := objects[i], report.Objects[k]
+		ao := AdminObject{
+			Name:     oi.Name,
+			Module:   oi.Module,
+			Type:     oi.Type.String(),
+			Init:     or.Init.String(),
+			Start:    or.Start.String(),
+			Running:  oi.Running,
+			Restarts: oi.Restarts,
+			Warnings: or.Warnings,
+		}
+		if oi.LastError != nil {
+			ao.LastError = oi.LastError.Error()
+		}
+		if err, ok := health[oi.Name]; ok {
+			ao.Health = HealthStatusOK
+			if err != nil {
+				ao.Health = err.Error()
+			}
+		}
+		for _, d := range oi.Dependencies {
+			ao.Dependencies = append(ao.Dependencies, AdminDependency{Field: d.Field, Object: d.Provider})
+		}
+		resp.Objects = append(resp.Objects, ao)
+	}
+	return resp
+}
+
+var adminPage = template.Must(template.New("admin").Parse(`<!DOCTYPE html>
+<html>
+<head><title>sdi</title></head>
+<body>
+<h1>Container {{.State}}</h1>
+<table border="1" cellpadding="4">
+<tr><th>Object</th><th>Module</th><th>Init</th><th>Start</th><th>Running</th><th>Restarts</th><th>Health</th><th>Dependencies</th><th>Warnings</th></tr>
+{{range .Objects}}<tr>
+<td>{{.Name}}</td><td>{{.Module}}</td><td>{{.Init}}</td><td>{{.Start}}</td><td>{{.Running}}</td><td>{{.Restarts}}</td><td>{{.Health}}</td>
+<td>{{range .Dependencies}}{{.Field}}={{.Object}}<br>{{end}}</td>
+<td>{{range .Warnings}}{{.}}<br>{{end}}{{.LastError}}</td>
+</tr>
+{{end}}</table>
+<h2>Graph</h2>
+<pre>{{.Graph}}</pre>
+</body>
+</html>
+`))
@@ -0,0 +1,89 @@
+package sdi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestAdminHandler(t *testing.T) {
+	tr := translator{}
+	cs := sdi.New()
+	cs.AddNamed("dict", dictionary{})
+	cs.AddNamed("tr", &tr)
+	cs.AddNamed("db", &healthy{err: errors.New("connection lost")})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	cs.AdminHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	var resp sdi.AdminResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.State != "initialized" || len(resp.Objects) != 3 || !strings.HasPrefix(resp.Graph, "digraph") {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	if o := resp.Objects[1]; o.Name != "tr" || len(o.Dependencies) != 1 || o.Dependencies[0] != (sdi.AdminDependency{Field: "Dict", Object: "dict"}) {
+		t.Errorf("unexpected object %+v", o)
+	}
+	if o := resp.Objects[2]; o.Health != "connection lost" {
+		t.Errorf("unexpected object %+v", o)
+	}
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "text/html")
+	cs.AdminHandler().ServeHTTP(rec, req)
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "<td>tr</td>") {
+		t.Errorf("expected object in HTML page:\n%s", rec.Body)
+	}
+}
+
+type clientPool struct {
+	Clients []Client
+}
+
+func (p *clientPool) Global() {}
+
+func TestAdminSliceDependencies(t *testing.T) {
+	cs := sdi.New()
+	cs.AddNamed("pool", &clientPool{})
+	cs.AddNamed("a", &client{name: "a"})
+	cs.AddNamed("b", &client{name: "b"})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	cs.AdminHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	var resp sdi.AdminResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	for _, o := range resp.Objects {
+		if o.Name != "pool" {
+			continue
+		}
+		expected := []sdi.AdminDependency{{Field: "Clients", Object: "a"}, {Field: "Clients", Object: "b"}}
+		if !reflect.DeepEqual(o.Dependencies, expected) {
+			t.Errorf("expected %v, got %v", expected, o.Dependencies)
+		}
+		return
+	}
+	t.Errorf("pool not found in %+v", resp.Objects)
+}
@@ -0,0 +1,82 @@
+package sdi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Bind declares that containered object impl satisfies dependencies of
+// type I. Bindings take precedence over automatic matching in
+// BuildDependencies, Resolve and As.
+//
+//	sdi.Bind[Storage](c, pg)
+//
+// The object impl must be added into container before BuildDependencies.
+func Bind[I any](c *SimpleContainer, impl I) {
+	c.Bind((*I)(nil), impl)
+}
+
+// Bind is the non-generic form of function Bind. Parameter iface is a
+// pointer to the bound type, e.g. (*Storage)(nil). It panics if impl is not
+// assignable to the type or BuildDependencies has been called already.
+func (c *SimpleContainer) Bind(iface interface{}, impl interface{}) {
+	pt := reflect.TypeOf(iface)
+	if pt == nil || pt.Kind() != reflect.Ptr {
+		panic("sdi: iface must be a pointer to a type")
+	}
+	t := pt.Elem()
+	if impl == nil || !reflect.TypeOf(impl).AssignableTo(t) {
+		panic(fmt.Sprintf("sdi: %T is not assignable to %s", impl, t))
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.built {
+		panic(fmt.Sprintf("sdi: %s bound after BuildDependencies", t))
+	}
+	if c.bindings == nil {
+		c.bindings = make(map[reflect.Type]interface{})
+	}
+	c.bindings[t] = impl
+}
+
+// AddAs adds object o into container and binds it to type I, see Bind.
+// Unlike Add, the compiler checks that o implements I:
+//
+//	sdi.AddAs[Storage](c, &pgStorage{})
+//
+// It panics if another object is bound to I already, and in the same
+// cases as Add.
+func AddAs[I any](c *SimpleContainer, o I) {
+	t := reflect.TypeOf((*I)(nil)).Elem()
+	c.mux.RLock()
+	impl, ok := c.bindings[t]
+	c.mux.RUnlock()
+	if ok {
+		panic(fmt.Sprintf("sdi: %s is bound to %T already", t, impl))
+	}
+
+	c.Add(o)
+	c.Bind((*I)(nil), o)
+}
+
+// bound returns position of the object bound to type t.
+func (c *SimpleContainer) bound(t reflect.Type) (int, bool) {
+	impl, ok := c.bindings[t]
+	if !ok {
+		return -1, false
+	}
+	i := c.indexOf(impl)
+	return i, i >= 0
+}
+
+// checkBindings returns error if a bound object is not containered.
+func (c *SimpleContainer) checkBindings() error {
+	for t, impl := range c.bindings {
+		if c.indexOf(impl) < 0 {
+			return fmt.Errorf("%w: %T bound to %s is not containered", ErrUnresolvedDependency, impl, t)
+		}
+	}
+	return nil
+}
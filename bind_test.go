@@ -0,0 +1,57 @@
+package sdi_test
+
+import (
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestBind(t *testing.T) {
+	cs := sdi.New()
+	primary := C{gender: "primary"}
+	b := B{}
+	cs.Add(&A{}, &primary, &b, &C{gender: "replica"})
+	sdi.Bind[CI](cs, &primary)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	if b.CService != &primary {
+		t.Error("expected bound object to be injected")
+	}
+	if ci := sdi.MustResolve[CI](cs); ci != &primary {
+		t.Error("expected bound object to be resolved")
+	}
+}
+
+func TestBindNotContainered(t *testing.T) {
+	cs := sdi.New()
+	cs.Add(&C{})
+	cs.Bind((*CI)(nil), &C{})
+	if err := cs.Build(); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestAddAs(t *testing.T) {
+	cs := sdi.New()
+	primary := C{gender: "primary"}
+	b := B{}
+	cs.Add(&A{}, &b, &C{gender: "replica"})
+	sdi.AddAs[CI](cs, &primary)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if b.CService != &primary {
+		t.Error("expected object added by AddAs to be injected")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on second object bound to CI")
+		}
+	}()
+	cs2 := sdi.New()
+	sdi.AddAs[CI](cs2, &C{})
+	sdi.AddAs[CI](cs2, &C{})
+}
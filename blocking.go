@@ -0,0 +1,87 @@
+package sdi
+
+import (
+	"context"
+	"time"
+)
+
+// BlockingRunner is the interface that wraps the basic Blocking method.
+//
+// Blocking marks the runner whose Start blocks until the runner is stopped,
+// e.g. HTTP server calling ListenAndServe in Start. StartRunners and
+// StartRunnersConcurrent call Start of such runners in a separate
+// goroutine, the same way as WithManagedRunners does for all runners:
+// errors returned by Start are sent to Errors and reported by Wait.
+// Runners of types which can't implement Blocking, e.g. of other packages,
+// can be added by AddBlocking.
+type BlockingRunner interface {
+	Runner
+	Blocking()
+}
+
+// AddBlocking adds runners whose Start blocks until they are stopped, as if
+// they implemented BlockingRunner:
+//
+//	c.AddBlocking(grpcServer)
+//
+// It panics in the same cases as Add.
+func (c *SimpleContainer) AddBlocking(r ...Runner) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for i := range r {
+		mustBeContainerable(r[i])
+		c.add(r[i], registration{blocking: true})
+	}
+}
+
+// blocking reports whether the runner at position i is blocking.
+func (c *SimpleContainer) blocking(i int) bool {
+	_, ok := c.objects[i].(BlockingRunner)
+	return ok || c.regs[i].blocking
+}
+
+// WithStartWarning makes the container warn about Start of a runner
+// running longer than threshold d, as it appears to block. The warning is
+// written to the logger set by WithLogger or to the standard logger and
+// emitted as StartBlocking event. Start of a runner must return once
+// the runner is started, blocking servers must run in a goroutine or
+// implement BlockingRunner. Runners of containers created with
+// WithManagedRunners or WithSupervision are not watched. Zero or negative
+// d turns the warning off, as without the option.
+func WithStartWarning(d time.Duration) Option {
+	return func(o *options) {
+		o.startWarning = d
+	}
+}
+
+// watchStart warns about Start of the runner name running longer than
+// the threshold set by WithStartWarning. The returned function stops
+// watching.
+func (c *SimpleContainer) watchStart(name string) func() {
+	d := c.opts.startWarning
+	if d <= 0 {
+		return func() {}
+	}
+	t := time.AfterFunc(d, func() {
+		c.printf("sdi: %s.Start has not returned in %s, it appears to block; run blocking servers in a goroutine or implement BlockingRunner", name, d)
+		c.emit(Event{Type: StartBlocking, Object: name, Duration: d})
+	})
+	return func() { t.Stop() }
+}
+
+// startBlocking calls Start of the blocking runner at position i in
+// a separate goroutine.
+func (c *SimpleContainer) startBlocking(ctx context.Context, i int, r Runner) {
+	errc, done := c.errorsChan(), c.doneChan()
+	c.setState(i, func(s *objectState) {
+		s.running = true
+		s.started = true
+		s.stopped = false
+	})
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.supervise(ctx, i, r, errc, done)
+	}()
+}
@@ -0,0 +1,149 @@
+package sdi_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+// stoppableServer blocks in Start until stopped, like
+// http.Server.ListenAndServe.
+type stoppableServer struct {
+	stop chan struct{}
+	once sync.Once
+}
+
+func (s *stoppableServer) Start(ctx context.Context) error {
+	<-s.stop
+	return errors.New("server closed")
+}
+
+func (s *stoppableServer) Stop(ctx context.Context) error {
+	s.once.Do(func() { close(s.stop) })
+	return nil
+}
+
+// markedServer is marked as BlockingRunner.
+type markedServer struct {
+	stoppableServer
+}
+
+func (s *markedServer) Blocking() {}
+
+func TestStartWarning(t *testing.T) {
+	var (
+		buf  bytes.Buffer
+		mux  sync.Mutex
+		seen []string
+	)
+	ctx := context.Background()
+	srv := &stoppableServer{stop: make(chan struct{})}
+	cs := sdi.New(sdi.WithStartWarning(10*time.Millisecond), sdi.WithLogger(log.New(&buf, "", 0)))
+	cs.Subscribe(func(e sdi.Event) {
+		if e.Type != sdi.StartBlocking {
+			return
+		}
+		mux.Lock()
+		seen = append(seen, e.Object)
+		mux.Unlock()
+		srv.Stop(ctx)
+	})
+	cs.AddNamed("server", srv)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	cs.StartRunners(ctx)
+
+	mux.Lock()
+	defer mux.Unlock()
+	if len(seen) != 1 || seen[0] != "server" {
+		t.Errorf("expected start blocking event of server, got %v", seen)
+	}
+	if !strings.Contains(buf.String(), "server.Start has not returned in 10ms, it appears to block") {
+		t.Errorf("expected warning, got %q", buf.String())
+	}
+}
+
+func TestBlockingRunner(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := context.Background()
+	srv := &markedServer{stoppableServer{stop: make(chan struct{})}}
+	cs := sdi.New(sdi.WithStartWarning(time.Millisecond), sdi.WithLogger(log.New(&buf, "", 0)))
+	cs.Add(srv, &A{})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	err := cs.Wait(ctx)
+	var re sdi.RunnerError
+	if !errors.As(err, &re) || re.Err.Error() != "server closed" {
+		t.Errorf("expected runner error, got %v", err)
+	}
+	if strings.Contains(buf.String(), "appears to block") {
+		t.Errorf("expected no warning for blocking runner, got %q", buf.String())
+	}
+}
+
+func TestAddBlocking(t *testing.T) {
+	ctx := context.Background()
+	srv := &stoppableServer{stop: make(chan struct{})}
+	cs := sdi.New(sdi.WithStartWarning(time.Millisecond))
+	cs.AddBlocking(srv)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.Wait(ctx); err == nil || !strings.Contains(err.Error(), "server closed") {
+		t.Errorf("expected runner exit reported by Wait, got %v", err)
+	}
+}
+
+// slowRunner takes a while to start.
+type slowRunner struct{}
+
+func (slowRunner) Start(ctx context.Context) error {
+	time.Sleep(20 * time.Millisecond)
+	return nil
+}
+
+func TestStartWarningOff(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := context.Background()
+	cs := sdi.New(sdi.WithLogger(log.New(&buf, "", 0)))
+	cs.Add(&slowRunner{})
+	cs.BuildDependencies()
+	cs.InitRequired(ctx)
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "appears to block") {
+		t.Errorf("expected no warning without WithStartWarning, got %q", buf.String())
+	}
+}
@@ -0,0 +1,102 @@
+package sdi
+
+import (
+	"reflect"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// BuildInfo describes the build of the application. The container injects
+// it into fields of type *BuildInfo and of interface types having method
+// BuildInfo implemented by *BuildInfo, e.g. BuildInfoProvider, unless
+// a containered object is assignable to the field. It's resolved by
+// Resolve and As as well. By default it's read by runtime/debug.ReadBuildInfo,
+// WithBuildInfo sets it explicitly.
+type BuildInfo struct {
+	// Path is the path of the main module.
+	Path string
+
+	// Version is the version of the main module, "(devel)" for builds
+	// outside of module cache.
+	Version string
+
+	// Commit, Time and Modified describe the version control revision
+	// the application is built from.
+	Commit   string
+	Time     time.Time
+	Modified bool
+
+	// GoVersion is the version of Go toolchain.
+	GoVersion string
+}
+
+// BuildInfoProvider is the interface that wraps the basic BuildInfo
+// method, implemented by *BuildInfo.
+type BuildInfoProvider interface {
+	BuildInfo() BuildInfo
+}
+
+// BuildInfo returns copy of b.
+func (b *BuildInfo) BuildInfo() BuildInfo {
+	return *b
+}
+
+// Global implements Globalizer interface.
+func (b *BuildInfo) Global() {}
+
+// WithBuildInfo sets build info injected by the container, e.g. version
+// set by linker flags, instead of the one read by
+// runtime/debug.ReadBuildInfo.
+func WithBuildInfo(b BuildInfo) Option {
+	return func(o *options) {
+		o.buildInfo = &b
+	}
+}
+
+var (
+	buildInfoOnce sync.Once
+	buildInfo     *BuildInfo
+	buildInfoType = reflect.TypeOf((*BuildInfo)(nil))
+)
+
+// readBuildInfo returns build info embedded into the binary.
+func readBuildInfo() *BuildInfo {
+	buildInfoOnce.Do(func() {
+		buildInfo = &BuildInfo{}
+		bi, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+		buildInfo.Path = bi.Main.Path
+		buildInfo.Version = bi.Main.Version
+		buildInfo.GoVersion = bi.GoVersion
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				buildInfo.Commit = s.Value
+			case "vcs.time":
+				buildInfo.Time, _ = time.Parse(time.RFC3339, s.Value)
+			case "vcs.modified":
+				buildInfo.Modified = s.Value == "true"
+			}
+		}
+	})
+	return buildInfo
+}
+
+// buildInfoFor returns build info if it's injected into type t.
+func (c *SimpleContainer) buildInfoFor(t reflect.Type) (*BuildInfo, bool) {
+	if t != buildInfoType {
+		if t.Kind() != reflect.Interface || !buildInfoType.Implements(t) {
+			return nil, false
+		}
+		if _, ok := t.MethodByName("BuildInfo"); !ok {
+			return nil, false
+		}
+	}
+	if c.opts.buildInfo != nil {
+		return c.opts.buildInfo, true
+	}
+	return readBuildInfo(), true
+}
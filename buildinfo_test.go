@@ -0,0 +1,56 @@
+package sdi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type versionEndpoint struct {
+	Build   sdi.BuildInfoProvider
+	Info    *sdi.BuildInfo
+	Service fmtStringer
+}
+
+type fmtStringer interface {
+	String() string
+}
+
+func (v *versionEndpoint) Init(ctx context.Context) error { return nil }
+
+func TestBuildInfo(t *testing.T) {
+	v := &versionEndpoint{}
+	cs := sdi.New(sdi.WithBuildInfo(sdi.BuildInfo{Version: "v1.2.3", Commit: "abc"}))
+	cs.Add(v)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Build == nil || v.Build.BuildInfo().Version != "v1.2.3" {
+		t.Errorf("expected build info injected, got %v", v.Build)
+	}
+	if v.Info == nil || v.Info.Commit != "abc" {
+		t.Errorf("expected *BuildInfo injected, got %v", v.Info)
+	}
+	if v.Service != nil {
+		t.Errorf("expected unrelated interface left nil, got %v", v.Service)
+	}
+
+	b, err := sdi.Resolve[*sdi.BuildInfo](cs)
+	if err != nil || b.Version != "v1.2.3" {
+		t.Errorf("expected resolved build info, got %v, %v", b, err)
+	}
+}
+
+func TestBuildInfoDefault(t *testing.T) {
+	v := &versionEndpoint{}
+	cs := sdi.New()
+	cs.Add(v)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if v.Info == nil || v.Info.GoVersion == "" {
+		t.Errorf("expected build info read from binary, got %+v", v.Info)
+	}
+}
@@ -0,0 +1,79 @@
+package sdi
+
+import (
+	"reflect"
+	"sort"
+)
+
+// typeCache holds types of containered objects and positions of objects
+// assignable to a type. It's used by BuildDependencies while the list of
+// objects is frozen, so assignability of each pair of field type and
+// object type is checked once, however many objects share the type.
+type typeCache struct {
+	// unique lists distinct types of objects in the order of the first
+	// object of the type, positions holds positions of objects by type.
+	unique    []reflect.Type
+	positions map[reflect.Type][]int
+
+	assignable map[reflect.Type][]int
+	matrix     map[typePair]bool
+}
+
+// typePair is a pair of object type and dependency type.
+type typePair struct {
+	object reflect.Type
+	target reflect.Type
+}
+
+// types returns the cache creating it on the first call. The caller must
+// hold write lock.
+func (c *SimpleContainer) types() *typeCache {
+	if c.cache == nil {
+		c.cache = &typeCache{
+			positions:  make(map[reflect.Type][]int),
+			assignable: make(map[reflect.Type][]int),
+			matrix:     make(map[typePair]bool),
+		}
+		for i := range c.objects {
+			if c.regs[i].notInjectable {
+				continue
+			}
+			ot := reflect.TypeOf(c.objects[i])
+			if _, ok := c.cache.positions[ot]; !ok {
+				c.cache.unique = append(c.cache.unique, ot)
+			}
+			c.cache.positions[ot] = append(c.cache.positions[ot], i)
+		}
+	}
+	return c.cache
+}
+
+// assignableTo returns positions of containered objects assignable to
+// type t in the order they've been added. The caller must hold write lock.
+func (c *SimpleContainer) assignableTo(t reflect.Type) []int {
+	tc := c.types()
+	res, ok := tc.assignable[t]
+	if !ok {
+		for _, ot := range tc.unique {
+			if c.isAssignable(ot, t) {
+				res = append(res, tc.positions[ot]...)
+			}
+		}
+		sort.Ints(res)
+		tc.assignable[t] = res
+	}
+	return res
+}
+
+// isAssignable reports whether values of object type ot are assignable to
+// type t. The caller must hold write lock.
+func (c *SimpleContainer) isAssignable(ot, t reflect.Type) bool {
+	tc := c.types()
+	p := typePair{object: ot, target: t}
+	ok, found := tc.matrix[p]
+	if !found {
+		ok = ot.AssignableTo(t)
+		tc.matrix[p] = ok
+	}
+	return ok
+}
@@ -0,0 +1,87 @@
+package sdi_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type (
+	benchDB     interface{ Query() }
+	benchCache  interface{ Lookup() }
+	benchLogger interface{ Log() }
+)
+
+type benchProvider struct{}
+
+func (p *benchProvider) Global() {}
+func (p *benchProvider) Query()  {}
+func (p *benchProvider) Lookup() {}
+func (p *benchProvider) Log()    {}
+
+type benchConsumer struct {
+	DB     benchDB
+	Cache  benchCache
+	Logger benchLogger
+	Deps   struct {
+		DB     benchDB
+		Logger benchLogger
+	}
+}
+
+func (bc *benchConsumer) Global() {}
+
+func BenchmarkBuildDependencies(b *testing.B) {
+	for _, n := range []int{10, 100, 200, 500} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			for k := 0; k < b.N; k++ {
+				cs := sdi.New()
+				cs.Add(&benchProvider{})
+				for i := 0; i < n; i++ {
+					cs.Add(&benchConsumer{})
+				}
+				if err := cs.Build(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// benchPort is instantiated with distinct array types to get many distinct
+// interface types none of the containered objects implements.
+type benchPort[T any] interface {
+	Handle(T)
+}
+
+type benchService struct {
+	P1  benchPort[[1]int]
+	P2  benchPort[[2]int]
+	P3  benchPort[[3]int]
+	P4  benchPort[[4]int]
+	P5  benchPort[[5]int]
+	P6  benchPort[[6]int]
+	P7  benchPort[[7]int]
+	P8  benchPort[[8]int]
+	DB  benchDB
+	Log benchLogger
+}
+
+func (bs *benchService) Global() {}
+
+// BenchmarkBuildDependenciesFieldTypes wires 200 objects of the same type
+// with many distinct field types, assignability of each field type is
+// checked once per object type instead of once per object.
+func BenchmarkBuildDependenciesFieldTypes(b *testing.B) {
+	for k := 0; k < b.N; k++ {
+		cs := sdi.New()
+		cs.Add(&benchProvider{})
+		for i := 0; i < 199; i++ {
+			cs.Add(&benchService{})
+		}
+		if err := cs.Build(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
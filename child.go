@@ -0,0 +1,18 @@
+package sdi
+
+// NewChild returns an empty container inheriting options of c.
+//
+// BuildDependencies of the child assigns objects of the parent container
+// to interface fields that can not be satisfied by objects of the child.
+// Resolve, As and GetByType fall back to the parent the same way.
+//
+// Lifecycle of the child is independent: InitRequired, StartRunners and
+// Stop of the child affect only objects added into the child.
+func (c *SimpleContainer) NewChild() *SimpleContainer {
+	child := &SimpleContainer{
+		opts:   c.opts,
+		parent: c,
+	}
+	child.opts.observers = append([]Observer(nil), c.opts.observers...)
+	return child
+}
@@ -0,0 +1,40 @@
+package sdi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestNewChild(t *testing.T) {
+	parent := sdi.New()
+	a := A{age: 40}
+	pc := C{gender: "F"}
+	parent.Add(&a, &pc)
+	parent.BuildDependencies()
+
+	child := parent.NewChild()
+	b := B{}
+	cc := C{gender: "M"}
+	child.Add(&b, &cc)
+	child.BuildDependencies()
+
+	if b.AService != &a {
+		t.Error("expected AService to be resolved from parent")
+	}
+	if b.CService != &cc {
+		t.Error("expected CService to be resolved from child")
+	}
+
+	if _, err := sdi.Resolve[AI](child); err != nil {
+		t.Error(err)
+	}
+
+	if err := child.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if a.age != 40 {
+		t.Error("child InitRequired must not init parent objects")
+	}
+}
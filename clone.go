@@ -0,0 +1,145 @@
+package sdi
+
+import "reflect"
+
+// Clone returns a container with the same options, registrations and
+// bindings but fresh wiring and lifecycle state. Objects that are pointers
+// to structs are copied, so linking, replacing and running the clone does
+// not affect the original container. Other objects are shared.
+//
+// Exported pointer and interface fields of copies pointing to objects of
+// the original container are pointed to their copies, objects returned by
+// DependsOn of copies are substituted by their copies the same way.
+// Gates are copied as well: runners of the clone wait for the gate
+// returned by Gate of the clone, opening a gate of the original container
+// does not open it.
+//
+// Clone is intended for table-driven tests and must be called before
+// BuildDependencies, it panics otherwise.
+func (c *SimpleContainer) Clone() *SimpleContainer {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	if c.built {
+		panic("sdi: container cloned after BuildDependencies")
+	}
+
+	clone := &SimpleContainer{
+		opts:    c.opts,
+		parent:  c.parent,
+		objects: make([]interface{}, len(c.objects)),
+		regs:    append([]registration(nil), c.regs...),
+
+		transients: append([]transient(nil), c.transients...),
+		providers:  append([]provider(nil), c.providers...),
+	}
+	clone.opts.observers = append([]Observer(nil), c.opts.observers...)
+	if c.loggers != nil {
+		clone.loggers = make(map[reflect.Type]func(string) interface{}, len(c.loggers))
+		for t, f := range c.loggers {
+			clone.loggers[t] = f
+		}
+	}
+
+	if c.decorators != nil {
+		clone.decorators = make(map[reflect.Type][]reflect.Value, len(c.decorators))
+		for t, ds := range c.decorators {
+			clone.decorators[t] = append([]reflect.Value(nil), ds...)
+		}
+	}
+	clone.subscribeSlog()
+
+	if c.gates != nil {
+		gates := make(map[*Gate]*Gate, len(c.gates))
+		clone.gates = make(map[string]*Gate, len(c.gates))
+		for name, g := range c.gates {
+			ng := &Gate{name: name, opened: make(chan struct{})}
+			if g.IsOpen() {
+				ng.Open()
+			}
+			gates[g] = ng
+			clone.gates[name] = ng
+		}
+		for i := range clone.regs {
+			if ng, ok := gates[clone.regs[i].gate]; ok {
+				clone.regs[i].gate = ng
+			}
+		}
+	}
+
+	copies := make(map[interface{}]interface{})
+	for i, o := range c.objects {
+		clone.objects[i] = copyObject(o)
+		if reflect.TypeOf(o).Comparable() {
+			copies[o] = clone.objects[i]
+		}
+	}
+	for _, o := range clone.objects {
+		repointCopies(o, copies)
+	}
+	// objects of a clone may still refer to objects of its origin.
+	for orig, cp := range c.copies {
+		if cp, ok := copies[cp]; ok {
+			copies[orig] = cp
+		}
+	}
+	clone.copies = copies
+
+	if c.bindings != nil {
+		clone.bindings = make(map[reflect.Type]interface{}, len(c.bindings))
+		for t, impl := range c.bindings {
+			if cp, ok := copies[impl]; ok {
+				impl = cp
+			}
+			clone.bindings[t] = impl
+		}
+	}
+	return clone
+}
+
+// copyObject returns a copy of the struct pointed to by o or o itself if
+// it's not a pointer to struct.
+func copyObject(o interface{}) interface{} {
+	v := reflect.ValueOf(o)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return o
+	}
+	cp := reflect.New(v.Elem().Type())
+	cp.Elem().Set(v.Elem())
+	return cp.Interface()
+}
+
+// repointCopies points exported pointer and interface fields of the struct
+// pointed to by o to copies of objects they point to.
+func repointCopies(o interface{}, copies map[interface{}]interface{}) {
+	v := reflect.ValueOf(o)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	v = v.Elem()
+	for k := 0; k < v.NumField(); k++ {
+		f := v.Field(k)
+		if !f.CanSet() || (f.Kind() != reflect.Ptr && f.Kind() != reflect.Interface) || f.IsNil() {
+			continue
+		}
+		fv := f.Interface()
+		if !reflect.TypeOf(fv).Comparable() {
+			continue
+		}
+		if cp, ok := copies[fv]; ok && reflect.TypeOf(cp).AssignableTo(f.Type()) {
+			f.Set(reflect.ValueOf(cp))
+		}
+	}
+}
+
+// copyOf returns the copy of object o of the cloned container, or o
+// itself if it's not copied, see Clone.
+func (c *SimpleContainer) copyOf(o interface{}) interface{} {
+	if c.copies == nil || o == nil || !reflect.TypeOf(o).Comparable() {
+		return o
+	}
+	if cp, ok := c.copies[o]; ok {
+		return cp
+	}
+	return o
+}
@@ -0,0 +1,79 @@
+package sdi_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestClone(t *testing.T) {
+	base := sdi.New()
+	primary := C{gender: "primary"}
+	base.Add(&primary, &B{})
+
+	for _, tc := range []struct {
+		name   string
+		fake   *C
+		expect string
+	}{
+		{"real", nil, "primary"},
+		{"fake", &C{gender: "fake"}, "fake"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cs := base.Clone()
+			if tc.fake != nil {
+				ci := sdi.MustResolve[CI](cs)
+				if err := cs.Replace(ci, tc.fake); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := cs.Build(); err != nil {
+				t.Fatal(err)
+			}
+			b := sdi.MustResolve[*B](cs)
+			if got := b.CService.(*C).gender; got != tc.expect {
+				t.Errorf("expected %s, got %s", tc.expect, got)
+			}
+		})
+	}
+
+	if b := sdi.MustResolve[*B](base); b.CService != nil {
+		t.Error("expected original objects to be intact")
+	}
+}
+
+type preset struct {
+	Dep *journaled
+}
+
+func (p *preset) Global() {}
+
+func TestCloneReferences(t *testing.T) {
+	var jn journal
+	base := sdi.New()
+	migrator := &journaled{name: "migrator", journal: &jn}
+	api := &dependent{journaled: journaled{name: "api", journal: &jn}, deps: []interface{}{migrator}}
+	base.Add(api, migrator, &preset{Dep: migrator})
+
+	for _, cs := range []*sdi.SimpleContainer{base.Clone(), base.Clone().Clone()} {
+		jn.calls = nil
+		if err := cs.Build(); err != nil {
+			t.Fatal(err)
+		}
+		if err := cs.InitRequired(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"init migrator", "init api"}; !reflect.DeepEqual(jn.calls, want) {
+			t.Errorf("expected %v, got %v", want, jn.calls)
+		}
+		m := sdi.MustResolve[*journaled](cs)
+		if m == migrator {
+			t.Fatal("expected migrator to be copied")
+		}
+		if p := sdi.MustResolve[*preset](cs); p.Dep != m {
+			t.Error("expected preset field to point to the copy of migrator")
+		}
+	}
+}
@@ -0,0 +1,547 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const sdiPath = "github.com/axkit/sdi"
+
+// object is an object added into a container by the analyzed package.
+type object struct {
+	pos   token.Position
+	name  string // name passed to AddNamed
+	typ   *types.Named
+	fn    types.Type      // type of function added by AddFunc, typ is nil
+	set   map[string]bool // fields set in the composite literal
+	all   bool            // all fields set by positional composite literal
+	deps  []int
+	wires []wire
+
+	initializer, runner, stopper bool
+
+	// notInjectable is set by option NotInjectable.
+	notInjectable bool
+}
+
+// typeOf returns type of the object added into container.
+func (o *object) typeOf() types.Type {
+	if o.fn != nil {
+		return o.fn
+	}
+	return types.NewPointer(o.typ)
+}
+
+// wire is assignment of providers to field of an object.
+type wire struct {
+	field     string
+	providers []int
+	slice     types.Type // slice type if the field is a slice
+}
+
+// generate returns source of wiring code for the package in dir and
+// notes on fields the code leaves unset.
+func generate(dir, out, typeName string) ([]byte, []string, error) {
+	a, err := load(dir, out)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := a.link(); err != nil {
+		return nil, nil, err
+	}
+	order, err := a.order()
+	if err != nil {
+		return nil, nil, err
+	}
+	src, err := a.render(typeName, order)
+	return src, a.notes, err
+}
+
+// load returns analyzer of objects added into container by the package
+// in dir, except the file out.
+func load(dir, out string) (*analyzer, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != out
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("sdigen: expected one package in %s, found %d", dir, len(pkgs))
+	}
+
+	var (
+		name  string
+		files []*ast.File
+	)
+	for n, p := range pkgs {
+		name = n
+		fnames := make([]string, 0, len(p.Files))
+		for fn := range p.Files {
+			fnames = append(fnames, fn)
+		}
+		sort.Strings(fnames)
+		for _, fn := range fnames {
+			files = append(files, p.Files[fn])
+		}
+	}
+
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check(name, fset, files, info)
+	if err != nil {
+		return nil, err
+	}
+
+	a := analyzer{fset: fset, pkg: pkg, info: info}
+	if err := a.collect(files); err != nil {
+		return nil, err
+	}
+	if len(a.objects) == 0 {
+		return nil, fmt.Errorf("sdigen: no objects added into container in %s", dir)
+	}
+	return &a, nil
+}
+
+type analyzer struct {
+	fset    *token.FileSet
+	pkg     *types.Package
+	info    *types.Info
+	objects []*object
+	notes   []string // fields left unset by generated code
+}
+
+// collect finds objects added into container by Add, AddService, AddNamed
+// and AddFunc calls.
+func (a *analyzer) collect(files []*ast.File) error {
+	var err error
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			if err != nil {
+				return false
+			}
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			se, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || !a.isContainer(se) {
+				return true
+			}
+
+			args := call.Args
+			var name string
+			switch se.Sel.Name {
+			case "AddFunc":
+				for _, arg := range args {
+					t := a.info.TypeOf(arg)
+					if _, ok := t.Underlying().(*types.Signature); !ok {
+						err = fmt.Errorf("%s: sdigen: %s is not a function", a.fset.Position(arg.Pos()), t)
+						return false
+					}
+					a.objects = append(a.objects, &object{pos: a.fset.Position(arg.Pos()), fn: t})
+				}
+				return true
+			case "Add", "AddService":
+			case "AddNamed":
+				if len(args) != 2 {
+					return true
+				}
+				lit, ok := args[0].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					err = fmt.Errorf("%s: sdigen: object name must be a string literal", a.fset.Position(args[0].Pos()))
+					return false
+				}
+				name, _ = strconv.Unquote(lit.Value)
+				args = args[1:]
+			default:
+				return true
+			}
+
+			var objects []ast.Expr
+			injectable := true
+			for _, arg := range args {
+				if !a.isAddOption(arg) {
+					objects = append(objects, arg)
+					continue
+				}
+				if oc, ok := arg.(*ast.CallExpr); ok && calleeName(oc.Fun) == "NotInjectable" {
+					injectable = false
+				}
+			}
+			for _, arg := range objects {
+				var o *object
+				if o, err = a.object(arg); err != nil {
+					return false
+				}
+				o.name = name
+				o.notInjectable = !injectable
+				a.objects = append(a.objects, o)
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isAddOption returns true if e is of type sdi.AddOption.
+func (a *analyzer) isAddOption(e ast.Expr) bool {
+	n, ok := a.info.TypeOf(e).(*types.Named)
+	return ok && a.isSDIType(n) && n.Obj().Name() == "AddOption"
+}
+
+// calleeName returns name of the function called by expression e.
+func calleeName(e ast.Expr) string {
+	switch e := e.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	}
+	return ""
+}
+
+// isContainer returns true if se selects a method of sdi container.
+func (a *analyzer) isContainer(se *ast.SelectorExpr) bool {
+	sel, ok := a.info.Selections[se]
+	if !ok || sel.Kind() != types.MethodVal {
+		return false
+	}
+	t := sel.Recv()
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	n, ok := t.(*types.Named)
+	if !ok || n.Obj().Pkg() == nil || n.Obj().Pkg().Path() != sdiPath {
+		return false
+	}
+	return n.Obj().Name() == "SimpleContainer" || n.Obj().Name() == "Container"
+}
+
+// object returns object added by expression e.
+func (a *analyzer) object(e ast.Expr) (*object, error) {
+	pos := a.fset.Position(e.Pos())
+	p, ok := a.info.TypeOf(e).(*types.Pointer)
+	if !ok {
+		return nil, fmt.Errorf("%s: sdigen: %s is not a pointer", pos, a.info.TypeOf(e))
+	}
+	n, ok := p.Elem().(*types.Named)
+	if !ok || n.Obj().Pkg() != a.pkg {
+		return nil, fmt.Errorf("%s: sdigen: %s is not a type declared in package %s", pos, p.Elem(), a.pkg.Name())
+	}
+	if _, ok := n.Underlying().(*types.Struct); !ok {
+		return nil, fmt.Errorf("%s: sdigen: %s is not a struct", pos, n.Obj().Name())
+	}
+
+	o := object{pos: pos, typ: n, set: make(map[string]bool)}
+	if ue, ok := e.(*ast.UnaryExpr); ok && ue.Op == token.AND {
+		if cl, ok := ue.X.(*ast.CompositeLit); ok {
+			for _, elt := range cl.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					o.all = true
+					break
+				}
+				if id, ok := kv.Key.(*ast.Ident); ok {
+					o.set[id.Name] = true
+				}
+			}
+		}
+	}
+
+	sdi := a.sdiPackage()
+	o.initializer = a.implements(p, sdi, "Initializer")
+	o.runner = a.implements(p, sdi, "Runner")
+	o.stopper = a.implements(p, sdi, "Stopper")
+	return &o, nil
+}
+
+func (a *analyzer) sdiPackage() *types.Package {
+	for _, p := range a.pkg.Imports() {
+		if p.Path() == sdiPath {
+			return p
+		}
+	}
+	return nil
+}
+
+func (a *analyzer) implements(t types.Type, sdi *types.Package, iface string) bool {
+	if sdi == nil {
+		return false
+	}
+	obj := sdi.Scope().Lookup(iface)
+	if obj == nil {
+		return false
+	}
+	it, ok := obj.Type().Underlying().(*types.Interface)
+	return ok && types.Implements(t, it)
+}
+
+// link finds providers of exported fields of each object.
+func (a *analyzer) link() error {
+	var errs []string
+	for i, o := range a.objects {
+		if o.fn != nil {
+			continue
+		}
+		st := o.typ.Underlying().(*types.Struct)
+		for k := 0; k < st.NumFields(); k++ {
+			f := st.Field(k)
+			if !f.Exported() || f.Anonymous() || o.all || o.set[f.Name()] {
+				continue
+			}
+			tag := reflect.StructTag(st.Tag(k)).Get("sdi")
+			if _, ok := parseTag(tag)["env"]; ok {
+				a.notes = append(a.notes, fmt.Sprintf("%s: sdigen: %s.%s: field tagged env is not set", o.pos, o.typ.Obj().Name(), f.Name()))
+				continue
+			}
+			w, err := a.wire(i, f, tag)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: sdigen: %s.%s: %v", o.pos, o.typ.Obj().Name(), f.Name(), err))
+				continue
+			}
+			if w == nil {
+				continue
+			}
+			o.wires = append(o.wires, *w)
+			o.deps = append(o.deps, w.providers...)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// wire returns assignment of the field f of the object at position pos
+// or nil if the field is not wired.
+func (a *analyzer) wire(pos int, f *types.Var, tag string) (*wire, error) {
+	opts := parseTag(tag)
+	ft := f.Type()
+
+	if q, ok := opts["qualifier"]; ok {
+		for i, o := range a.objects {
+			if i == pos || o.name != q {
+				continue
+			}
+			if !types.AssignableTo(o.typeOf(), ft) {
+				return nil, fmt.Errorf("object %q is not assignable to %s", q, ft)
+			}
+			return &wire{field: f.Name(), providers: []int{i}}, nil
+		}
+		return nil, fmt.Errorf("no object named %q", q)
+	}
+
+	switch u := ft.Underlying().(type) {
+	case *types.Slice:
+		if !types.IsInterface(u.Elem()) {
+			return nil, nil
+		}
+		if p := a.assignable(pos, u.Elem()); len(p) > 0 {
+			return &wire{field: f.Name(), providers: p, slice: ft}, nil
+		}
+		return nil, nil
+	case *types.Interface, *types.Pointer, *types.Signature:
+	default:
+		return nil, nil
+	}
+
+	if a.isSDIType(ft) {
+		return nil, fmt.Errorf("injection of %s is not supported", ft)
+	}
+
+	p := a.assignable(pos, ft)
+	switch len(p) {
+	case 0:
+		return nil, fmt.Errorf("no object assignable to %s", types.TypeString(ft, a.qualifier))
+	case 1:
+		return &wire{field: f.Name(), providers: p}, nil
+	}
+	names := make([]string, len(p))
+	for k, i := range p {
+		names[k] = types.TypeString(a.objects[i].typeOf(), a.qualifier)
+	}
+	return nil, fmt.Errorf("ambiguous dependency %s: %s", types.TypeString(ft, a.qualifier), strings.Join(names, ", "))
+}
+
+// assignable returns positions of objects, except the object at position
+// pos and objects not injectable, assignable to type t.
+func (a *analyzer) assignable(pos int, t types.Type) []int {
+	var res []int
+	for i, o := range a.objects {
+		if i != pos && !o.notInjectable && types.AssignableTo(o.typeOf(), t) {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// isSDIType returns true if t is a type declared in package sdi or pointer
+// to it, e.g. sdi.Container.
+func (a *analyzer) isSDIType(t types.Type) bool {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	n, ok := t.(*types.Named)
+	return ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == sdiPath
+}
+
+// order returns positions of objects ordered so that each object follows
+// its providers. Otherwise objects keep the order they've been added.
+func (a *analyzer) order() ([]int, error) {
+	done := make([]bool, len(a.objects))
+	res := make([]int, 0, len(a.objects))
+	for len(res) < len(a.objects) {
+		next := -1
+		for i, o := range a.objects {
+			if done[i] {
+				continue
+			}
+			ready := true
+			for _, d := range o.deps {
+				if !done[d] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			return nil, fmt.Errorf("sdigen: dependency cycle")
+		}
+		done[next] = true
+		res = append(res, next)
+	}
+	return res, nil
+}
+
+// qualifier omits name of the analyzed package in type names.
+func (a *analyzer) qualifier(p *types.Package) string {
+	if p == a.pkg {
+		return ""
+	}
+	return p.Name()
+}
+
+// render returns formatted source of the generated code.
+func (a *analyzer) render(typeName string, order []int) ([]byte, error) {
+	var (
+		b       bytes.Buffer
+		imports = map[string]bool{"context": true}
+	)
+	qualifier := func(p *types.Package) string {
+		if p == a.pkg {
+			return ""
+		}
+		imports[p.Path()] = true
+		return p.Name()
+	}
+
+	var body bytes.Buffer
+	params := make([]string, len(a.objects))
+	for i, o := range a.objects {
+		params[i] = fmt.Sprintf("o%d %s", i, types.TypeString(o.typeOf(), qualifier))
+	}
+
+	fmt.Fprintf(&body, "// %s holds objects linked without reflection.\n", typeName)
+	fmt.Fprintf(&body, "type %s struct {\n", typeName)
+	for _, p := range params {
+		fmt.Fprintln(&body, p)
+	}
+	fmt.Fprintf(&body, "}\n\n")
+
+	ctor := "new" + strings.ToUpper(typeName[:1]) + typeName[1:]
+	fmt.Fprintf(&body, "// %s links objects the way BuildDependencies does.\n", ctor)
+	fmt.Fprintf(&body, "func %s(%s) *%s {\n", ctor, strings.Join(params, ", "), typeName)
+	for _, i := range order {
+		for _, w := range a.objects[i].wires {
+			refs := make([]string, len(w.providers))
+			for k, p := range w.providers {
+				refs[k] = fmt.Sprintf("o%d", p)
+			}
+			if w.slice != nil {
+				fmt.Fprintf(&body, "o%d.%s = %s{%s}\n", i, w.field, types.TypeString(w.slice, qualifier), strings.Join(refs, ", "))
+			} else {
+				fmt.Fprintf(&body, "o%d.%s = %s\n", i, w.field, refs[0])
+			}
+		}
+	}
+	fmt.Fprintf(&body, "return &%s{", typeName)
+	for i := range a.objects {
+		fmt.Fprintf(&body, "o%d: o%d, ", i, i)
+	}
+	fmt.Fprintf(&body, "}\n}\n\n")
+
+	phase := func(method, call string, objects []int, pred func(*object) bool) {
+		fmt.Fprintf(&body, "// %s calls %s of objects in dependency order.\n", method, call)
+		fmt.Fprintf(&body, "func (c *%s) %s(ctx context.Context) error {\n", typeName, method)
+		for _, i := range objects {
+			if pred(a.objects[i]) {
+				fmt.Fprintf(&body, "if err := c.o%d.%s(ctx); err != nil {\nreturn err\n}\n", i, call)
+			}
+		}
+		fmt.Fprintf(&body, "return nil\n}\n\n")
+	}
+	phase("InitRequired", "Init", order, func(o *object) bool { return o.initializer })
+	phase("StartRunners", "Start", order, func(o *object) bool { return o.runner })
+
+	imports["errors"] = true
+	fmt.Fprintf(&body, "// Stop calls Stop of objects in reverse dependency order.\n")
+	fmt.Fprintf(&body, "func (c *%s) Stop(ctx context.Context) error {\nvar errs []error\n", typeName)
+	for k := len(order) - 1; k >= 0; k-- {
+		if i := order[k]; a.objects[i].stopper {
+			fmt.Fprintf(&body, "if err := c.o%d.Stop(ctx); err != nil {\nerrs = append(errs, err)\n}\n", i)
+		}
+	}
+	fmt.Fprintf(&body, "return errors.Join(errs...)\n}\n")
+
+	fmt.Fprintf(&b, "// Code generated by sdigen. DO NOT EDIT.\n\npackage %s\n\nimport (\n", a.pkg.Name())
+	paths := make([]string, 0, len(imports))
+	for p := range imports {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		fmt.Fprintf(&b, "%q\n", p)
+	}
+	fmt.Fprintf(&b, ")\n\n")
+	b.Write(body.Bytes())
+
+	return format.Source(b.Bytes())
+}
+
+// parseTag parses comma separated list of key=value pairs the same way
+// package sdi does.
+func parseTag(tag string) map[string]string {
+	res := make(map[string]string)
+	for _, kv := range strings.Split(tag, ",") {
+		kv = strings.TrimSpace(kv)
+		if kv == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(kv, "=")
+		res[k] = v
+	}
+	return res
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := filepath.Join("testdata", "app")
+	src, notes, err := generate(dir, "sdi_gen.go", "generatedContainer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 1 || !strings.HasSuffix(notes[0], "server.Addr: field tagged env is not set") {
+		t.Errorf("expected note on env field, got %q", notes)
+	}
+	expected, err := os.ReadFile(filepath.Join(dir, "sdi_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(src) != string(expected) {
+		t.Errorf("unexpected generated code:\n%s", src)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, _, err := generate(filepath.Join("testdata", "missing"), "sdi_gen.go", "generatedContainer")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, s := range []string{
+		"reader.Storage: ambiguous dependency Storage: *replica, *replica",
+		"reader.Mailer: no object assignable to Mailer",
+		"reader.Logger: no object assignable to interface{Print(...interface{})}",
+	} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("expected error to contain %q, got %v", s, err)
+		}
+	}
+}
+
+func TestStubs(t *testing.T) {
+	dir := filepath.Join("testdata", "app")
+	src, err := stubs(dir, "sdi_stubs_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := os.ReadFile(filepath.Join(dir, "sdi_stubs_test.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(src) != string(expected) {
+		t.Errorf("unexpected generated code:\n%s", src)
+	}
+}
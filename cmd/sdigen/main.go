@@ -0,0 +1,84 @@
+// Command sdigen generates explicit wiring code for objects registered in
+// a sdi container.
+//
+// sdigen analyzes the package in the directory (current by default) and
+// finds objects added into a container by Add, AddService, AddNamed or
+// AddFunc. For them it generates a type with a constructor assigning
+// dependencies to exported fields the way BuildDependencies does, and
+// methods InitRequired and StartRunners calling Init and Start in
+// dependency order.
+//
+//	//go:generate sdigen -o sdi_gen.go
+//
+// Unlike the runtime container the generated code uses no reflection, and
+// a missing or ambiguous dependency is reported by sdigen instead of
+// surfacing at startup. The runtime container remains available for
+// objects sdigen can't handle.
+//
+// Only objects of named struct types declared in the analyzed package,
+// added as pointers, and functions are supported. Interface and pointer
+// fields, function fields of functions added by AddFunc, fields tagged
+// with `sdi:"qualifier=NAME"` and slices of interfaces are wired. Fields
+// set in the composite literal passed to Add are left as is. Fields
+// tagged with `sdi:"env=NAME"` are not set by the generated code and are
+// reported to standard error.
+//
+// With flag -stubs sdigen generates a test file, sdi_stubs_test.go by
+// default, with stubs of named interfaces of exported fields of the
+// objects. Stubs are registered by sditest.RegisterStub and their methods
+// panic with message "sditest: stub of pkg.Iface.Method called":
+//
+//	//go:generate sdigen -stubs
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	var (
+		out      = flag.String("o", "sdi_gen.go", "output file name, relative to the package directory")
+		typeName = flag.String("type", "generatedContainer", "name of the generated type")
+		stub     = flag.Bool("stubs", false, "generate stubs of interfaces for package sditest")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: sdigen [flags] [dir]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	var (
+		src []byte
+		err error
+	)
+	if *stub {
+		set := false
+		flag.Visit(func(f *flag.Flag) { set = set || f.Name == "o" })
+		if !set {
+			*out = "sdi_stubs_test.go"
+		}
+		src, err = stubs(dir, *out)
+	} else {
+		var notes []string
+		src, notes, err = generate(dir, *out, *typeName)
+		for _, n := range notes {
+			fmt.Fprintln(os.Stderr, n)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(filepath.Join(dir, *out), src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+const sditestPath = sdiPath + "/sditest"
+
+// stubs returns source of a test file with stubs of interfaces exported
+// fields of objects in the package in dir depend on.
+func stubs(dir, out string) ([]byte, error) {
+	a, err := load(dir, out)
+	if err != nil {
+		return nil, err
+	}
+	ifaces := a.interfaces()
+	if len(ifaces) == 0 {
+		return nil, fmt.Errorf("sdigen: no interfaces to stub in %s", dir)
+	}
+	return a.renderStubs(ifaces)
+}
+
+// interfaces returns named interface types of exported fields and
+// elements of exported slice fields of objects, in the order of objects
+// and fields. Types declared in package sdi, generic types, interfaces
+// without methods and interfaces of other packages with unexported
+// methods are omitted.
+func (a *analyzer) interfaces() []*types.Named {
+	var res []*types.Named
+	for _, o := range a.objects {
+		if o.fn != nil {
+			continue
+		}
+		st := o.typ.Underlying().(*types.Struct)
+		for k := 0; k < st.NumFields(); k++ {
+			f := st.Field(k)
+			if !f.Exported() || f.Anonymous() {
+				continue
+			}
+			t := f.Type()
+			if s, ok := t.Underlying().(*types.Slice); ok {
+				t = s.Elem()
+			}
+			n, ok := t.(*types.Named)
+			if !ok || !a.stubbable(n) {
+				continue
+			}
+			known := false
+			for _, r := range res {
+				if types.Identical(r, n) {
+					known = true
+					break
+				}
+			}
+			if !known {
+				res = append(res, n)
+			}
+		}
+	}
+	return res
+}
+
+func (a *analyzer) stubbable(n *types.Named) bool {
+	it, ok := n.Underlying().(*types.Interface)
+	if !ok || it.NumMethods() == 0 || a.isSDIType(n) || n.TypeArgs().Len() > 0 {
+		return false
+	}
+	if n.Obj().Pkg() == a.pkg {
+		return true
+	}
+	for k := 0; k < it.NumMethods(); k++ {
+		if !it.Method(k).Exported() {
+			return false
+		}
+	}
+	return n.Obj().Exported()
+}
+
+// renderStubs returns formatted source of stubs of interfaces ifaces
+// registered by sditest.RegisterStub.
+func (a *analyzer) renderStubs(ifaces []*types.Named) ([]byte, error) {
+	var (
+		b       bytes.Buffer
+		body    bytes.Buffer
+		imports = map[string]bool{sditestPath: true}
+	)
+	qualifier := func(p *types.Package) string {
+		if p == a.pkg {
+			return ""
+		}
+		imports[p.Path()] = true
+		return p.Name()
+	}
+
+	for _, n := range ifaces {
+		name := stubName(a.pkg, n)
+		iface := types.TypeString(n, qualifier)
+		full := types.TypeString(n, (*types.Package).Name)
+		fmt.Fprintf(&body, "// %s is a stub of %s panicking when called.\n", name, iface)
+		fmt.Fprintf(&body, "type %s struct{}\n\n", name)
+
+		it := n.Underlying().(*types.Interface)
+		for k := 0; k < it.NumMethods(); k++ {
+			m := it.Method(k)
+			sig := m.Type().(*types.Signature)
+			fmt.Fprintf(&body, "func (%s) %s%s {\n", name, m.Name(), signature(sig, qualifier))
+			fmt.Fprintf(&body, "panic(%q)\n}\n\n", "sditest: stub of "+full+"."+m.Name()+" called")
+		}
+	}
+
+	fmt.Fprintf(&body, "func init() {\n")
+	for _, n := range ifaces {
+		fmt.Fprintf(&body, "sditest.RegisterStub[%s](%s{})\n", types.TypeString(n, qualifier), stubName(a.pkg, n))
+	}
+	fmt.Fprintf(&body, "}\n")
+
+	fmt.Fprintf(&b, "// Code generated by sdigen -stubs. DO NOT EDIT.\n\npackage %s\n\nimport (\n", a.pkg.Name())
+	paths := make([]string, 0, len(imports))
+	for p := range imports {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		fmt.Fprintf(&b, "%q\n", p)
+	}
+	fmt.Fprintf(&b, ")\n\n")
+	b.Write(body.Bytes())
+
+	return format.Source(b.Bytes())
+}
+
+// stubName returns name of the stub type of interface n, prefixed with
+// the package name of interfaces declared in other packages.
+func stubName(pkg *types.Package, n *types.Named) string {
+	name := n.Obj().Name()
+	if p := n.Obj().Pkg(); p != nil && p != pkg {
+		name = strings.ToUpper(p.Name()[:1]) + p.Name()[1:] + name
+	}
+	return "stub" + strings.ToUpper(name[:1]) + name[1:]
+}
+
+// signature returns parameters and results of method signature sig
+// without names.
+func signature(sig *types.Signature, qualifier types.Qualifier) string {
+	tuple := func(t *types.Tuple, variadic bool) []string {
+		res := make([]string, t.Len())
+		for k := range res {
+			typ := t.At(k).Type()
+			if variadic && k == t.Len()-1 {
+				res[k] = "..." + types.TypeString(typ.(*types.Slice).Elem(), qualifier)
+				continue
+			}
+			res[k] = types.TypeString(typ, qualifier)
+		}
+		return res
+	}
+
+	s := "(" + strings.Join(tuple(sig.Params(), sig.Variadic()), ", ") + ")"
+	switch res := tuple(sig.Results(), false); len(res) {
+	case 0:
+	case 1:
+		s += " " + res[0]
+	default:
+		s += " (" + strings.Join(res, ", ") + ")"
+	}
+	return s
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+type Storage interface {
+	Get(key string) string
+}
+
+type Handler interface {
+	Handle(ctx context.Context) error
+}
+
+type db struct {
+	DSN string
+}
+
+func (d *db) Init(ctx context.Context) error { return nil }
+func (d *db) Stop(ctx context.Context) error { return nil }
+func (d *db) Get(key string) string          { return key }
+
+type cache struct {
+	Origin Storage `sdi:"qualifier=primary"`
+}
+
+func (c *cache) Init(ctx context.Context) error { return nil }
+func (c *cache) Get(key string) string          { return key }
+
+type ping struct{}
+
+func (p *ping) Global()                          {}
+func (p *ping) Handle(ctx context.Context) error { return nil }
+
+type server struct {
+	Cache    *cache
+	Handlers []Handler
+	Now      func() time.Time
+	Addr     string `sdi:"env=ADDR,default=:8080"`
+}
+
+func (s *server) Init(ctx context.Context) error  { return nil }
+func (s *server) Start(ctx context.Context) error { return nil }
+
+func register(c *sdi.SimpleContainer) {
+	c.Add(&server{}, &cache{})
+	c.AddNamed("primary", &db{DSN: "postgres://"})
+	c.Add(&ping{})
+	c.AddFunc(time.Now)
+}
@@ -0,0 +1,58 @@
+// Code generated by sdigen. DO NOT EDIT.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// generatedContainer holds objects linked without reflection.
+type generatedContainer struct {
+	o0 *server
+	o1 *cache
+	o2 *db
+	o3 *ping
+	o4 func() time.Time
+}
+
+// newGeneratedContainer links objects the way BuildDependencies does.
+func newGeneratedContainer(o0 *server, o1 *cache, o2 *db, o3 *ping, o4 func() time.Time) *generatedContainer {
+	o1.Origin = o2
+	o0.Cache = o1
+	o0.Handlers = []Handler{o3}
+	o0.Now = o4
+	return &generatedContainer{o0: o0, o1: o1, o2: o2, o3: o3, o4: o4}
+}
+
+// InitRequired calls Init of objects in dependency order.
+func (c *generatedContainer) InitRequired(ctx context.Context) error {
+	if err := c.o2.Init(ctx); err != nil {
+		return err
+	}
+	if err := c.o1.Init(ctx); err != nil {
+		return err
+	}
+	if err := c.o0.Init(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// StartRunners calls Start of objects in dependency order.
+func (c *generatedContainer) StartRunners(ctx context.Context) error {
+	if err := c.o0.Start(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Stop calls Stop of objects in reverse dependency order.
+func (c *generatedContainer) Stop(ctx context.Context) error {
+	var errs []error
+	if err := c.o2.Stop(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
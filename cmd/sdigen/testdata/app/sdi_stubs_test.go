@@ -0,0 +1,27 @@
+// Code generated by sdigen -stubs. DO NOT EDIT.
+
+package app
+
+import (
+	"context"
+	"github.com/axkit/sdi/sditest"
+)
+
+// stubHandler is a stub of Handler panicking when called.
+type stubHandler struct{}
+
+func (stubHandler) Handle(context.Context) error {
+	panic("sditest: stub of app.Handler.Handle called")
+}
+
+// stubStorage is a stub of Storage panicking when called.
+type stubStorage struct{}
+
+func (stubStorage) Get(string) string {
+	panic("sditest: stub of app.Storage.Get called")
+}
+
+func init() {
+	sditest.RegisterStub[Handler](stubHandler{})
+	sditest.RegisterStub[Storage](stubStorage{})
+}
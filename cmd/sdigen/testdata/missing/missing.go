@@ -0,0 +1,38 @@
+package missing
+
+import (
+	"context"
+
+	"github.com/axkit/sdi"
+)
+
+type Storage interface {
+	Get(key string) string
+}
+
+type Mailer interface {
+	Send(to string) error
+}
+
+type mailer struct{}
+
+func (m *mailer) Global()              {}
+func (m *mailer) Send(to string) error { return nil }
+
+type replica struct{}
+
+func (r *replica) Global()               {}
+func (r *replica) Get(key string) string { return key }
+
+type reader struct {
+	Storage Storage
+	Mailer  Mailer
+	Logger  interface{ Print(...interface{}) }
+}
+
+func (r *reader) Init(ctx context.Context) error { return nil }
+
+func register(c *sdi.SimpleContainer) {
+	c.Add(&reader{}, &replica{}, &replica{})
+	c.Add(&mailer{}, sdi.NotInjectable())
+}
@@ -0,0 +1,64 @@
+package sdi
+
+import (
+	"context"
+	"reflect"
+)
+
+type objectKey struct{}
+
+// objectValue describes the containered object whose Init or Start
+// received the context.
+type objectValue struct {
+	name string
+	typ  reflect.Type
+}
+
+// objectContext returns ctx carrying name and type of the object at
+// position i.
+func (c *SimpleContainer) objectContext(ctx context.Context, i int) context.Context {
+	return context.WithValue(ctx, objectKey{}, objectValue{
+		name: c.nameOf(i),
+		typ:  reflect.TypeOf(c.objects[i]),
+	})
+}
+
+// ObjectName returns the name of the containered object whose Init or Start
+// received ctx: the name used in AddNamed or the object type.
+func ObjectName(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(objectKey{}).(objectValue)
+	return v.name, ok
+}
+
+// ObjectType returns the type of the containered object whose Init or Start
+// received ctx.
+func ObjectType(ctx context.Context) (reflect.Type, bool) {
+	v, ok := ctx.Value(objectKey{}).(objectValue)
+	return v.typ, ok
+}
+
+// Cancel cancels the context passed to Start of the containered object o.
+// A supervised runner is not restarted after cancellation. Cancel returns
+// false if o is not containered or not started.
+func (c *SimpleContainer) Cancel(o interface{}) bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	i := c.indexOf(o)
+	if i < 0 || i >= len(c.states) || c.states[i].cancel == nil {
+		return false
+	}
+	c.states[i].canceled = true
+	c.states[i].cancel()
+	return true
+}
+
+// release cancels the context passed to Start of the object at position i.
+func (c *SimpleContainer) release(i int) {
+	c.setState(i, func(s *objectState) {
+		if s.cancel != nil {
+			s.cancel()
+			s.cancel = nil
+		}
+	})
+}
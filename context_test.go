@@ -0,0 +1,102 @@
+package sdi_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+type nameRecorder struct {
+	initName  string
+	startName string
+}
+
+func (nr *nameRecorder) Init(ctx context.Context) error {
+	nr.initName, _ = sdi.ObjectName(ctx)
+	return nil
+}
+
+func (nr *nameRecorder) Start(ctx context.Context) error {
+	nr.startName, _ = sdi.ObjectName(ctx)
+	if _, ok := sdi.ObjectType(ctx); !ok {
+		nr.startName = ""
+	}
+	return nil
+}
+
+func TestObjectContext(t *testing.T) {
+	cs := sdi.New()
+	named, unnamed := nameRecorder{}, nameRecorder{}
+	cs.AddNamed("recorder", &named)
+	cs.Add(&unnamed)
+	cs.BuildDependencies()
+	ctx := context.Background()
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if named.initName != "recorder" || named.startName != "recorder" {
+		t.Errorf("unexpected names %q, %q", named.initName, named.startName)
+	}
+	if unnamed.startName != "*sdi_test.nameRecorder" {
+		t.Errorf("unexpected name %q", unnamed.startName)
+	}
+	if _, ok := sdi.ObjectName(ctx); ok {
+		t.Error("expected no name in the parent context")
+	}
+}
+
+// blocking runs until its context is cancelled and returns the context
+// error, so a supervisor would restart it.
+type blocking struct {
+	starts  int32
+	running int32
+}
+
+func (b *blocking) Start(ctx context.Context) error {
+	atomic.AddInt32(&b.starts, 1)
+	atomic.StoreInt32(&b.running, 1)
+	defer atomic.StoreInt32(&b.running, 0)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (b *blocking) isRunning() bool {
+	return atomic.LoadInt32(&b.running) == 1
+}
+
+func TestCancel(t *testing.T) {
+	cs := sdi.New(sdi.WithSupervision(sdi.RestartPolicy{MaxRestarts: -1, InitialBackoff: time.Millisecond}))
+	a, b := blocking{}, blocking{}
+	cs.Add(&a, &b)
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+	if cs.Cancel(&a) {
+		t.Error("expected false for not started object")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return a.isRunning() && b.isRunning() })
+
+	if !cs.Cancel(&a) {
+		t.Fatal("expected runner to be cancelled")
+	}
+	waitFor(t, func() bool { return !a.isRunning() })
+	time.Sleep(10 * time.Millisecond)
+	if atomic.LoadInt32(&a.starts) != 1 || !b.isRunning() {
+		t.Error("expected only cancelled runner to exit without restart")
+	}
+
+	cancel()
+	cs.Wait(context.Background())
+}
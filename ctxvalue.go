@@ -0,0 +1,71 @@
+package sdi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ContextKey is the type of context keys of values copied into fields
+// tagged with `sdi:"ctx=NAME"` unless NAME is mapped to another key by
+// WithContextKey.
+//
+//	ctx = context.WithValue(ctx, sdi.ContextKey("tenant"), "acme")
+//	err := c.InitRequired(ctx)
+type ContextKey string
+
+// WithContextKey maps name used in tag option ctx to the context key,
+// e.g. the key of a package storing values in context.
+func WithContextKey(name string, key interface{}) Option {
+	return func(o *options) {
+		if o.contextKeys == nil {
+			o.contextKeys = make(map[string]interface{})
+		}
+		o.contextKeys[name] = key
+	}
+}
+
+// ctxField is an exported field tagged with `sdi:"ctx=NAME"`.
+type ctxField struct {
+	consumer int
+	field    string
+	target   reflect.Value
+	key      interface{}
+	required bool
+}
+
+// addContextField records the field fs of the object at position pos
+// getting context value by name. The caller must hold write lock.
+func (c *SimpleContainer) addContextField(pos int, fs reflect.Value, field, name string, opts map[string]string) {
+	var key interface{} = ContextKey(name)
+	if k, ok := c.opts.contextKeys[name]; ok {
+		key = k
+	}
+	_, required := opts["required"]
+	c.ctxFields = append(c.ctxFields, ctxField{consumer: pos, field: field, target: fs, key: key, required: required})
+}
+
+// setContextValues copies values carried by ctx into fields tagged with
+// `sdi:"ctx=NAME"` described by fields. A missing value leaves the field
+// intact unless it's tagged with option required.
+func (c *SimpleContainer) setContextValues(ctx context.Context, fields []ctxField) error {
+	var errs []error
+	for _, f := range fields {
+		v := ctx.Value(f.key)
+		if v == nil {
+			if f.required {
+				errs = append(errs, fmt.Errorf("%w: %s.%s: context has no value %v", ErrUnresolvedDependency, c.nameOf(f.consumer), f.field, f.key))
+			}
+			continue
+		}
+		rv := reflect.ValueOf(v)
+		if !rv.Type().AssignableTo(f.target.Type()) {
+			errs = append(errs, fmt.Errorf("sdi: %s.%s: context value %v of type %T is not assignable to %s",
+				c.nameOf(f.consumer), f.field, f.key, v, f.target.Type()))
+			continue
+		}
+		f.target.Set(rv)
+	}
+	return errors.Join(errs...)
+}
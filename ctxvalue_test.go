@@ -0,0 +1,63 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type tenantKey struct{}
+
+type tenantAware struct {
+	Env    string `sdi:"ctx=env"`
+	Tenant string `sdi:"ctx=tenant,required"`
+	Client Client `sdi:"ctx=client"`
+	inited string
+}
+
+func (ta *tenantAware) Init(ctx context.Context) error {
+	ta.inited = ta.Env + "/" + ta.Tenant
+	return nil
+}
+
+func TestContextValues(t *testing.T) {
+	cl := &client{name: "tracing"}
+	cs := sdi.New(sdi.WithContextKey("tenant", tenantKey{}))
+	ta := tenantAware{}
+	cs.Add(&ta, &client{name: "containered"})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.WithValue(context.Background(), sdi.ContextKey("env"), "staging")
+	ctx = context.WithValue(ctx, tenantKey{}, "acme")
+	ctx = context.WithValue(ctx, sdi.ContextKey("client"), Client(cl))
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ta.inited != "staging/acme" || ta.Client != cl {
+		t.Errorf("expected context values to be set before Init, got %+v", ta)
+	}
+}
+
+func TestContextValuesRequired(t *testing.T) {
+	cs := sdi.New()
+	ta := tenantAware{}
+	cs.Add(&ta)
+	cs.BuildDependencies()
+
+	ctx := context.WithValue(context.Background(), sdi.ContextKey("env"), 42)
+	err := cs.InitRequired(ctx)
+	if !errors.Is(err, sdi.ErrUnresolvedDependency) || !strings.Contains(err.Error(), "tenantAware.Tenant") {
+		t.Errorf("expected missing tenant, got %v", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "value env of type int is not assignable to string") {
+		t.Errorf("expected type mismatch of env, got %v", err)
+	}
+	if ta.inited != "" || cs.State() != sdi.StateBuilt {
+		t.Error("expected no Init")
+	}
+}
@@ -0,0 +1,96 @@
+package sdi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// decoration identifies the object at position pos decorated for
+// dependencies of type typ.
+type decoration struct {
+	typ reflect.Type
+	pos int
+}
+
+// Decorate registers decorator fn of dependencies of type I. Containered
+// objects injected into fields, slices, maps and setters of type I and
+// returned by Resolve are wrapped by fn, e.g. to add logging, metrics or
+// retries:
+//
+//	sdi.Decorate(c, func(next Storage) Storage {
+//		return &instrumentedStorage{next: next}
+//	})
+//
+// Several decorators of the same type are applied in the order they've
+// been registered, the first one wraps the object itself. Each object is
+// wrapped once, all consumers get the same wrapper. Decorators must be
+// registered before BuildDependencies.
+func Decorate[I any](c *SimpleContainer, fn func(next I) I) {
+	c.Decorate((*I)(nil), fn)
+}
+
+// Decorate is the non-generic form of function Decorate. Parameter iface
+// is a pointer to the decorated type, e.g. (*Storage)(nil), fn is
+// a function taking and returning the type. It panics if fn has wrong
+// signature or BuildDependencies has been called already.
+func (c *SimpleContainer) Decorate(iface interface{}, fn interface{}) {
+	pt := reflect.TypeOf(iface)
+	if pt == nil || pt.Kind() != reflect.Ptr {
+		panic("sdi: iface must be a pointer to a type")
+	}
+	t := pt.Elem()
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func || fv.IsNil() || fv.Type().NumIn() != 1 || fv.Type().NumOut() != 1 ||
+		fv.Type().In(0) != t || fv.Type().Out(0) != t {
+		panic(fmt.Sprintf("sdi: decorator %T is not func(%s) %s", fn, t, t))
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.built {
+		panic(fmt.Sprintf("sdi: decorator of %s registered after BuildDependencies", t))
+	}
+	if c.decorators == nil {
+		c.decorators = make(map[reflect.Type][]reflect.Value)
+	}
+	c.decorators[t] = append(c.decorators[t], fv)
+}
+
+// provide returns the object at position i to be injected into dependency
+// of type t: the object itself or the object wrapped by decorators of t.
+func (c *SimpleContainer) provide(t reflect.Type, i int) reflect.Value {
+	ds := c.decorators[t]
+	if len(ds) == 0 {
+		return reflect.ValueOf(c.objects[i])
+	}
+
+	c.decoMux.Lock()
+	defer c.decoMux.Unlock()
+
+	key := decoration{typ: t, pos: i}
+	if v, ok := c.decorated[key]; ok {
+		return v
+	}
+	v := reflect.ValueOf(c.objects[i]).Convert(t)
+	for _, d := range ds {
+		v = d.Call([]reflect.Value{v})[0]
+	}
+	if c.decorated == nil {
+		c.decorated = make(map[decoration]reflect.Value)
+	}
+	c.decorated[key] = v
+	return v
+}
+
+// undecorate forgets wrappers of the object at position i.
+func (c *SimpleContainer) undecorate(i int) {
+	c.decoMux.Lock()
+	defer c.decoMux.Unlock()
+
+	for key := range c.decorated {
+		if key.pos == i {
+			delete(c.decorated, key)
+		}
+	}
+}
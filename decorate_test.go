@@ -0,0 +1,81 @@
+package sdi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type countingLookuper struct {
+	next  Lookuper
+	calls int
+}
+
+func (cl *countingLookuper) Lookup(k string) string {
+	cl.calls++
+	return cl.next.Lookup(k)
+}
+
+type prefixLookuper struct {
+	next Lookuper
+}
+
+func (pl prefixLookuper) Lookup(k string) string {
+	return "> " + pl.next.Lookup(k)
+}
+
+type staticLookuper struct {
+	value string
+}
+
+func (sl *staticLookuper) Global() {}
+
+func (sl *staticLookuper) Lookup(k string) string {
+	return sl.value
+}
+
+type glossary struct {
+	Dicts []Lookuper
+}
+
+func (g *glossary) Global() {}
+
+func TestDecorate(t *testing.T) {
+	var counting *countingLookuper
+	tr := translator{}
+	g := glossary{}
+	cs := sdi.New()
+	hello := staticLookuper{value: "hello"}
+	cs.Add(&hello, &tr, &g)
+	sdi.Decorate(cs, func(next Lookuper) Lookuper {
+		counting = &countingLookuper{next: next}
+		return counting
+	})
+	sdi.Decorate(cs, func(next Lookuper) Lookuper {
+		return prefixLookuper{next: next}
+	})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	if s := tr.Dict.Lookup("greeting"); s != "> hello" {
+		t.Errorf("unexpected lookup result %q", s)
+	}
+	if len(g.Dicts) != 1 || g.Dicts[0] != tr.Dict {
+		t.Error("expected consumers to get the same wrapper")
+	}
+	if l := sdi.MustResolve[Lookuper](cs); l != tr.Dict {
+		t.Error("expected Resolve to return the wrapper")
+	}
+	if counting.calls != 1 {
+		t.Errorf("expected decorators to be applied in order, got %d calls", counting.calls)
+	}
+
+	if err := cs.Swap(context.Background(), &hello, &staticLookuper{value: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	if s := tr.Dict.Lookup("greeting"); s != "> hi" || g.Dicts[0] != tr.Dict {
+		t.Errorf("expected swapped object to be decorated, got %q", s)
+	}
+}
@@ -0,0 +1,156 @@
+package sdi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DependsOner is the interface that wraps the basic DependsOn method.
+//
+// DependsOn returns objects which must be initialized and started before
+// the object, even if they are not injected into it. An element of
+// the returned slice is either a containered object or reflect.Type;
+// in the latter case the object depends on all containered objects
+// assignable to the type.
+type DependsOner interface {
+	DependsOn() []interface{}
+}
+
+// buildOrder collects dependencies declared by DependsOn and computes
+// sequence of Init and Start calls. The caller must hold write lock.
+func (c *SimpleContainer) buildOrder() error {
+	for i := range c.objects {
+		do, ok := c.objects[i].(DependsOner)
+		if !ok {
+			continue
+		}
+		for _, d := range do.DependsOn() {
+			if t, ok := d.(reflect.Type); ok {
+				for _, k := range c.assignableTo(t) {
+					if k != i {
+						c.deps = append(c.deps, dependency{consumer: i, provider: k, explicit: true})
+					}
+				}
+				continue
+			}
+
+			k := c.indexOf(c.copyOf(d))
+			if k < 0 {
+				return fmt.Errorf("sdi: %s depends on not containered %T", c.nameOf(i), d)
+			}
+			c.deps = append(c.deps, dependency{consumer: i, provider: k, explicit: true})
+		}
+	}
+
+	if err := c.checkPhases(); err != nil {
+		return err
+	}
+
+	explicit := func(d dependency) bool { return d.explicit }
+	order, err := c.explicitOrder(c.byPhase(), explicit)
+	if err != nil {
+		return err
+	}
+	c.order = order
+	if c.opts.readinessGate {
+		// runners wait for injected Readier objects, see WithReadinessGate.
+		explicit = func(d dependency) bool {
+			_, ok := c.objects[d.provider].(Readier)
+			return d.explicit || ok
+		}
+	}
+	if c.startOrder, err = c.explicitOrder(c.byPriority(order), explicit); err != nil {
+		return err
+	}
+	c.stopOrder = c.reverseOrder(c.startOrder)
+	return nil
+}
+
+// explicitOrder sorts positions of objects topologically by dependencies
+// declared by DependsOn, or other dependencies follow reports true for.
+// Objects keep the order of seq, unless they have to be moved after their
+// dependencies.
+func (c *SimpleContainer) explicitOrder(seq []int, follow func(dependency) bool) ([]int, error) {
+	providers := make([][]int, len(c.objects))
+	for _, d := range c.deps {
+		if follow(d) && d.provider != d.consumer {
+			providers[d.consumer] = append(providers[d.consumer], d.provider)
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(c.objects))
+	order := make([]int, 0, len(c.objects))
+
+	var visit func(int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: %s", ErrCycle, c.nameOf(i))
+		}
+		state[i] = visiting
+		for _, p := range providers[i] {
+			if err := visit(p); err != nil {
+				return err
+			}
+		}
+		state[i] = visited
+		order = append(order, i)
+		return nil
+	}
+
+	for _, i := range seq {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// reverseOrder returns positions of objects in the reverse order of seq
+// moved so that each object precedes objects it depends on, either by
+// injection or by DependsOn. Dependencies closing a cycle are ignored.
+func (c *SimpleContainer) reverseOrder(seq []int) []int {
+	consumers := make([][]int, len(c.objects))
+	for _, d := range c.deps {
+		consumers[d.provider] = append(consumers[d.provider], d.consumer)
+	}
+
+	visited := make([]bool, len(c.objects))
+	order := make([]int, 0, len(c.objects))
+
+	var visit func(int)
+	visit = func(i int) {
+		if visited[i] {
+			return
+		}
+		visited[i] = true
+		for _, k := range consumers[i] {
+			visit(k)
+		}
+		order = append(order, i)
+	}
+
+	for k := len(seq) - 1; k >= 0; k-- {
+		visit(seq[k])
+	}
+	return order
+}
+
+// sequence returns positions of objects in the order of Init calls.
+func (c *SimpleContainer) sequence() []int {
+	if c.order != nil {
+		return c.order
+	}
+	seq := make([]int, len(c.objects))
+	for i := range seq {
+		seq[i] = i
+	}
+	return seq
+}
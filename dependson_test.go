@@ -0,0 +1,95 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type dependent struct {
+	journaled
+	deps []interface{}
+}
+
+func (d *dependent) DependsOn() []interface{} {
+	return d.deps
+}
+
+func TestDependsOn(t *testing.T) {
+	var jn journal
+	migrator := &journaled{name: "migrator", journal: &jn}
+	api := &dependent{journaled: journaled{name: "api", journal: &jn}, deps: []interface{}{migrator}}
+	cache := &journaled{name: "cache", journal: &jn}
+
+	cs := sdi.New()
+	cs.Add(api, cache, migrator)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.StartRunners(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"init migrator", "init api", "init cache",
+		"start migrator", "start api", "start cache",
+		"stop cache", "stop api", "stop migrator",
+	}
+	if !reflect.DeepEqual(jn.calls, expected) {
+		t.Errorf("expected %v, got %v", expected, jn.calls)
+	}
+}
+
+func TestDependsOnType(t *testing.T) {
+	var jn journal
+	api := &dependent{journaled: journaled{name: "api", journal: &jn},
+		deps: []interface{}{reflect.TypeOf((*CI)(nil)).Elem()}}
+
+	cs := sdi.New()
+	cs.Add(api, &C{})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if objs := cs.Objects(); objs[0].Name != "*sdi_test.dependent" || len(objs[0].Dependencies) != 0 {
+		t.Errorf("declared dependencies must not be reported as injections: %+v", objs[0])
+	}
+}
+
+func TestDependsOnErrors(t *testing.T) {
+	a := &dependent{}
+	b := &dependent{deps: []interface{}{a}}
+	a.deps = []interface{}{b}
+
+	cs := sdi.New()
+	cs.Add(a, b)
+	if err := cs.Build(); !errors.Is(err, sdi.ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+
+	cs = sdi.New()
+	cs.Add(a, b)
+	func() {
+		defer func() {
+			if err, _ := recover().(error); !errors.Is(err, sdi.ErrCycle) {
+				t.Errorf("expected BuildDependencies to panic with ErrCycle, got %v", err)
+			}
+		}()
+		var c sdi.Container = cs
+		c.BuildDependencies()
+	}()
+
+	cs = sdi.New()
+	cs.Add(&dependent{deps: []interface{}{&C{}}})
+	if err := cs.Build(); err == nil {
+		t.Error("expected error for not containered dependency")
+	}
+}
@@ -0,0 +1,60 @@
+package sdi
+
+import (
+	"reflect"
+	"sort"
+)
+
+// WithDeterministicOrder makes BuildDependencies sort containered objects
+// by name, see Namer, then by type before wiring, instead of keeping
+// the order they've been added in. The order is used to choose candidates
+// by PreferFirst and PreferLast resolution policies, to fill slices of
+// interfaces and to break ties of Init, Start and Stop order, so the
+// wiring doesn't change when unrelated Add calls are reordered. Objects
+// with the same name and type keep the order they've been added in,
+// objects constructed by Provide follow the sorted ones in the order of
+// construction.
+func WithDeterministicOrder() Option {
+	return func(o *options) {
+		o.deterministicOrder = true
+	}
+}
+
+// sortObjects sorts containered objects by name and type if the container
+// is created with WithDeterministicOrder. The caller must hold write lock.
+func (c *SimpleContainer) sortObjects() {
+	if !c.opts.deterministicOrder {
+		return
+	}
+
+	type key struct {
+		name, typ string
+	}
+	keys := make([]key, len(c.objects))
+	idx := make([]int, len(c.objects))
+	for i, o := range c.objects {
+		keys[i] = key{name: c.nameOf(i), typ: reflect.TypeOf(o).String()}
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		ka, kb := keys[idx[a]], keys[idx[b]]
+		if ka.name != kb.name {
+			return ka.name < kb.name
+		}
+		return ka.typ < kb.typ
+	})
+
+	objects := make([]interface{}, len(idx))
+	regs := make([]registration, len(idx))
+	for k, i := range idx {
+		objects[k], regs[k] = c.objects[i], c.regs[i]
+	}
+	c.objects, c.regs = objects, regs
+	if len(c.states) == len(idx) {
+		states := make([]objectState, len(idx))
+		for k, i := range idx {
+			states[k] = c.states[i]
+		}
+		c.states = states
+	}
+}
@@ -0,0 +1,47 @@
+package sdi_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestDeterministicOrder(t *testing.T) {
+	build := func(reversed bool) (*B, *A, []string) {
+		var jn journal
+		primary, replica := &A{}, &A{}
+		b := &B{}
+		add := []func(cs *sdi.SimpleContainer){
+			func(cs *sdi.SimpleContainer) { cs.AddNamed("replica", replica) },
+			func(cs *sdi.SimpleContainer) { cs.AddNamed("primary", primary) },
+			func(cs *sdi.SimpleContainer) { cs.AddNamed("zeta", &journaled{name: "zeta", journal: &jn}) },
+			func(cs *sdi.SimpleContainer) { cs.AddNamed("alpha", &journaled{name: "alpha", journal: &jn}) },
+			func(cs *sdi.SimpleContainer) { cs.Add(b, &C{}) },
+		}
+		cs := sdi.New(sdi.WithDeterministicOrder(), sdi.WithResolutionPolicy(sdi.PreferFirst))
+		for k := range add {
+			if reversed {
+				k = len(add) - 1 - k
+			}
+			add[k](cs)
+		}
+		if err := cs.Build(); err != nil {
+			t.Fatal(err)
+		}
+		if err := cs.InitRequired(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		return b, primary, jn.calls
+	}
+
+	b1, primary1, calls1 := build(false)
+	b2, primary2, calls2 := build(true)
+	if b1.AService != primary1 || b2.AService != primary2 {
+		t.Error("expected object sorted first to be injected regardless of Add order")
+	}
+	if !reflect.DeepEqual(calls1, calls2) || !reflect.DeepEqual(calls1, []string{"init alpha", "init zeta"}) {
+		t.Errorf("expected the same Init order, got %v and %v", calls1, calls2)
+	}
+}
@@ -0,0 +1,114 @@
+package sdi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// unresolvedError returns error wrapping ErrUnresolvedDependency describing
+// the missing dependency m: the field, its owner and type, and near-miss
+// candidates with the reason each of them does not match.
+func (c *SimpleContainer) unresolvedError(m dependency) error {
+	if m.typ == nil {
+		return fmt.Errorf("%w: %s.%s", ErrUnresolvedDependency, c.nameOf(m.consumer), m.field)
+	}
+	msg := fmt.Sprintf("%s.%s: field of %T of type %s", c.nameOf(m.consumer), m.field, c.objects[m.consumer], m.typ)
+	if misses := c.nearMisses(m.consumer, m.typ); len(misses) > 0 {
+		msg += "; near misses: " + strings.Join(misses, ", ")
+	}
+	return fmt.Errorf("%w: %s", ErrUnresolvedDependency, msg)
+}
+
+// nearMisses describes containered objects, except the object at position
+// pos, which are close to type t but not assignable to it: objects having
+// some methods of interface t, or objects of other types with the same
+// name as t.
+func (c *SimpleContainer) nearMisses(pos int, t reflect.Type) []string {
+	var res []string
+	for i, o := range c.objects {
+		if i == pos {
+			continue
+		}
+		ot := reflect.TypeOf(o)
+		var reason string
+		if c.regs[i].notInjectable && ot.AssignableTo(t) {
+			reason = "not injectable"
+		} else if t.Kind() == reflect.Interface {
+			reason = mismatch(ot, t)
+		} else if n := baseName(t); n != "" && baseName(ot) == n && ot != t {
+			reason = fmt.Sprintf("type is %s", ot)
+		}
+		if reason != "" {
+			res = append(res, fmt.Sprintf("%s (%s)", c.nameOf(i), reason))
+		}
+	}
+	return res
+}
+
+// mismatch returns why type ot does not implement interface it, or empty
+// string if ot implements it or has none of its methods.
+func mismatch(ot, it reflect.Type) string {
+	if ot.Implements(it) {
+		return ""
+	}
+	var (
+		near    bool
+		reasons []string
+	)
+	for k := 0; k < it.NumMethod(); k++ {
+		m := it.Method(k)
+		om, ok := ot.MethodByName(m.Name)
+		switch {
+		case ok && sameSignature(om.Type, m.Type):
+			near = true
+		case ok:
+			near = true
+			reasons = append(reasons, fmt.Sprintf("method %s has type %s, want %s", m.Name, withoutReceiver(om.Type), m.Type))
+		case ot.Kind() != reflect.Ptr && hasMethod(reflect.PtrTo(ot), m.Name):
+			near = true
+			reasons = append(reasons, fmt.Sprintf("method %s has pointer receiver", m.Name))
+		case !m.IsExported():
+			reasons = append(reasons, fmt.Sprintf("unexported method %s of another package", m.Name))
+		default:
+			reasons = append(reasons, fmt.Sprintf("missing method %s", m.Name))
+		}
+	}
+	if !near {
+		return ""
+	}
+	return strings.Join(reasons, "; ")
+}
+
+func hasMethod(t reflect.Type, name string) bool {
+	_, ok := t.MethodByName(name)
+	return ok
+}
+
+// sameSignature reports whether method type mt, with receiver as the first
+// parameter, has signature of interface method type it.
+func sameSignature(mt, it reflect.Type) bool {
+	return withoutReceiver(mt) == it
+}
+
+// withoutReceiver returns type of method type mt without receiver.
+func withoutReceiver(mt reflect.Type) reflect.Type {
+	in := make([]reflect.Type, mt.NumIn()-1)
+	for k := range in {
+		in[k] = mt.In(k + 1)
+	}
+	out := make([]reflect.Type, mt.NumOut())
+	for k := range out {
+		out[k] = mt.Out(k)
+	}
+	return reflect.FuncOf(in, out, mt.IsVariadic())
+}
+
+// baseName returns name of type t, or of the type t points to, without
+// package.
+func baseName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
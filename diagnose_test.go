@@ -0,0 +1,68 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type Store interface {
+	Find(id int) string
+	Delete(id int)
+}
+
+type storeConsumer struct {
+	Store Store `sdi:"unresolved=strict"`
+}
+
+func (s *storeConsumer) Init(ctx context.Context) error { return nil }
+
+// valueStore implements Store by pointer only.
+type valueStore struct{}
+
+func (valueStore) Init(ctx context.Context) error { return nil }
+func (*valueStore) Find(id int) string            { return "" }
+func (valueStore) Delete(id int)                  {}
+
+// partialStore has no Delete.
+type partialStore struct{}
+
+func (*partialStore) Init(ctx context.Context) error { return nil }
+func (*partialStore) Find(id int) string             { return "" }
+
+// stringStore has Find of another signature.
+type stringStore struct{}
+
+func (*stringStore) Init(ctx context.Context) error { return nil }
+func (*stringStore) Find(id string) string          { return "" }
+func (*stringStore) Delete(id int)                  {}
+
+func TestUnresolvedDiagnostics(t *testing.T) {
+	cs := sdi.New()
+	cs.AddNamed("api", &storeConsumer{})
+	cs.AddNamed("value", valueStore{})
+	cs.AddNamed("partial", &partialStore{})
+	cs.AddNamed("string", &stringStore{})
+	cs.Add(&A{})
+
+	err := cs.Build()
+	if !errors.Is(err, sdi.ErrUnresolvedDependency) {
+		t.Fatalf("expected %v, got %v", sdi.ErrUnresolvedDependency, err)
+	}
+	for _, s := range []string{
+		"api.Store: field of *sdi_test.storeConsumer of type sdi_test.Store",
+		"value (method Find has pointer receiver)",
+		"partial (missing method Delete)",
+		"string (method Find has type func(string) string, want func(int) string)",
+	} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("expected %q in %q", s, err)
+		}
+	}
+	if strings.Contains(err.Error(), "*sdi_test.A") {
+		t.Errorf("expected unrelated object not reported, got %q", err)
+	}
+}
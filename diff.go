@@ -0,0 +1,149 @@
+package sdi
+
+import (
+	"sort"
+	"strings"
+)
+
+// DiffReport describes how wiring of a container differs from another,
+// see Diff.
+type DiffReport struct {
+	// Added and Removed list names of objects present in only one of
+	// the containers. Objects are matched by name, see ObjectInfo.
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+
+	// Changed lists fields injected with other objects.
+	Changed []InjectionChange `json:"changed,omitempty"`
+}
+
+// InjectionChange describes a field of object Consumer injected with
+// objects Before in one container and with objects After in another.
+// Field is empty for dependencies declared by DependsOn.
+type InjectionChange struct {
+	Consumer string   `json:"consumer"`
+	Field    string   `json:"field,omitempty"`
+	Before   []string `json:"before,omitempty"`
+	After    []string `json:"after,omitempty"`
+}
+
+// Diff compares objects and wiring of built containers a and b, e.g. of
+// two releases, and reports objects added into b, removed from a, and
+// fields injected with other objects.
+func Diff(a, b *SimpleContainer) DiffReport {
+	return DiffGraphs(a.Graph(), b.Graph())
+}
+
+// DiffGraphs compares graphs returned by Graph, e.g. stored as JSON by
+// a deploy pipeline, the same way as Diff.
+func DiffGraphs(a, b Graph) DiffReport {
+	var r DiffReport
+	an, bn := nodeNames(a), nodeNames(b)
+	r.Added = missingNames(bn, an)
+	r.Removed = missingNames(an, bn)
+
+	ai, bi := injections(a, an), injections(b, bn)
+	keys := make(map[injectionKey]bool)
+	for k := range ai {
+		keys[k] = true
+	}
+	for k := range bi {
+		keys[k] = true
+	}
+	for k := range keys {
+		before, after := ai[k], bi[k]
+		if strings.Join(before, "\n") != strings.Join(after, "\n") {
+			r.Changed = append(r.Changed, InjectionChange{Consumer: k.consumer, Field: k.field, Before: before, After: after})
+		}
+	}
+	sort.Slice(r.Changed, func(x, y int) bool {
+		if r.Changed[x].Consumer != r.Changed[y].Consumer {
+			return r.Changed[x].Consumer < r.Changed[y].Consumer
+		}
+		return r.Changed[x].Field < r.Changed[y].Field
+	})
+	return r
+}
+
+// Empty reports whether the containers compared have the same wiring.
+func (r DiffReport) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Changed) == 0
+}
+
+// String returns the report as lines prefixed by "+" for added objects,
+// "-" for removed objects and "~" for changed injections.
+func (r DiffReport) String() string {
+	var b strings.Builder
+	for _, n := range r.Added {
+		b.WriteString("+ " + n + "\n")
+	}
+	for _, n := range r.Removed {
+		b.WriteString("- " + n + "\n")
+	}
+	for _, c := range r.Changed {
+		field := c.Field
+		if field == "" {
+			field = "DependsOn"
+		}
+		b.WriteString("~ " + c.Consumer + "." + field + ": " + listOrNone(c.Before) + " -> " + listOrNone(c.After) + "\n")
+	}
+	return b.String()
+}
+
+func listOrNone(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// nodeNames returns unique names of nodes of g by node ID. Names of
+// objects sharing the same name get a numeric suffix in the order
+// they've been added.
+func nodeNames(g Graph) map[int]string {
+	res := make(map[int]string, len(g.Nodes))
+	used := make(map[string]error, len(g.Nodes))
+	for _, n := range g.Nodes {
+		name := uniqueKey(used, n.Name)
+		used[name] = nil
+		res[n.ID] = name
+	}
+	return res
+}
+
+// missingNames returns sorted names of from not present in other.
+func missingNames(from, other map[int]string) []string {
+	names := make(map[string]bool, len(other))
+	for _, n := range other {
+		names[n] = true
+	}
+	var res []string
+	for _, n := range from {
+		if !names[n] {
+			res = append(res, n)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+type injectionKey struct {
+	consumer string
+	field    string
+}
+
+// injections returns sorted names of objects injected into each field.
+func injections(g Graph, names map[int]string) map[injectionKey][]string {
+	res := make(map[injectionKey][]string)
+	for _, e := range g.Edges {
+		k := injectionKey{consumer: names[e.From]}
+		if !e.Explicit {
+			k.field = e.Field
+		}
+		res[k] = append(res[k], names[e.To])
+	}
+	for _, v := range res {
+		sort.Strings(v)
+	}
+	return res
+}
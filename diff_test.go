@@ -0,0 +1,40 @@
+package sdi_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestDiff(t *testing.T) {
+	a := sdi.New()
+	a.AddNamed("a", &A{})
+	a.AddNamed("b", &B{})
+	a.AddNamed("c", &C{})
+	a.BuildDependencies()
+
+	b := sdi.New()
+	b.AddNamed("a2", &A{})
+	b.AddNamed("b", &B{})
+	b.AddNamed("c", &C{})
+	b.BuildDependencies()
+
+	r := sdi.Diff(a, b)
+	expected := sdi.DiffReport{
+		Added:   []string{"a2"},
+		Removed: []string{"a"},
+		Changed: []sdi.InjectionChange{
+			{Consumer: "b", Field: "AService", Before: []string{"a"}, After: []string{"a2"}},
+		},
+	}
+	if !reflect.DeepEqual(r, expected) {
+		t.Errorf("expected %+v, got %+v", expected, r)
+	}
+	if s := r.String(); s != "+ a2\n- a\n~ b.AService: a -> a2\n" {
+		t.Errorf("unexpected report %q", s)
+	}
+	if !sdi.Diff(a, a).Empty() {
+		t.Errorf("expected no difference, got %v", sdi.Diff(a, a))
+	}
+}
@@ -0,0 +1,52 @@
+package sdi
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Drainer is the interface that wraps the basic Drain method.
+//
+// Drain makes the started object stop accepting new work and waits for
+// work in flight to finish, e.g. an HTTP server closes its listeners and
+// waits for active requests. Drain is called by Stop before Stop of any
+// object.
+type Drainer interface {
+	Drain(ctx context.Context) error
+}
+
+// WithDrainTimeout sets maximum duration of the drain phase of Stop, see
+// Drainer. Objects whose Drain has not returned in time are abandoned and
+// stopped. Zero means no separate deadline, the drain phase is limited by
+// the context passed to Stop only.
+func WithDrainTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.drainTimeout = d
+	}
+}
+
+// drain calls Drain of started objects implementing Drainer in the order
+// of Stop calls.
+func (c *SimpleContainer) drain(ctx context.Context) error {
+	if d := c.opts.drainTimeout; d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+
+	var errs []error
+	for _, i := range c.stopSequence() {
+		d, ok := c.objects[i].(Drainer)
+		if !ok {
+			continue
+		}
+		if s := c.stateOf(i); !s.started || s.stopped {
+			continue
+		}
+		if err := callUntilDone(ctx, c.nameOf(i)+".Drain", d.Drain); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,66 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+type draining struct {
+	journaled
+	hang bool
+}
+
+func (d *draining) Drain(ctx context.Context) error {
+	if d.hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	d.journal.calls = append(d.journal.calls, "drain "+d.name)
+	return nil
+}
+
+func TestDrain(t *testing.T) {
+	var jn journal
+	ctx := context.Background()
+	cs := sdi.New()
+	cs.Add(&draining{journaled: journaled{name: "consumer", journal: &jn}},
+		&journaled{name: "db", journal: &jn},
+		&draining{journaled: journaled{name: "http", journal: &jn}})
+	cs.BuildDependencies()
+	cs.InitRequired(ctx)
+	cs.StartRunners(ctx)
+	jn.calls = nil
+
+	if err := cs.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"drain http", "drain consumer", "stop http", "stop db", "stop consumer"}
+	if !reflect.DeepEqual(jn.calls, expected) {
+		t.Errorf("expected %v, got %v", expected, jn.calls)
+	}
+}
+
+func TestDrainTimeout(t *testing.T) {
+	var jn journal
+	ctx := context.Background()
+	cs := sdi.New(sdi.WithDrainTimeout(5 * time.Millisecond))
+	cs.AddNamed("http", &draining{journaled: journaled{name: "http", journal: &jn}, hang: true})
+	cs.BuildDependencies()
+	cs.InitRequired(ctx)
+	cs.StartRunners(ctx)
+	jn.calls = nil
+
+	err := cs.Stop(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "http.Drain abandoned") {
+		t.Errorf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(jn.calls, []string{"stop http"}) {
+		t.Errorf("expected object to be stopped after drain timeout, got %v", jn.calls)
+	}
+}
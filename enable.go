@@ -0,0 +1,41 @@
+package sdi
+
+// Enabler is the interface that wraps the basic Enabled method.
+//
+// Enabled is invocated inside container's BuildDependencies() before
+// linking. Objects returning false are removed from container, they're
+// neither injected nor initialized, started or stopped.
+type Enabler interface {
+	Enabled() bool
+}
+
+// AddIf adds objects into container if cond is true. It panics in the same
+// cases as Add even if cond is false.
+func (c *SimpleContainer) AddIf(cond bool, o ...interface{}) {
+	objects, _ := withAddOptions(o, registration{})
+	for i := range objects {
+		mustBeContainerable(objects[i])
+	}
+	if cond {
+		c.Add(o...)
+	}
+}
+
+// removeDisabled removes objects implementing Enabler and returning false
+// and objects added for inactive profile. The caller must hold write lock.
+func (c *SimpleContainer) removeDisabled() {
+	objects, regs := c.objects[:0], c.regs[:0]
+	for i, o := range c.objects {
+		if e, ok := o.(Enabler); ok && !e.Enabled() {
+			c.logf("sdi: %s is disabled", c.nameOf(i))
+			continue
+		}
+		if c.inactive(i) {
+			c.logf("sdi: %s is added for inactive profile %q", c.nameOf(i), c.regs[i].profile)
+			continue
+		}
+		objects = append(objects, o)
+		regs = append(regs, c.regs[i])
+	}
+	c.objects, c.regs = objects, regs
+}
@@ -0,0 +1,41 @@
+package sdi_test
+
+import (
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type featureFlagged struct {
+	C
+	enabled bool
+}
+
+func (ff *featureFlagged) Enabled() bool {
+	return ff.enabled
+}
+
+func TestEnabler(t *testing.T) {
+	cs := sdi.New()
+	b := B{}
+	enabled := featureFlagged{enabled: true}
+	cs.Add(&featureFlagged{}, &b, &enabled)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if b.CService != &enabled {
+		t.Error("expected enabled object to be injected")
+	}
+	if n := len(cs.Objects()); n != 2 {
+		t.Errorf("expected 2 objects, got %d", n)
+	}
+}
+
+func TestAddIf(t *testing.T) {
+	cs := sdi.New()
+	cs.AddIf(false, &C{})
+	cs.AddIf(true, &A{})
+	if n := len(cs.Objects()); n != 1 {
+		t.Errorf("expected 1 object, got %d", n)
+	}
+}
@@ -0,0 +1,66 @@
+package sdi
+
+import "errors"
+
+var (
+	// ErrNotContainerable is the value of panic of Add if the object
+	// can't be added into container.
+	ErrNotContainerable = errors.New("sdi: object is not containerable")
+
+	// ErrUnresolvedDependency is returned when a dependency required
+	// explicitly, e.g. by qualifier or Resolve, can not be found.
+	ErrUnresolvedDependency = errors.New("sdi: unresolved dependency")
+
+	// ErrCycle is returned when containered objects depend on each other
+	// and can not be ordered.
+	ErrCycle = errors.New("sdi: dependency cycle")
+
+	// ErrAmbiguous is returned by Build when several containered objects
+	// are assignable to the same field and none is chosen explicitly.
+	ErrAmbiguous = errors.New("sdi: ambiguous dependency")
+
+	// ErrInvalidState is returned when a lifecycle method is called
+	// out of order, e.g. StartRunners before InitRequired.
+	ErrInvalidState = errors.New("sdi: invalid container state")
+
+	// ErrWiringChanged is returned by Build when wiring differs
+	// from the one recorded before, see WithWiringVerify.
+	ErrWiringChanged = errors.New("sdi: wiring changed")
+)
+
+// InitError is returned by InitRequired when Init of a containered object
+// fails.
+type InitError struct {
+	// Object is the name of the object.
+	Object string
+
+	// Err is the error returned by Init.
+	Err error
+}
+
+func (e *InitError) Error() string {
+	return "sdi: init " + e.Object + ": " + e.Err.Error()
+}
+
+func (e *InitError) Unwrap() error {
+	return e.Err
+}
+
+// StartError is returned by StartRunners and StartRunnersConcurrent when
+// Start of a containered object fails. Errors of managed runners are
+// reported as RunnerError.
+type StartError struct {
+	// Object is the name of the object.
+	Object string
+
+	// Err is the error returned by Start.
+	Err error
+}
+
+func (e *StartError) Error() string {
+	return "sdi: start " + e.Object + ": " + e.Err.Error()
+}
+
+func (e *StartError) Unwrap() error {
+	return e.Err
+}
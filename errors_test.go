@@ -0,0 +1,64 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestNotContainerable(t *testing.T) {
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || !errors.Is(err, sdi.ErrNotContainerable) {
+			t.Errorf("expected ErrNotContainerable, got %v", err)
+		}
+	}()
+	sdi.New().Add(&struct{}{})
+}
+
+func TestUnresolvedDependency(t *testing.T) {
+	if _, err := sdi.Resolve[Pinger](sdi.New()); !errors.Is(err, sdi.ErrUnresolvedDependency) {
+		t.Errorf("expected ErrUnresolvedDependency, got %v", err)
+	}
+
+	cs := sdi.New()
+	cs.Add(&replicated{})
+	if err := cs.Build(); !errors.Is(err, sdi.ErrUnresolvedDependency) {
+		t.Errorf("expected ErrUnresolvedDependency, got %v", err)
+	}
+}
+
+func TestInitError(t *testing.T) {
+	errDB := errors.New("no database")
+	cs := sdi.New()
+	cs.AddNamed("db", &failingInit{err: errDB})
+	cs.BuildDependencies()
+
+	err := cs.InitRequired(context.Background())
+	var ie *sdi.InitError
+	if !errors.As(err, &ie) || ie.Object != "db" || !errors.Is(err, errDB) {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err.Error() != "sdi: init db: no database" {
+		t.Errorf("unexpected message %q", err)
+	}
+}
+
+func TestStartError(t *testing.T) {
+	errBind := errors.New("address in use")
+	cs := sdi.New()
+	cs.Add(&serving{err: errBind})
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+
+	err := cs.StartRunners(context.Background())
+	var se *sdi.StartError
+	if !errors.As(err, &se) || !errors.Is(err, errBind) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err.Error() != "sdi: start *sdi_test.serving: address in use" {
+		t.Errorf("unexpected message %q", err)
+	}
+}
@@ -0,0 +1,155 @@
+package sdi
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType is the type of container lifecycle event.
+type EventType int
+
+const (
+	// ObjectAdded is emitted when an object is added into container.
+	ObjectAdded EventType = iota
+
+	// Wired is emitted when BuildDependencies succeeds.
+	Wired
+
+	// InitStarted is emitted before Init of an object.
+	InitStarted
+
+	// InitFinished is emitted after Init of an object, Err is the
+	// error returned by Init.
+	InitFinished
+
+	// RunnerStarted is emitted when Start of a runner is called.
+	RunnerStarted
+
+	// RunnerExited is emitted when Start of a managed runner returns or
+	// Start of a runner fails, Err is the error returned by Start.
+	RunnerExited
+
+	// ShutdownBegan is emitted when Stop is called.
+	ShutdownBegan
+
+	// LivenessFailed is emitted when liveness check of a runner fails,
+	// Err is the error returned by Liveness. See WithWatchdog.
+	LivenessFailed
+
+	// InitSlow is emitted when Init of an object runs longer than
+	// the threshold set by WithInitWarning, Duration is the threshold.
+	InitSlow
+
+	// StartBlocking is emitted when Start of a runner has not returned
+	// within the threshold set by WithStartWarning, Duration is
+	// the threshold.
+	StartBlocking
+
+	// CircuitOpened is emitted when a supervised runner failed too many
+	// times and is not restarted anymore, see RestartPolicy. Err describes
+	// the failures.
+	CircuitOpened
+
+	// RunnersStarted is emitted when StartRunners or
+	// StartRunnersConcurrent moves the container to StateStarted, Err
+	// joins errors of runners failed to start, see
+	// WithContinueOnStartError.
+	RunnersStarted
+
+	// Reconfiguring is emitted when Reconfigure is called.
+	Reconfiguring
+
+	// Reconfigured is emitted when Reconfigure finishes, Err joins errors
+	// returned by Reconfigure of objects.
+	Reconfigured
+
+	// LivenessPassed is emitted after each round of liveness checks none
+	// of which failed, see WithWatchdog.
+	LivenessPassed
+)
+
+var eventTypeNames = [...]string{"object added", "wired", "init started", "init finished",
+	"runner started", "runner exited", "shutdown began", "liveness failed", "init slow", "start blocking", "circuit opened",
+	"runners started", "reconfiguring", "reconfigured", "liveness passed"}
+
+func (t EventType) String() string {
+	if t >= 0 && int(t) < len(eventTypeNames) {
+		return eventTypeNames[t]
+	}
+	return "unknown"
+}
+
+// Event describes a container lifecycle event.
+type Event struct {
+	Type EventType
+	Time time.Time
+
+	// Object is the name of the object, empty for container events.
+	Object string
+
+	// Duration of Init or Start for InitFinished and RunnerExited events.
+	Duration time.Duration
+
+	// Err is the error of InitFinished, RunnerExited, RunnersStarted and
+	// Reconfigured events.
+	Err error
+
+	// Stack is the dump of all goroutines of InitSlow event, if requested
+	// by WithInitWarning.
+	Stack []byte
+}
+
+// subscribers holds functions receiving container events.
+type subscribers struct {
+	mux  sync.Mutex
+	next int
+	fns  []subscriber
+}
+
+type subscriber struct {
+	id int
+	fn func(Event)
+}
+
+// Subscribe registers function fn receiving container lifecycle events
+// and returns function cancelling the subscription.
+//
+// Subscribers are called synchronously, concurrently if the container
+// inits or starts objects concurrently, and must not call methods of
+// the container.
+func (c *SimpleContainer) Subscribe(fn func(Event)) (unsubscribe func()) {
+	s := &c.subs
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	id := s.next
+	s.next++
+	s.fns = append(s.fns, subscriber{id: id, fn: fn})
+
+	return func() {
+		s.mux.Lock()
+		defer s.mux.Unlock()
+		for i := range s.fns {
+			if s.fns[i].id == id {
+				s.fns = append(s.fns[:i:i], s.fns[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// emit sends event e to subscribers.
+func (c *SimpleContainer) emit(e Event) {
+	s := &c.subs
+	s.mux.Lock()
+	fns := s.fns
+	s.mux.Unlock()
+
+	if len(fns) == 0 {
+		return
+	}
+	e.Time = time.Now()
+	for _, sub := range fns {
+		sub.fn(e)
+	}
+}
@@ -0,0 +1,104 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type failingRunner struct{}
+
+func (fr *failingRunner) Start(ctx context.Context) error {
+	return errors.New("port in use")
+}
+
+func TestSubscribe(t *testing.T) {
+	var (
+		jn     journal
+		mux    sync.Mutex
+		events []string
+	)
+	cs := sdi.New()
+	unsubscribe := cs.Subscribe(func(e sdi.Event) {
+		mux.Lock()
+		defer mux.Unlock()
+		s := e.Type.String()
+		if e.Object != "" {
+			s += " " + e.Object
+		}
+		if e.Err != nil {
+			s += ": " + e.Err.Error()
+		}
+		events = append(events, s)
+	})
+
+	cs.AddNamed("a", &journaled{name: "a", journal: &jn})
+	cs.AddNamed("b", &failingRunner{})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.StartRunners(context.Background()); err == nil {
+		t.Fatal("expected start error")
+	}
+	unsubscribe()
+	cs.Stop(context.Background())
+
+	expected := []string{
+		"object added a", "object added b", "wired",
+		"init started a", "init finished a",
+		"runner started a", "runner started b", "runner exited b: port in use",
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("expected %q, got %q", expected, events)
+	}
+}
+
+func TestSubscribeShutdown(t *testing.T) {
+	var types []sdi.EventType
+	cs := sdi.New()
+	cs.Subscribe(func(e sdi.Event) {
+		if e.Time.IsZero() {
+			t.Error("expected event time")
+		}
+		types = append(types, e.Type)
+	})
+	cs.BuildDependencies()
+	cs.Stop(context.Background())
+
+	if len(types) != 2 || types[1] != sdi.ShutdownBegan {
+		t.Errorf("unexpected events %v", types)
+	}
+}
+
+func TestSubscribeReconfigure(t *testing.T) {
+	var types []sdi.EventType
+	cs := sdi.New()
+	cs.Subscribe(func(e sdi.Event) {
+		switch e.Type {
+		case sdi.RunnersStarted, sdi.Reconfiguring, sdi.Reconfigured:
+			types = append(types, e.Type)
+		}
+	})
+	cs.Add(&journaled{name: "a", journal: &journal{}})
+	ctx := context.Background()
+	cs.BuildDependencies()
+	cs.InitRequired(ctx)
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.Reconfigure(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []sdi.EventType{sdi.RunnersStarted, sdi.Reconfiguring, sdi.Reconfigured}
+	if !reflect.DeepEqual(types, expected) {
+		t.Errorf("expected %v, got %v", expected, types)
+	}
+}
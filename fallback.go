@@ -0,0 +1,42 @@
+package sdi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// WithFallbackResolver sets function consulted for fields and types
+// resolved by Resolve when neither containered object, transient factory,
+// parent container nor build info satisfies them. fn returns object
+// assignable to the type or false. It's meant for tests auto-stubbing
+// uninteresting dependencies by mocks instead of registering fakes:
+//
+//	c := sdi.New(sdi.WithFallbackResolver(func(t reflect.Type) (interface{}, bool) {
+//		if t == reflect.TypeOf((*Mailer)(nil)).Elem() {
+//			return &mocks.Mailer{}, true
+//		}
+//		return nil, false
+//	}))
+//
+// Objects returned by fn are not containered: their fields are not
+// injected and they are neither initialized nor stopped.
+func WithFallbackResolver(fn func(fieldType reflect.Type) (interface{}, bool)) Option {
+	return func(o *options) {
+		o.fallbackResolver = fn
+	}
+}
+
+// fallback returns object of fallback resolver assignable to type t.
+func (c *SimpleContainer) fallback(t reflect.Type) (interface{}, bool, error) {
+	if c.opts.fallbackResolver == nil {
+		return nil, false, nil
+	}
+	o, ok := c.opts.fallbackResolver(t)
+	if !ok {
+		return nil, false, nil
+	}
+	if o == nil || !reflect.TypeOf(o).AssignableTo(t) {
+		return nil, false, fmt.Errorf("sdi: fallback resolver returned %T not assignable to %s", o, t)
+	}
+	return o, true, nil
+}
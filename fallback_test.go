@@ -0,0 +1,52 @@
+package sdi_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type stubAge struct{}
+
+func (stubAge) Age() int { return 99 }
+
+func TestFallbackResolver(t *testing.T) {
+	var asked []reflect.Type
+	cs := sdi.New(sdi.WithFallbackResolver(func(t reflect.Type) (interface{}, bool) {
+		asked = append(asked, t)
+		if t == reflect.TypeOf((*AI)(nil)).Elem() {
+			return stubAge{}, true
+		}
+		return nil, false
+	}))
+	b := &B{}
+	cs.Add(b, &C{})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := b.AService.(stubAge); !ok {
+		t.Errorf("expected stub of fallback resolver, got %T", b.AService)
+	}
+	for _, at := range asked {
+		if at == reflect.TypeOf((*CI)(nil)).Elem() {
+			t.Error("expected fallback resolver not to be asked for resolved field")
+		}
+	}
+
+	a, err := sdi.Resolve[AI](cs)
+	if err != nil || a.Age() != 99 {
+		t.Errorf("expected Resolve to use fallback resolver, got %v, %v", a, err)
+	}
+}
+
+func TestFallbackResolverWrongType(t *testing.T) {
+	cs := sdi.New(sdi.WithFallbackResolver(func(t reflect.Type) (interface{}, bool) {
+		return &C{}, t == reflect.TypeOf((*AI)(nil)).Elem()
+	}))
+	cs.Add(&B{})
+	if err := cs.Build(); err == nil {
+		t.Error("expected error on object not assignable to the field")
+	}
+}
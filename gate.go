@@ -0,0 +1,146 @@
+package sdi
+
+import (
+	"context"
+	"sync"
+)
+
+// Gate holds back Start of runners added with option Gated until it's
+// opened, e.g. by a migration job, leader election or fetch of feature
+// flags completing outside of the container, see SimpleContainer.Gate.
+type Gate struct {
+	name   string
+	once   sync.Once
+	opened chan struct{}
+}
+
+// Gate returns start gate with the name creating it on the first call.
+// Runners added with Gated(g) are not started by StartRunners until g is
+// opened:
+//
+//	g := c.Gate("after-migrations")
+//	c.Add(httpServer, sdi.Gated(g))
+//	...
+//	c.StartRunners(ctx)
+//	go func() {
+//		migrate(ctx)
+//		g.Open()
+//	}()
+//
+// StartRunners returns without waiting for closed gates, Start of gated
+// runners is called in a separate goroutine once the gate is opened,
+// errors returned by it are sent to Errors and Done. Runners of gates
+// opened before StartRunners are started as other runners. Stop cancels
+// waiting for the gate. It panics if the name is empty.
+func (c *SimpleContainer) Gate(name string) *Gate {
+	if name == "" {
+		panic("sdi: empty gate name")
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	g, ok := c.gates[name]
+	if !ok {
+		g = &Gate{name: name, opened: make(chan struct{})}
+		if c.gates == nil {
+			c.gates = make(map[string]*Gate)
+		}
+		c.gates[name] = g
+	}
+	return g
+}
+
+// Name returns the gate name.
+func (g *Gate) Name() string {
+	return g.name
+}
+
+// Open opens the gate starting runners waiting for it. Calls after
+// the first one do nothing.
+func (g *Gate) Open() {
+	g.once.Do(func() {
+		close(g.opened)
+	})
+}
+
+// Opened returns channel closed when the gate is opened.
+func (g *Gate) Opened() <-chan struct{} {
+	return g.opened
+}
+
+// IsOpen reports whether the gate is opened.
+func (g *Gate) IsOpen() bool {
+	select {
+	case <-g.opened:
+		return true
+	default:
+		return false
+	}
+}
+
+// Gated makes runners wait for the gate g before Start, see Gate.
+func Gated(g *Gate) AddOption {
+	if g == nil {
+		panic("sdi: nil gate")
+	}
+	return func(r *registration) {
+		r.gate = g
+	}
+}
+
+// gated reports whether the runner at position i waits for a closed gate.
+func (c *SimpleContainer) gated(i int) bool {
+	g := c.regs[i].gate
+	return g != nil && !g.IsOpen()
+}
+
+// startGated calls Start of the runner at position i in a separate
+// goroutine once its gate is opened, unless ctx is done or the runner is
+// stopped before.
+func (c *SimpleContainer) startGated(ctx context.Context, i int, r Runner) {
+	g := c.regs[i].gate
+	wctx, cancel := context.WithCancel(ctx)
+	c.setState(i, func(s *objectState) {
+		s.cancel = cancel
+	})
+	c.logf("sdi: %s waits for gate %s", c.nameOf(i), g.name)
+
+	errc, done := c.errorsChan(), c.doneChan()
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer cancel()
+		_, unlabel := c.labeled(ctx, i)
+		defer unlabel()
+
+		select {
+		case <-g.opened:
+		case <-wctx.Done():
+			return
+		}
+		if wctx.Err() != nil {
+			return
+		}
+		if c.opts.managed || c.blocking(i) {
+			c.setState(i, func(s *objectState) {
+				s.running = true
+				s.started = true
+				s.stopped = false
+			})
+			c.supervise(ctx, i, r, errc, done)
+			return
+		}
+		if err := c.startObject(ctx, i, r); err != nil {
+			re := RunnerError{Object: c.nameOf(i), Err: err}
+			select {
+			case errc <- re:
+			default:
+			}
+			select {
+			case done <- re:
+			default:
+			}
+		}
+	}()
+}
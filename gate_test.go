@@ -0,0 +1,144 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+type gatedServer struct {
+	starts atomic.Int32
+	err    error
+}
+
+func (s *gatedServer) Start(ctx context.Context) error {
+	s.starts.Add(1)
+	return s.err
+}
+
+func TestGate(t *testing.T) {
+	ctx := context.Background()
+	srv, other := &gatedServer{}, &gatedServer{}
+	cs := sdi.New()
+	g := cs.Gate("after-migrations")
+	if cs.Gate("after-migrations") != g {
+		t.Fatal("expected the same gate for the same name")
+	}
+	cs.Add(srv, sdi.Gated(g))
+	cs.Add(other)
+	cs.BuildDependencies()
+	cs.InitRequired(ctx)
+
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if srv.starts.Load() != 0 || other.starts.Load() != 1 {
+		t.Fatalf("expected only runner without gate to be started, got %d and %d", srv.starts.Load(), other.starts.Load())
+	}
+
+	g.Open()
+	g.Open()
+	waitFor(t, func() bool { return srv.starts.Load() == 1 })
+	if !g.IsOpen() {
+		t.Error("expected gate to be open")
+	}
+	if err := cs.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGateStopped(t *testing.T) {
+	ctx := context.Background()
+	srv := &gatedServer{}
+	cs := sdi.New()
+	g := cs.Gate("leader")
+	cs.Add(srv, sdi.Gated(g))
+	cs.BuildDependencies()
+	cs.InitRequired(ctx)
+	cs.StartRunners(ctx)
+
+	if err := cs.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	g.Open()
+	if srv.starts.Load() != 0 {
+		t.Error("expected runner stopped before the gate is opened not to be started")
+	}
+}
+
+func TestGateError(t *testing.T) {
+	ctx := context.Background()
+	errListen := errors.New("listen failed")
+	cs := sdi.New()
+	g := cs.Gate("flags")
+	cs.Add(&gatedServer{err: errListen}, sdi.Gated(g))
+	cs.BuildDependencies()
+	cs.InitRequired(ctx)
+	cs.StartRunners(ctx)
+
+	g.Open()
+	if err := <-cs.Done(); !errors.Is(err, errListen) {
+		t.Errorf("expected %v, got %v", errListen, err)
+	}
+}
+
+func TestGateClone(t *testing.T) {
+	ctx := context.Background()
+	base := sdi.New()
+	g := base.Gate("after-migrations")
+	base.Add(&gatedServer{}, sdi.Gated(g))
+
+	cs := base.Clone()
+	cg := cs.Gate("after-migrations")
+	if cg == g {
+		t.Fatal("expected clone to have its own gate")
+	}
+	cs.BuildDependencies()
+	cs.InitRequired(ctx)
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Stop(ctx)
+
+	srv := sdi.MustResolve[*gatedServer](cs)
+	g.Open()
+	time.Sleep(10 * time.Millisecond)
+	if srv.starts.Load() != 0 {
+		t.Fatal("expected gate of the original container not to start runners of the clone")
+	}
+	cg.Open()
+	waitFor(t, func() bool { return srv.starts.Load() == 1 })
+}
+
+func TestGateMerge(t *testing.T) {
+	ctx := context.Background()
+	srv := &gatedServer{}
+	other := sdi.New()
+	other.Add(srv, sdi.Gated(other.Gate("after-migrations")))
+	other.Add(&gatedServer{}, sdi.Gated(other.Gate("leader")))
+
+	cs := sdi.New()
+	g := cs.Gate("after-migrations")
+	if err := cs.Merge(other); err != nil {
+		t.Fatal(err)
+	}
+	if cs.Gate("leader") != other.Gate("leader") {
+		t.Error("expected gate of merged container to be added")
+	}
+	cs.BuildDependencies()
+	cs.InitRequired(ctx)
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Stop(ctx)
+
+	g.Open()
+	waitFor(t, func() bool { return srv.starts.Load() == 1 })
+}
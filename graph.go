@@ -0,0 +1,123 @@
+package sdi
+
+import (
+	"bufio"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// Graph is the wiring of containered objects discovered by
+// BuildDependencies, see Graph method.
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a containered object. ID is the position of the object in
+// the order objects have been added into container.
+type GraphNode struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Module string `json:"module,omitempty"`
+	Group  string `json:"group,omitempty"`
+
+	// Lifecycle interfaces implemented by the object.
+	Initializer bool `json:"initializer"`
+	Runner      bool `json:"runner"`
+	Stopper     bool `json:"stopper"`
+	Globalizer  bool `json:"globalizer"`
+}
+
+// GraphEdge is the injection of the object To into field Field of
+// the object From, or the dependency of From on To declared by DependsOn
+// if Explicit is true.
+type GraphEdge struct {
+	From     int    `json:"from"`
+	To       int    `json:"to"`
+	Field    string `json:"field,omitempty"`
+	Explicit bool   `json:"explicit,omitempty"`
+}
+
+// Graph returns dependencies discovered by BuildDependencies. Every
+// containered object is a node, every injected field and dependency
+// declared by DependsOn is an edge from the consumer to the provider.
+func (c *SimpleContainer) Graph() Graph {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	return c.graph()
+}
+
+// graph returns wiring of containered objects. The caller must hold read
+// lock.
+func (c *SimpleContainer) graph() Graph {
+	g := Graph{
+		Nodes: make([]GraphNode, len(c.objects)),
+		Edges: make([]GraphEdge, len(c.deps)),
+	}
+	for i, o := range c.objects {
+		n := &g.Nodes[i]
+		n.ID = i
+		n.Name = c.nameOf(i)
+		n.Type = reflect.TypeOf(o).String()
+		n.Module = c.regs[i].module
+		n.Group = c.regs[i].group
+		_, n.Initializer = o.(Initializer)
+		_, n.Runner = o.(Runner)
+		_, n.Stopper = o.(Stopper)
+		_, n.Globalizer = o.(Globalizer)
+	}
+	for k, d := range c.deps {
+		g.Edges[k] = GraphEdge{From: d.consumer, To: d.provider, Field: d.field, Explicit: d.explicit}
+	}
+	return g
+}
+
+// Dependents returns IDs of nodes depending on the node id directly or
+// transitively, i.e. objects affected by a change of the object.
+func (g Graph) Dependents(id int) []int {
+	seen := make([]bool, len(g.Nodes))
+	var res []int
+	queue := []int{id}
+	for len(queue) > 0 {
+		to := queue[0]
+		queue = queue[1:]
+		for _, e := range g.Edges {
+			if e.To == to && !seen[e.From] && e.From != id {
+				seen[e.From] = true
+				res = append(res, e.From)
+				queue = append(queue, e.From)
+			}
+		}
+	}
+	return res
+}
+
+// GraphDOT writes dependencies discovered by BuildDependencies to w in
+// Graphviz DOT format. Every containered object is a node, every injected
+// field is an edge from the consumer to the injected object labeled
+// by the field name. Dependencies declared by DependsOn are dashed edges.
+func (c *SimpleContainer) GraphDOT(w io.Writer) error {
+	g := c.Graph()
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString("digraph sdi {\n")
+	for _, n := range g.Nodes {
+		bw.WriteString("\tn" + strconv.Itoa(n.ID) + " [label=" + strconv.Quote(n.Name) + "];\n")
+	}
+	for _, e := range g.Edges {
+		bw.WriteString("\tn" + strconv.Itoa(e.From) + " -> n" + strconv.Itoa(e.To))
+		switch {
+		case e.Explicit:
+			bw.WriteString(" [style=dashed]")
+		case e.Field != "":
+			bw.WriteString(" [label=" + strconv.Quote(e.Field) + "]")
+		}
+		bw.WriteString(";\n")
+	}
+	bw.WriteString("}\n")
+
+	return bw.Flush()
+}
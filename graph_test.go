@@ -0,0 +1,51 @@
+package sdi_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestGraphDOT(t *testing.T) {
+	cs := sdi.New()
+	cs.Add(&A{}, &B{}, &C{})
+	cs.BuildDependencies()
+
+	var buf bytes.Buffer
+	if err := cs.GraphDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `digraph sdi {
+	n0 [label="*sdi_test.A"];
+	n1 [label="*sdi_test.B"];
+	n2 [label="*sdi_test.C"];
+	n1 -> n0 [label="AService"];
+	n1 -> n2 [label="CService"];
+}
+`
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestGraph(t *testing.T) {
+	cs := sdi.New()
+	cs.AddModule(sdi.NewModule("storage", &A{}))
+	cs.Add(&B{}, &C{}, &gateway{})
+	cs.BuildDependencies()
+
+	g := cs.Graph()
+	if len(g.Nodes) != 4 || g.Nodes[0].Module != "storage" || g.Nodes[1].Type != "*sdi_test.B" || !g.Nodes[1].Runner {
+		t.Errorf("unexpected nodes %+v", g.Nodes)
+	}
+	expected := []sdi.GraphEdge{{From: 1, To: 0, Field: "AService"}, {From: 1, To: 2, Field: "CService"}}
+	if !reflect.DeepEqual(g.Edges, expected) {
+		t.Errorf("expected edges %+v, got %+v", expected, g.Edges)
+	}
+	if d := g.Dependents(0); !reflect.DeepEqual(d, []int{1}) {
+		t.Errorf("expected B to depend on A, got %v", d)
+	}
+}
@@ -0,0 +1,57 @@
+package sdi
+
+import (
+	"context"
+	"sync"
+)
+
+// group runs functions in separate goroutines, limits number of
+// simultaneously running functions and keeps the first returned error.
+// It's modeled after golang.org/x/sync/errgroup.
+type group struct {
+	wg     sync.WaitGroup
+	sem    chan struct{}
+	once   sync.Once
+	err    error
+	cancel context.CancelFunc
+}
+
+// newGroup returns group and context derived from ctx. The derived context
+// is cancelled when a function passed to Go returns an error. Limit less or
+// equal zero means no limit.
+func newGroup(ctx context.Context, limit int) (*group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	g := &group{cancel: cancel}
+	if limit > 0 {
+		g.sem = make(chan struct{}, limit)
+	}
+	return g, ctx
+}
+
+// Go calls f in a new goroutine. It blocks while the limit is reached.
+func (g *group) Go(f func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.wg.Add(1)
+	go func() {
+		defer func() {
+			if g.sem != nil {
+				<-g.sem
+			}
+			g.wg.Done()
+		}()
+		if err := f(); err != nil {
+			g.once.Do(func() {
+				g.err = err
+				g.cancel()
+			})
+		}
+	}()
+}
+
+// Wait blocks until all functions return and returns the first error.
+func (g *group) Wait() error {
+	g.wg.Wait()
+	return g.err
+}
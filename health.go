@@ -0,0 +1,112 @@
+package sdi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Healther is the interface that wraps the basic Health method.
+//
+// Health reports whether the containered object is able to serve.
+// A nil error means healthy.
+type Healther interface {
+	Health(context.Context) error
+}
+
+// Health calls Health of each containered object implementing Healther
+// interface and returns results keyed by object name. Runners failed
+// the last liveness check, see WithWatchdog, and runners not restarted by
+// circuit breaker, see RestartPolicy, are reported unhealthy. Runners
+// stopped by StopOne are skipped.
+func (c *SimpleContainer) Health(ctx context.Context) map[string]error {
+	var (
+		hs       []Healther
+		names    []string
+		liveErrs []error
+	)
+	c.mux.RLock()
+	for i := range c.objects {
+		if i < len(c.states) && c.states[i].halted {
+			continue
+		}
+		h, ok := c.objects[i].(Healther)
+		var liveErr error
+		if i < len(c.states) {
+			liveErr = errors.Join(c.states[i].liveErr, c.states[i].failErr)
+		}
+		if ok || liveErr != nil {
+			hs = append(hs, h)
+			names = append(names, c.nameOf(i))
+			liveErrs = append(liveErrs, liveErr)
+		}
+	}
+	c.mux.RUnlock()
+
+	res := make(map[string]error, len(hs))
+	for i, h := range hs {
+		var err error
+		if h != nil {
+			err = h.Health(ctx)
+		}
+		if liveErrs[i] != nil {
+			err = errors.Join(err, liveErrs[i])
+		}
+		res[uniqueKey(res, names[i])] = err
+	}
+	return res
+}
+
+// uniqueKey returns key if it's not in m, otherwise key with a numeric suffix.
+func uniqueKey(m map[string]error, key string) string {
+	if _, ok := m[key]; !ok {
+		return key
+	}
+	for n := 2; ; n++ {
+		k := fmt.Sprintf("%s#%d", key, n)
+		if _, ok := m[k]; !ok {
+			return k
+		}
+	}
+}
+
+// HealthResponse is the JSON document written by HealthHandler.
+type HealthResponse struct {
+	Status   string            `json:"status"`
+	Services map[string]string `json:"services"`
+}
+
+// Values of HealthResponse.Status and HealthResponse.Services.
+const (
+	HealthStatusOK   = "ok"
+	HealthStatusFail = "fail"
+)
+
+// HealthHandler returns http.Handler reporting health of containered
+// objects implementing Healther as HealthResponse JSON.
+// It responds with status 200 if all objects are healthy and
+// 503 otherwise.
+func (c *SimpleContainer) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := HealthResponse{
+			Status:   HealthStatusOK,
+			Services: make(map[string]string),
+		}
+		for name, err := range c.Health(r.Context()) {
+			if err != nil {
+				resp.Status = HealthStatusFail
+				resp.Services[name] = err.Error()
+				continue
+			}
+			resp.Services[name] = HealthStatusOK
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if resp.Status != HealthStatusOK {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
@@ -0,0 +1,49 @@
+package sdi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type healthy struct {
+	err error
+}
+
+func (h *healthy) Global() {}
+
+func (h *healthy) Health(ctx context.Context) error {
+	return h.err
+}
+
+func TestHealth(t *testing.T) {
+	cs := sdi.New()
+	cs.Add(&healthy{}, &healthy{err: errors.New("db is down")}, &C{})
+
+	res := cs.Health(context.Background())
+	if len(res) != 2 {
+		t.Fatalf("expected 2 results, got %v", res)
+	}
+	if res["*sdi_test.healthy"] != nil || res["*sdi_test.healthy#2"] == nil {
+		t.Errorf("unexpected results %v", res)
+	}
+
+	rec := httptest.NewRecorder()
+	cs.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", rec.Code)
+	}
+
+	var resp sdi.HealthResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != sdi.HealthStatusFail || resp.Services["*sdi_test.healthy#2"] != "db is down" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
@@ -0,0 +1,54 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type hooked struct {
+	journaled
+	afterErr error
+}
+
+func (h *hooked) BeforeStart(ctx context.Context) error {
+	h.journal.calls = append(h.journal.calls, "before start "+h.name)
+	return nil
+}
+
+func (h *hooked) AfterStart(ctx context.Context) error {
+	h.journal.calls = append(h.journal.calls, "after start "+h.name)
+	return h.afterErr
+}
+
+func TestStartHooks(t *testing.T) {
+	var jn journal
+	cs := sdi.New()
+	cs.Add(&hooked{journaled: journaled{name: "a", journal: &jn}})
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+
+	if err := cs.StartRunners(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"init a", "before start a", "start a", "after start a"}
+	if !reflect.DeepEqual(jn.calls, expected) {
+		t.Errorf("expected %v, got %v", expected, jn.calls)
+	}
+}
+
+func TestAfterStartError(t *testing.T) {
+	var jn journal
+	errWarmup := errors.New("warmup failed")
+	cs := sdi.New()
+	cs.Add(&hooked{journaled: journaled{name: "a", journal: &jn}, afterErr: errWarmup})
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+
+	if err := cs.StartRunners(context.Background()); !errors.Is(err, errWarmup) {
+		t.Errorf("expected %v, got %v", errWarmup, err)
+	}
+}
@@ -0,0 +1,114 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type failingInit struct {
+	err error
+}
+
+func (f *failingInit) Init(ctx context.Context) error {
+	return f.err
+}
+
+func (f *failingInit) Ping() bool {
+	return false
+}
+
+type initCounter struct {
+	Dep   Pinger
+	inits int
+}
+
+func (ic *initCounter) Init(ctx context.Context) error {
+	ic.inits++
+	return nil
+}
+
+func TestContinueOnInitError(t *testing.T) {
+	for _, opts := range [][]sdi.Option{
+		{sdi.WithContinueOnInitError(), sdi.WithResolutionPolicy(sdi.PreferLast)},
+		{sdi.WithContinueOnInitError(), sdi.WithResolutionPolicy(sdi.PreferLast), sdi.WithParallelInit()},
+	} {
+		errDB := errors.New("no database")
+		errMQ := errors.New("no broker")
+		dependent := initCounter{}
+		independent := G(0)
+
+		cs := sdi.New(opts...)
+		cs.Add(&failingInit{err: errDB}, &C{})
+		cs.Add(&failingInit{err: errMQ}, &dependent, &independent)
+		cs.BuildDependencies()
+
+		err := cs.InitRequired(context.Background())
+		if !errors.Is(err, errDB) || !errors.Is(err, errMQ) {
+			t.Errorf("expected both errors, got %v", err)
+		}
+		if dependent.inits != 0 {
+			t.Error("dependent of failed object must be skipped")
+		}
+		if independent != 10 {
+			t.Error("independent object must be initialized")
+		}
+	}
+}
+
+// brokenRunner is a runner whose Init fails.
+type brokenRunner struct {
+	failingInit
+	starts int
+}
+
+func (f *brokenRunner) Start(ctx context.Context) error {
+	f.starts++
+	return nil
+}
+
+// dependentRunner is a runner depending on Pinger.
+type dependentRunner struct {
+	Dep    Pinger
+	starts int
+}
+
+func (d *dependentRunner) Start(ctx context.Context) error {
+	d.starts++
+	return nil
+}
+
+func TestContinueOnInitErrorStart(t *testing.T) {
+	var jn journal
+	ctx := context.Background()
+	failed := &brokenRunner{failingInit: failingInit{err: errors.New("no database")}}
+	dependent := &dependentRunner{}
+	api := &journaled{name: "api", journal: &jn}
+	cs := sdi.New(sdi.WithContinueOnInitError())
+	cs.Add(failed, dependent, api)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cs.InitRequired(ctx); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if failed.starts != 0 || dependent.starts != 0 {
+		t.Errorf("expected failed runner and its dependent not to be started, got %d and %d", failed.starts, dependent.starts)
+	}
+	if expected := []string{"init api", "start api"}; !reflect.DeepEqual(jn.calls, expected) {
+		t.Errorf("expected %v, got %v", expected, jn.calls)
+	}
+	if st := cs.Status(failed); !st.Failed {
+		t.Errorf("expected failed runner to be reported, got %+v", st)
+	}
+	if err := cs.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
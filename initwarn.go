@@ -0,0 +1,53 @@
+package sdi
+
+import (
+	"runtime"
+	"time"
+)
+
+// WithInitWarning makes the container warn about Init calls running
+// longer than threshold d, before the Init timeout set by WithInitTimeout
+// fires. The warning naming the object is written to the logger set by
+// WithLogger, emitted as InitSlow event and logged by the structured
+// logger set by WithSlog. If dump is true, the warning carries the stack
+// of all goroutines, showing what the Init is blocked on.
+func WithInitWarning(d time.Duration, dump bool) Option {
+	return func(o *options) {
+		o.initWarning = d
+		o.initDump = dump
+	}
+}
+
+// watchInit warns about Init of the object name running longer than
+// the threshold set by WithInitWarning. The returned function stops
+// watching.
+func (c *SimpleContainer) watchInit(name string) func() {
+	d := c.opts.initWarning
+	if d <= 0 {
+		return func() {}
+	}
+	t := time.AfterFunc(d, func() {
+		e := Event{Type: InitSlow, Object: name, Duration: d}
+		if c.opts.initDump {
+			e.Stack = goroutines()
+		}
+		c.logf("sdi: %s.Init is running longer than %s", name, d)
+		if e.Stack != nil {
+			c.logf("sdi: goroutines:\n%s", e.Stack)
+		}
+		c.emit(e)
+	})
+	return func() { t.Stop() }
+}
+
+// goroutines returns stack traces of all goroutines.
+func goroutines() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
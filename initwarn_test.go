@@ -0,0 +1,63 @@
+package sdi_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+// stuckInit blocks in Init until released.
+type stuckInit struct {
+	release chan struct{}
+}
+
+func (s *stuckInit) Init(ctx context.Context) error {
+	<-s.release
+	return nil
+}
+
+func TestWithInitWarning(t *testing.T) {
+	var (
+		buf    bytes.Buffer
+		mux    sync.Mutex
+		events []sdi.Event
+	)
+	si := &stuckInit{release: make(chan struct{})}
+	cs := sdi.New(
+		sdi.WithInitWarning(10*time.Millisecond, true),
+		sdi.WithLogger(log.New(&buf, "", 0)))
+	cs.Subscribe(func(e sdi.Event) {
+		if e.Type != sdi.InitSlow {
+			return
+		}
+		mux.Lock()
+		defer mux.Unlock()
+		events = append(events, e)
+		close(si.release)
+	})
+	cs.AddNamed("stuck", si)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	mux.Lock()
+	defer mux.Unlock()
+	if len(events) != 1 || events[0].Object != "stuck" || events[0].Duration != 10*time.Millisecond {
+		t.Fatalf("expected single init slow event of stuck, got %+v", events)
+	}
+	if !bytes.Contains(events[0].Stack, []byte("stuckInit).Init")) {
+		t.Errorf("expected goroutine dump showing blocked Init, got %s", events[0].Stack)
+	}
+	if !strings.Contains(buf.String(), "stuck.Init is running longer than 10ms") {
+		t.Errorf("expected warning logged, got %q", buf.String())
+	}
+}
@@ -0,0 +1,134 @@
+package sdi
+
+import "reflect"
+
+// ObjectInfo describes a containered object.
+type ObjectInfo struct {
+	// Name is the object name used in reports.
+	Name string
+
+	// Module is the name of the module the object was added with.
+	Module string
+
+	// Group is the runner group the object was added into, see AddGrouped.
+	Group string
+
+	// Type is the concrete type of the object.
+	Type reflect.Type
+
+	// Object is the containered object itself.
+	Object interface{}
+
+	// Lifecycle interfaces implemented by the object.
+	Initializer bool
+	Runner      bool
+	Stopper     bool
+	Globalizer  bool
+
+	// Supervision state of the runner, see WithSupervision.
+	Running   bool
+	Restarts  int
+	LastError error
+
+	// Dependencies lists objects injected into the object's fields.
+	Dependencies []Injection
+
+	// Dependents lists fields of other objects the object was injected into.
+	Dependents []Injection
+}
+
+// Injection describes assignment of the object Provider to the field Field
+// of the object Consumer made by BuildDependencies.
+type Injection struct {
+	Consumer string
+	Field    string
+	Provider string
+}
+
+// Objects returns description of containered objects in the order they've
+// been added into container.
+func (c *SimpleContainer) Objects() []ObjectInfo {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	res := make([]ObjectInfo, len(c.objects))
+	for i := range c.objects {
+		res[i] = c.objectInfo(i)
+	}
+
+	for _, d := range c.deps {
+		if d.explicit {
+			continue
+		}
+		in := Injection{
+			Consumer: c.nameOf(d.consumer),
+			Field:    d.field,
+			Provider: c.nameOf(d.provider),
+		}
+		res[d.consumer].Dependencies = append(res[d.consumer].Dependencies, in)
+		res[d.provider].Dependents = append(res[d.provider].Dependents, in)
+	}
+	return res
+}
+
+// objectInfo returns description of the object at position i without
+// dependencies. The caller must hold read lock.
+func (c *SimpleContainer) objectInfo(i int) ObjectInfo {
+	o := c.objects[i]
+	oi := ObjectInfo{
+		Name:   c.nameOf(i),
+		Module: c.regs[i].module,
+		Group:  c.regs[i].group,
+		Type:   reflect.TypeOf(o),
+		Object: o,
+	}
+	_, oi.Initializer = o.(Initializer)
+	_, oi.Runner = o.(Runner)
+	_, oi.Stopper = o.(Stopper)
+	_, oi.Globalizer = o.(Globalizer)
+	if i < len(c.states) {
+		oi.Running = c.states[i].running
+		oi.Restarts = c.states[i].restarts
+		oi.LastError = c.states[i].lastErr
+	}
+	return oi
+}
+
+// Unused returns description of containered objects which are not
+// injected into other objects, including DependsOn declarations, and
+// implement neither Initializer nor Runner. Such objects are often dead
+// registrations left after refactoring.
+//
+// Only injections made by BuildDependencies of the container are
+// considered, objects used by child containers or obtained by Resolve
+// are reported as unused.
+func (c *SimpleContainer) Unused() []ObjectInfo {
+	objects := c.Objects()
+
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	var res []ObjectInfo
+	for i, oi := range objects {
+		if !c.used(i) {
+			res = append(res, oi)
+		}
+	}
+	return res
+}
+
+// used returns false if the object at position i is not injected into
+// other objects and implements neither Initializer nor Runner. The caller
+// must hold read lock.
+func (c *SimpleContainer) used(i int) bool {
+	switch c.objects[i].(type) {
+	case Initializer, Runner:
+		return true
+	}
+	for _, d := range c.deps {
+		if d.provider == i {
+			return true
+		}
+	}
+	return false
+}
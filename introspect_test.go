@@ -0,0 +1,52 @@
+package sdi_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestObjects(t *testing.T) {
+	cs := sdi.New()
+	a := A{}
+	cs.Add(&a, &B{}, new(G))
+	cs.BuildDependencies()
+
+	objs := cs.Objects()
+	if len(objs) != 3 {
+		t.Fatalf("expected 3 objects, got %d", len(objs))
+	}
+
+	oa := objs[0]
+	if oa.Object != &a || oa.Type != reflect.TypeOf(&a) || oa.Name != "*sdi_test.A" {
+		t.Errorf("unexpected object info %+v", oa)
+	}
+	if !oa.Initializer || !oa.Runner || oa.Stopper || oa.Globalizer {
+		t.Errorf("unexpected lifecycle flags %+v", oa)
+	}
+
+	in := sdi.Injection{Consumer: "*sdi_test.B", Field: "AService", Provider: "*sdi_test.A"}
+	if !reflect.DeepEqual(oa.Dependents, []sdi.Injection{in}) {
+		t.Errorf("unexpected dependents %v", oa.Dependents)
+	}
+	if !reflect.DeepEqual(objs[1].Dependencies, []sdi.Injection{in}) {
+		t.Errorf("unexpected dependencies %v", objs[1].Dependencies)
+	}
+	if objs[2].Runner || !objs[2].Initializer {
+		t.Errorf("unexpected lifecycle flags %+v", objs[2])
+	}
+}
+
+func TestUnused(t *testing.T) {
+	cs := sdi.New()
+	cs.AddNamed("used", &barrierNode{})
+	cs.AddNamed("consumer", &pingConsumer{})
+	cs.AddNamed("dead", &eventBus{})
+	cs.BuildDependencies()
+
+	unused := cs.Unused()
+	if len(unused) != 1 || unused[0].Name != "dead" {
+		t.Errorf("expected dead object only, got %v", unused)
+	}
+}
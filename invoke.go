@@ -0,0 +1,52 @@
+package sdi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Invoke calls function fn with arguments resolved the same way as by
+// Resolve. Parameters of type Container or *SimpleContainer get
+// the container itself. Function fn returns nothing or error, the error
+// is returned by Invoke.
+//
+//	err := c.Invoke(func(db *sql.DB, log Logger) error {
+//		return migrate(db, log)
+//	})
+//
+// Invoke returns error if fn is not a function, an argument can not be
+// resolved or fn fails.
+func (c *SimpleContainer) Invoke(fn interface{}) error {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return fmt.Errorf("sdi: %T is not a function", fn)
+	}
+	ft := v.Type()
+	if n := ft.NumOut(); n > 1 || n == 1 && ft.Out(0) != errorType {
+		return fmt.Errorf("sdi: %s must return nothing or error", ft)
+	}
+
+	args := make([]reflect.Value, ft.NumIn())
+	for n := range args {
+		t := ft.In(n)
+		if ft.IsVariadic() && n == len(args)-1 {
+			args = args[:n]
+			break
+		}
+		if t == containerType || t == reflect.TypeOf(c) {
+			args[n] = reflect.ValueOf(c)
+			continue
+		}
+		o, err := c.resolve(t)
+		if err != nil {
+			return fmt.Errorf("sdi: %s: parameter %d: %w", ft, n, err)
+		}
+		args[n] = reflect.ValueOf(o)
+	}
+
+	out := v.Call(args)
+	if len(out) == 1 && !out[0].IsNil() {
+		return out[0].Interface().(error)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package sdi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestInvoke(t *testing.T) {
+	cs := sdi.New()
+	a, c := &A{age: 7}, &C{gender: "f"}
+	cs.Add(a, c)
+	cs.BuildDependencies()
+
+	var called bool
+	err := cs.Invoke(func(ai AI, ci CI, container sdi.Container) {
+		called = ai == a && ci == c && container == cs
+	})
+	if err != nil || !called {
+		t.Errorf("expected call with resolved arguments, got %v", err)
+	}
+
+	errMigrate := errors.New("migration failed")
+	if err := cs.Invoke(func(AI) error { return errMigrate }); err != errMigrate {
+		t.Errorf("expected %v, got %v", errMigrate, err)
+	}
+}
+
+func TestInvokeErrors(t *testing.T) {
+	cs := sdi.New()
+	cs.Add(&A{})
+	cs.BuildDependencies()
+
+	if err := cs.Invoke(func(Client) {}); !errors.Is(err, sdi.ErrUnresolvedDependency) {
+		t.Errorf("expected %v, got %v", sdi.ErrUnresolvedDependency, err)
+	}
+	for _, fn := range []interface{}{nil, 42, func() int { return 0 }} {
+		if err := cs.Invoke(fn); err == nil {
+			t.Errorf("expected error for %T", fn)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package sdi
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// ProfileLabel is the pprof label set to the name of the runner on
+// goroutines calling its Start, supervising it or waiting for its gate.
+// Goroutines started by Start inherit the label and the context passed to
+// Start carries it, so CPU and goroutine profiles attribute work to
+// the runner:
+//
+//	go tool pprof -tagfocus=service=api http://localhost:6060/debug/pprof/profile
+const ProfileLabel = "service"
+
+// labeled returns ctx labeled by the name of the object at position i and
+// sets its labels on the current goroutine. The returned function restores
+// labels of ctx on the goroutine.
+func (c *SimpleContainer) labeled(ctx context.Context, i int) (context.Context, func()) {
+	lctx := pprof.WithLabels(ctx, pprof.Labels(ProfileLabel, c.nameOf(i)))
+	pprof.SetGoroutineLabels(lctx)
+	return lctx, func() { pprof.SetGoroutineLabels(ctx) }
+}
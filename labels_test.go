@@ -0,0 +1,51 @@
+package sdi_test
+
+import (
+	"context"
+	"runtime/pprof"
+	"sync"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type labeledRunner struct {
+	mux   sync.Mutex
+	label string
+}
+
+func (r *labeledRunner) Start(ctx context.Context) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.label, _ = pprof.Label(ctx, sdi.ProfileLabel)
+	return nil
+}
+
+func (r *labeledRunner) Label() string {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.label
+}
+
+func TestProfileLabels(t *testing.T) {
+	for _, opts := range [][]sdi.Option{nil, {sdi.WithManagedRunners()}} {
+		ctx := context.Background()
+		api, worker := &labeledRunner{}, &labeledRunner{}
+		cs := sdi.New(opts...)
+		cs.AddNamed("api", api)
+		cs.AddNamed("worker", worker)
+		cs.BuildDependencies()
+		cs.InitRequired(ctx)
+		if err := cs.StartRunners(ctx); err != nil {
+			t.Fatal(err)
+		}
+		waitFor(t, func() bool { return api.Label() != "" && worker.Label() != "" })
+		if api.Label() != "api" || worker.Label() != "worker" {
+			t.Errorf("expected labels api and worker, got %q and %q", api.Label(), worker.Label())
+		}
+		if _, ok := pprof.Label(ctx, sdi.ProfileLabel); ok {
+			t.Error("expected context of the caller not to be labeled")
+		}
+		cs.Stop(ctx)
+	}
+}
@@ -0,0 +1,237 @@
+package sdi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// AddLate adds object o into container after BuildDependencies, e.g. a
+// component discovered by a plugin system while the application is
+// running.
+//
+// Fields of o are injected from objects already containered, the same way
+// as by BuildDependencies. Then nil fields of containered objects, which
+// o is assignable to, are set to o, and o is appended to slice fields
+// filled by the container or left empty. Fields tagged with qualifier,
+// env or ctx are not set.
+//
+// The object catches up with the container: if InitRequired has been
+// called, fields of o tagged with ctx get values carried by ctx and Init
+// of o is called; if StartRunners has been called, its Start is called
+// as well, in a separate goroutine if o implements BlockingRunner or
+// the container created with WithManagedRunners or WithSupervision
+// option. If Init or Start fails, the object stays in the container and
+// is stopped by Stop.
+//
+// AddLate returns error and leaves container intact if a dependency of o
+// with policy FailOnUnresolved can't be resolved. It must be called after
+// BuildDependencies and before Stop, otherwise error wrapping
+// ErrInvalidState is returned. It panics in the same cases as Add.
+func (c *SimpleContainer) AddLate(ctx context.Context, o interface{}) error {
+	mustBeContainerable(o)
+	if err := c.begin("AddLate", StateBuilt, StateInitialized, StateStarted); err != nil {
+		return err
+	}
+	state := c.State()
+
+	i, nc, err := c.wireLate(o)
+	if err != nil {
+		return c.end(state, err)
+	}
+	return c.end(state, c.catchUp(ctx, i, nc, state))
+}
+
+// wireLate appends object o, injects its dependencies and sets nil fields
+// of other objects o is assignable to. It returns position of o and
+// the number of fields tagged with ctx before o was added.
+func (c *SimpleContainer) wireLate(o interface{}) (int, int, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	defer func() { c.cache = nil }()
+
+	if c.indexOf(o) >= 0 {
+		return 0, 0, fmt.Errorf("sdi: %T added already", o)
+	}
+
+	nd, nm, nc := len(c.deps), len(c.missing), len(c.ctxFields)
+	c.cache = nil
+	c.objects = append(c.objects, o)
+	c.regs = append(c.regs, registration{})
+	i := len(c.objects) - 1
+
+	err := c.inject(i)
+	if err == nil {
+		err = c.checkMissing(c.missing[nm:])
+	}
+	if err == nil {
+		if ai, ok := o.(AfterInjector); ok {
+			if err = ai.AfterInject(); err != nil {
+				err = fmt.Errorf("sdi: %s.AfterInject: %w", c.nameOf(i), err)
+			}
+		}
+	}
+	if err != nil {
+		c.objects = c.objects[:i]
+		c.regs = c.regs[:i]
+		c.deps = c.deps[:nd]
+		c.missing = c.missing[:nm]
+		c.ctxFields = c.ctxFields[:nc]
+		return 0, 0, err
+	}
+
+	c.fill(i)
+	c.order = append(c.order, i)
+	c.startOrder = append(c.startOrder, i)
+	c.stopOrder = c.reverseOrder(c.startOrder)
+	c.emit(Event{Type: ObjectAdded, Object: c.nameOf(i)})
+	return i, nc, nil
+}
+
+// fill sets nil fields of containered objects, which the object at
+// position i is assignable to, to the object.
+func (c *SimpleContainer) fill(i int) {
+	ot := reflect.TypeOf(c.objects[i])
+	for k := 0; k < i; k++ {
+		if c.regs[k].constructed {
+			continue
+		}
+		refs := []interface{}{c.objects[k]}
+		if pa, ok := c.objects[k].(Privater); ok {
+			refs = append(refs, pa.Private())
+		}
+		if mp, ok := c.objects[k].(MultiPrivater); ok {
+			refs = append(refs, mp.Privates()...)
+		}
+		for _, ref := range refs {
+			s := reflect.ValueOf(ref)
+			if s.Kind() == reflect.Ptr && !s.IsNil() && s.Elem().Kind() == reflect.Struct {
+				c.fillFields(k, s.Elem(), "", i, ot)
+			}
+		}
+	}
+}
+
+// fillFields sets nil fields of the struct sv of the object at position
+// pos, and structs embedded into it, to the object at position i of type
+// ot. It traverses fields the same way as setFields.
+func (c *SimpleContainer) fillFields(pos int, sv reflect.Value, prefix string, i int, ot reflect.Type) {
+	t := sv.Type()
+	for f := 0; f < t.NumField(); f++ {
+		sf := t.Field(f)
+		fs := sv.Field(f)
+		ft := fs.Type()
+		name := prefix + sf.Name
+
+		if sf.Anonymous {
+			if ft.Kind() == reflect.Ptr && ft.Elem().Kind() == reflect.Struct && !fs.IsNil() {
+				c.fillFields(pos, fs.Elem(), name+".", i, ot)
+				continue
+			}
+			if ft.Kind() == reflect.Struct {
+				c.fillFields(pos, fs, name+".", i, ot)
+				continue
+			}
+		}
+		if !fs.CanSet() {
+			var ok bool
+			if fs, ok = c.unexportedField(pos, sf, fs); !ok {
+				continue
+			}
+		}
+
+		opts := parseTag(sf.Tag.Get(tagName))
+		if _, ok := opts["env"]; ok {
+			continue
+		}
+		if _, ok := opts["ctx"]; ok {
+			continue
+		}
+		if _, ok := opts["qualifier"]; ok {
+			continue
+		}
+		if _, ok := fs.Addr().Interface().(lazyInjectable); ok {
+			continue
+		}
+		if _, inject := opts["inject"]; ft.Kind() == reflect.Struct && (ft.Name() == "" || inject) {
+			c.fillFields(pos, fs, name+".", i, ot)
+			continue
+		}
+
+		if ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.Interface {
+			if c.isAssignable(ot, ft.Elem()) && (fs.Len() == 0 || c.injected(pos, name)) {
+				fs.Set(reflect.Append(fs, c.provide(ft.Elem(), i)))
+				c.deps = append(c.deps, dependency{consumer: pos, provider: i, field: name, target: fs})
+			}
+			continue
+		}
+		if ft.Kind() != reflect.Interface && ft.Kind() != reflect.Ptr && ft.Kind() != reflect.Func {
+			continue
+		}
+		if !fs.IsNil() || !c.isAssignable(ot, ft) {
+			continue
+		}
+		fs.Set(c.provide(ft, i))
+		c.deps = append(c.deps, dependency{consumer: pos, provider: i, field: name, target: fs})
+		c.resolved(pos, name)
+	}
+}
+
+// injected reports whether field of the object at position pos has been
+// set by the container.
+func (c *SimpleContainer) injected(pos int, field string) bool {
+	for _, d := range c.deps {
+		if d.consumer == pos && d.field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// resolved forgets field of the object at position pos recorded as
+// missing.
+func (c *SimpleContainer) resolved(pos int, field string) {
+	for k := range c.missing {
+		if c.missing[k].consumer == pos && c.missing[k].field == field {
+			c.missing = append(c.missing[:k], c.missing[k+1:]...)
+			return
+		}
+	}
+}
+
+// catchUp initializes and starts the object at position i added by
+// AddLate if the container is in state initialized or started. Fields
+// tagged with ctx from position nc belong to the object.
+func (c *SimpleContainer) catchUp(ctx context.Context, i, nc int, state State) error {
+	if state == StateBuilt {
+		return nil
+	}
+
+	c.mux.RLock()
+	fields := c.ctxFields[nc:]
+	c.mux.RUnlock()
+	if err := c.setContextValues(ctx, fields); err != nil {
+		return err
+	}
+	if s, ok := c.objects[i].(Initializer); ok {
+		if err := c.initObject(ctx, i, s); err != nil {
+			return err
+		}
+	}
+	if state != StateStarted {
+		return nil
+	}
+
+	r, ok := c.objects[i].(Runner)
+	if !ok {
+		return nil
+	}
+	if c.opts.managed || c.blocking(i) {
+		c.startBlocking(ctx, i, r)
+		return nil
+	}
+	if err := c.startObject(ctx, i, r); err != nil {
+		return &StartError{Object: c.nameOf(i), Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+// lateReporter is a runner depending on Pinger.
+type lateReporter struct {
+	journaled
+	Pool Pinger
+}
+
+func TestAddLate(t *testing.T) {
+	var jn journal
+	ctx := context.Background()
+	api := &journaledAPI{journaled: journaled{name: "api", journal: &jn}}
+
+	cs := sdi.New()
+	cs.Add(api)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	pool := &journaledPool{journaled{name: "pool", journal: &jn}}
+	if err := cs.AddLate(ctx, pool); err != nil {
+		t.Fatal(err)
+	}
+	if api.Pool != pool {
+		t.Errorf("expected nil field set to late object, got %v", api.Pool)
+	}
+
+	reporter := &lateReporter{journaled: journaled{name: "reporter", journal: &jn}}
+	if err := cs.AddLate(ctx, reporter); err != nil {
+		t.Fatal(err)
+	}
+	if reporter.Pool != pool {
+		t.Errorf("expected late object injected, got %v", reporter.Pool)
+	}
+	if err := cs.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"init api", "start api",
+		"init pool", "start pool",
+		"init reporter", "start reporter",
+		"stop reporter", "stop api", "stop pool",
+	}
+	if !reflect.DeepEqual(jn.calls, expected) {
+		t.Errorf("expected %v, got %v", expected, jn.calls)
+	}
+	if s := cs.State(); s != sdi.StateStopped {
+		t.Errorf("expected %s, got %s", sdi.StateStopped, s)
+	}
+}
+
+func TestAddLateUnresolved(t *testing.T) {
+	cs := sdi.New(sdi.WithUnresolvedPolicy(sdi.FailOnUnresolved))
+	cs.Add(&A{})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	err := cs.AddLate(context.Background(), &lateReporter{journaled: journaled{journal: &journal{}}})
+	if !errors.Is(err, sdi.ErrUnresolvedDependency) {
+		t.Errorf("expected %v, got %v", sdi.ErrUnresolvedDependency, err)
+	}
+	if n := len(cs.Objects()); n != 1 {
+		t.Errorf("expected container intact, got %d objects", n)
+	}
+}
+
+func TestAddLateState(t *testing.T) {
+	cs := sdi.New()
+	if err := cs.AddLate(context.Background(), &A{}); !errors.Is(err, sdi.ErrInvalidState) {
+		t.Errorf("expected %v, got %v", sdi.ErrInvalidState, err)
+	}
+}
+
+type clientList struct {
+	Clients []Client
+}
+
+func (l *clientList) Global() {}
+
+func TestAddLateSlice(t *testing.T) {
+	ctx := context.Background()
+	gw := &gateway{}
+	own := &clientList{Clients: []Client{&client{name: "own"}}}
+	cs := sdi.New()
+	cs.Add(gw, own, &client{name: "a"})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.AddLate(ctx, &client{name: "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, cl := range gw.Clients {
+		names = append(names, cl.Call())
+	}
+	if expected := []string{"a", "b"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+	if len(own.Clients) != 1 {
+		t.Errorf("expected pre-set slice intact, got %d clients", len(own.Clients))
+	}
+}
@@ -0,0 +1,65 @@
+package sdi
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Lazy defers resolution of a dependency of type T until the first call
+// of Get or Resolve. Declare an exported field of type Lazy[T], the
+// container sets resolver into it in BuildDependencies:
+//
+//	type B struct {
+//		A sdi.Lazy[AI]
+//	}
+//
+//	func (b *B) Start(ctx context.Context) error {
+//		fmt.Println(b.A.Get().Age())
+//		return nil
+//	}
+//
+// Lazy dependencies are not taken into account in dependency ordering,
+// therefore they can be used by objects referencing each other.
+type Lazy[T any] struct {
+	once sync.Once
+	r    resolver
+	v    T
+	err  error
+}
+
+// lazyInjectable is implemented by Lazy.
+type lazyInjectable interface {
+	setResolver(resolver)
+}
+
+func (l *Lazy[T]) setResolver(r resolver) {
+	l.r = r
+}
+
+// Resolve returns the dependency resolving it on the first call.
+func (l *Lazy[T]) Resolve() (T, error) {
+	l.once.Do(func() {
+		t := reflect.TypeOf((*T)(nil)).Elem()
+		if l.r == nil {
+			l.err = fmt.Errorf("sdi: lazy %s is not injected", t)
+			return
+		}
+		o, err := l.r.resolve(t)
+		if err != nil {
+			l.err = err
+			return
+		}
+		l.v = o.(T)
+	})
+	return l.v, l.err
+}
+
+// Get is like Resolve but panics if the dependency can not be resolved.
+func (l *Lazy[T]) Get() T {
+	v, err := l.Resolve()
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
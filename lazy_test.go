@@ -0,0 +1,47 @@
+package sdi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type pingPong struct {
+	ready bool
+}
+
+func (p *pingPong) Init(ctx context.Context) error {
+	p.ready = true
+	return nil
+}
+
+func (p *pingPong) Ping() bool {
+	return p.ready
+}
+
+type lazyConsumer struct {
+	Pinger sdi.Lazy[Pinger]
+	Absent sdi.Lazy[BI]
+}
+
+func (l *lazyConsumer) Global() {}
+
+func TestLazy(t *testing.T) {
+	cs := sdi.New(sdi.WithParallelInit())
+	lc := lazyConsumer{}
+	pp := pingPong{}
+	cs.Add(&lc, &pp)
+	cs.BuildDependencies()
+
+	if err := cs.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !lc.Pinger.Get().Ping() {
+		t.Error("expected initialized dependency")
+	}
+	if _, err := lc.Absent.Resolve(); err == nil {
+		t.Error("expected error resolving absent dependency")
+	}
+}
@@ -0,0 +1,69 @@
+package sdi
+
+import (
+	"errors"
+	"log"
+	"time"
+)
+
+// Logger is the interface of loggers used by the container.
+// *log.Logger implements it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// logObserver logs Init and Start of containered objects.
+type logObserver struct {
+	l Logger
+}
+
+func (lo logObserver) BeforeInit(obj interface{}) {}
+
+func (lo logObserver) AfterInit(obj interface{}, err error, d time.Duration) {
+	if err != nil {
+		lo.l.Printf("sdi: %T init failed in %s: %v", obj, d, err)
+		return
+	}
+	lo.l.Printf("sdi: %T initialized in %s", obj, d)
+}
+
+func (lo logObserver) BeforeStart(obj interface{}) {}
+
+func (lo logObserver) AfterStart(obj interface{}, err error, d time.Duration) {
+	if err != nil {
+		lo.l.Printf("sdi: %T start failed in %s: %v", obj, d, err)
+		return
+	}
+	lo.l.Printf("sdi: %T started in %s", obj, d)
+}
+
+// logf logs the message if the container has logger.
+func (c *SimpleContainer) logf(format string, v ...interface{}) {
+	if c.opts.logger != nil {
+		c.opts.logger.Printf(format, v...)
+	}
+}
+
+// printf writes to the logger set by WithLogger or, if there is none, to
+// the standard logger. It's used for diagnostics users should not miss.
+func (c *SimpleContainer) printf(format string, v ...interface{}) {
+	if c.opts.logger != nil {
+		c.opts.logger.Printf(format, v...)
+		return
+	}
+	log.Printf(format, v...)
+}
+
+// checkMissing logs fields left nil, described by missing. It returns error
+// wrapping ErrUnresolvedDependency for each of them having policy
+// FailOnUnresolved, see unresolvedError. The caller must hold write lock.
+func (c *SimpleContainer) checkMissing(missing []dependency) error {
+	var errs []error
+	for _, m := range missing {
+		c.logf("sdi: %s.%s is not injected", c.nameOf(m.consumer), m.field)
+		if m.policy == FailOnUnresolved {
+			errs = append(errs, c.unresolvedError(m))
+		}
+	}
+	return errors.Join(errs...)
+}
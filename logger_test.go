@@ -0,0 +1,40 @@
+package sdi_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	cs := sdi.New(sdi.WithLogger(log.New(&buf, "", 0)))
+	cs.Add(&eventBus{}, &initCounter{Dep: &barrierNode{}})
+	cs.BuildDependencies()
+	if err := cs.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		"sdi: *sdi_test.eventBus.Handle is not injected",
+		"sdi: *sdi_test.initCounter initialized in",
+	} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("expected log to contain %q:\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestWithStrictMode(t *testing.T) {
+	cs := sdi.New(sdi.WithStrictMode())
+	cs.Add(&eventBus{})
+	err := cs.Build()
+	if !errors.Is(err, sdi.ErrUnresolvedDependency) || !strings.Contains(err.Error(), "eventBus.Handle") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package sdi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// LoggerFactory registers factory f of loggers of type L. Every field and
+// setter of type L gets its own logger created by f with the name of
+// the consuming object, registration name or type if the object is added
+// without name, instead of a containered object:
+//
+//	sdi.LoggerFactory(c, func(consumer string) *slog.Logger {
+//		return slog.Default().With("component", consumer)
+//	})
+//
+// Factory must be registered before BuildDependencies.
+func LoggerFactory[L any](c *SimpleContainer, f func(consumer string) L) {
+	c.LoggerFactory((*L)(nil), func(consumer string) interface{} {
+		return f(consumer)
+	})
+}
+
+// LoggerFactory is the non-generic form of function LoggerFactory.
+// Parameter iface is a pointer to the logger type, e.g. (*Logger)(nil).
+// Loggers returned by f must be assignable to the type. It panics if
+// BuildDependencies has been called already.
+func (c *SimpleContainer) LoggerFactory(iface interface{}, f func(consumer string) interface{}) {
+	pt := reflect.TypeOf(iface)
+	if pt == nil || pt.Kind() != reflect.Ptr {
+		panic("sdi: iface must be a pointer to a type")
+	}
+	t := pt.Elem()
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.built {
+		panic(fmt.Sprintf("sdi: logger factory of %s registered after BuildDependencies", t))
+	}
+	if c.loggers == nil {
+		c.loggers = make(map[reflect.Type]func(string) interface{})
+	}
+	c.loggers[t] = f
+}
+
+// logger returns logger of type t created for the object at position pos.
+// It returns false if there is no factory of such loggers.
+func (c *SimpleContainer) logger(pos int, t reflect.Type) (reflect.Value, bool, error) {
+	f, ok := c.loggers[t]
+	if !ok {
+		return reflect.Value{}, false, nil
+	}
+	l := f(c.nameOf(pos))
+	if l == nil || !reflect.TypeOf(l).AssignableTo(t) {
+		return reflect.Value{}, false, fmt.Errorf("sdi: logger factory returned %T not assignable to %s", l, t)
+	}
+	return reflect.ValueOf(l), true, nil
+}
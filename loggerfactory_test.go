@@ -0,0 +1,56 @@
+package sdi_test
+
+import (
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type ComponentLogger interface {
+	Component() string
+}
+
+type taggedLogger struct {
+	component string
+}
+
+func (tl *taggedLogger) Component() string {
+	return tl.component
+}
+
+type orders struct {
+	Log ComponentLogger
+}
+
+func (o *orders) Global() {}
+
+type payments struct {
+	log ComponentLogger
+}
+
+func (p *payments) Global() {}
+
+func (p *payments) SetLogger(l ComponentLogger) {
+	p.log = l
+}
+
+func TestLoggerFactory(t *testing.T) {
+	o := orders{}
+	p := payments{}
+	cs := sdi.New()
+	sdi.LoggerFactory(cs, func(consumer string) ComponentLogger {
+		return &taggedLogger{component: consumer}
+	})
+	cs.Add(&o)
+	cs.AddNamed("payments", &p)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	if o.Log == nil || o.Log.Component() != "*sdi_test.orders" {
+		t.Errorf("unexpected logger %+v", o.Log)
+	}
+	if p.log == nil || p.log.Component() != "payments" {
+		t.Errorf("unexpected logger %+v", p.log)
+	}
+}
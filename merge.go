@@ -0,0 +1,115 @@
+package sdi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Merge adds objects of container other into c, after objects of c,
+// keeping their names and modules. Bindings, logger factories,
+// decorators, transient factories, constructors and gates of other are
+// merged as well, decorators of other are applied after decorators of c.
+// Runners of other waiting for a gate named the same as a gate of c wait
+// for the gate of c.
+//
+// Merge returns error and leaves c intact if either container is built,
+// an object is added into both containers, names or modules of objects
+// conflict, the same type is bound in both containers, or both
+// containers have logger factory of the same type. Container other must
+// not be used after Merge.
+func (c *SimpleContainer) Merge(other *SimpleContainer) error {
+	if other == c {
+		return errors.New("sdi: container merged into itself")
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	other.mux.RLock()
+	defer other.mux.RUnlock()
+
+	if c.built || other.built {
+		return fmt.Errorf("%w: Merge called after BuildDependencies", ErrInvalidState)
+	}
+	if err := c.checkMerge(other); err != nil {
+		return err
+	}
+
+	gates := make(map[*Gate]*Gate)
+	for name, g := range other.gates {
+		if cg, ok := c.gates[name]; ok {
+			gates[g] = cg
+			continue
+		}
+		if c.gates == nil {
+			c.gates = make(map[string]*Gate)
+		}
+		c.gates[name] = g
+	}
+	for i, o := range other.objects {
+		reg := other.regs[i]
+		if cg, ok := gates[reg.gate]; ok {
+			reg.gate = cg
+		}
+		c.add(o, reg)
+	}
+	c.transients = append(c.transients, other.transients...)
+	c.providers = append(c.providers, other.providers...)
+	for t, impl := range other.bindings {
+		if c.bindings == nil {
+			c.bindings = make(map[reflect.Type]interface{})
+		}
+		c.bindings[t] = impl
+	}
+	for t, f := range other.loggers {
+		if c.loggers == nil {
+			c.loggers = make(map[reflect.Type]func(string) interface{})
+		}
+		c.loggers[t] = f
+	}
+	for t, ds := range other.decorators {
+		if c.decorators == nil {
+			c.decorators = make(map[reflect.Type][]reflect.Value)
+		}
+		c.decorators[t] = append(c.decorators[t], ds...)
+	}
+	return nil
+}
+
+// checkMerge returns error if registrations of other conflict with
+// registrations of c.
+func (c *SimpleContainer) checkMerge(other *SimpleContainer) error {
+	var errs []error
+	names, modules := make(map[string]bool), make(map[string]bool)
+	for i := range c.regs {
+		if n := c.regs[i].name; n != "" {
+			names[n] = true
+		}
+		if m := c.regs[i].module; m != "" {
+			modules[m] = true
+		}
+	}
+	for i, o := range other.objects {
+		if c.indexOf(o) >= 0 {
+			errs = append(errs, fmt.Errorf("sdi: %s added into both containers", other.nameOf(i)))
+		}
+		if n := other.regs[i].name; names[n] {
+			errs = append(errs, fmt.Errorf("sdi: object name %q used in both containers", n))
+		}
+		if m := other.regs[i].module; modules[m] {
+			errs = append(errs, fmt.Errorf("sdi: module %q added into both containers", m))
+			delete(modules, m)
+		}
+	}
+	for t, impl := range other.bindings {
+		if b, ok := c.bindings[t]; ok {
+			errs = append(errs, fmt.Errorf("sdi: %s bound to %T and %T", t, b, impl))
+		}
+	}
+	for t := range other.loggers {
+		if _, ok := c.loggers[t]; ok {
+			errs = append(errs, fmt.Errorf("sdi: logger factory of %s registered in both containers", t))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,68 @@
+package sdi_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestMerge(t *testing.T) {
+	billing := sdi.New()
+	billing.AddModule(sdi.NewModule("billing", &C{}))
+	users := sdi.New()
+	users.AddNamed("users", &B{})
+	users.Add(&A{})
+
+	cs := sdi.New()
+	if err := cs.Merge(billing); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.Merge(users); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	objs := cs.Objects()
+	if len(objs) != 3 || objs[0].Module != "billing" || objs[1].Name != "users" {
+		t.Fatalf("unexpected objects %+v", objs)
+	}
+	if b := objs[1].Object.(*B); b.AService == nil || b.CService == nil {
+		t.Error("expected objects of merged containers to be wired")
+	}
+}
+
+func TestMergeConflicts(t *testing.T) {
+	a := &A{}
+	cs := sdi.New()
+	cs.AddNamed("users", &B{})
+	cs.Add(a)
+
+	other := sdi.New()
+	other.AddNamed("users", &B{})
+	other.Add(a, &C{})
+
+	err := cs.Merge(other)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, s := range []string{`name "users"`, "*sdi_test.A added into both"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("expected %q in %v", s, err)
+		}
+	}
+	if n := len(cs.Objects()); n != 2 {
+		t.Errorf("expected container to be intact, got %d objects", n)
+	}
+
+	if err := cs.Merge(cs); err == nil {
+		t.Error("expected error on merge into itself")
+	}
+	cs.BuildDependencies()
+	if err := cs.Merge(sdi.New()); !errors.Is(err, sdi.ErrInvalidState) {
+		t.Errorf("expected %v, got %v", sdi.ErrInvalidState, err)
+	}
+}
@@ -0,0 +1,63 @@
+package sdi
+
+import "fmt"
+
+// Module is a named bundle of objects added into container at once.
+// Modules let packages define their own part of the wiring
+// (storage, http, etc.) and compose them in main().
+type Module struct {
+	name    string
+	objects []interface{}
+	modules []*Module
+}
+
+// NewModule returns module with the name holding objects o.
+func NewModule(name string, o ...interface{}) *Module {
+	return &Module{name: name, objects: o}
+}
+
+// Name returns module name.
+func (m *Module) Name() string {
+	return m.name
+}
+
+// Add adds objects into module.
+func (m *Module) Add(o ...interface{}) *Module {
+	m.objects = append(m.objects, o...)
+	return m
+}
+
+// Include adds submodules into module. Submodules are added into
+// container after objects of the module.
+func (m *Module) Include(sub ...*Module) *Module {
+	m.modules = append(m.modules, sub...)
+	return m
+}
+
+// AddModule adds objects of modules and their submodules into container.
+// It panics if the module name is empty or a module with the same name
+// has been added already, and in the same cases as Add.
+func (c *SimpleContainer) AddModule(ms ...*Module) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.addModules(ms)
+}
+
+func (c *SimpleContainer) addModules(ms []*Module) {
+	for _, m := range ms {
+		if m.name == "" {
+			panic("sdi: empty module name")
+		}
+		for i := range c.regs {
+			if c.regs[i].module == m.name {
+				panic(fmt.Sprintf("sdi: module %q already added", m.name))
+			}
+		}
+		for _, o := range m.objects {
+			mustBeContainerable(o)
+			c.add(o, registration{module: m.name})
+		}
+		c.addModules(m.modules)
+	}
+}
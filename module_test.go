@@ -0,0 +1,35 @@
+package sdi_test
+
+import (
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestAddModule(t *testing.T) {
+	storage := sdi.NewModule("storage", &A{}).Add(&C{})
+	api := sdi.NewModule("api", &B{}).Include(storage)
+
+	cs := sdi.New()
+	cs.AddModule(api)
+	cs.BuildDependencies()
+
+	objs := cs.Objects()
+	if len(objs) != 3 {
+		t.Fatalf("expected 3 objects, got %d", len(objs))
+	}
+	modules := []string{objs[0].Module, objs[1].Module, objs[2].Module}
+	if modules[0] != "api" || modules[1] != "storage" || modules[2] != "storage" {
+		t.Errorf("unexpected modules %v", modules)
+	}
+	if b := objs[0].Object.(*B); b.AService == nil || b.CService == nil {
+		t.Error("expected module objects to be wired")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on duplicate module")
+		}
+	}()
+	cs.AddModule(storage)
+}
@@ -0,0 +1,43 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type replica struct {
+	region string
+	err    error
+}
+
+func (r *replica) ContainerName() string          { return "replica-" + r.region }
+func (r *replica) Init(ctx context.Context) error { return r.err }
+
+func TestNamer(t *testing.T) {
+	cs := sdi.New()
+	cs.Add(&replica{region: "eu"}, &replica{region: "us", err: errors.New("refused")})
+	cs.AddNamed("primary", &replica{region: "local"})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, n := range cs.Graph().Nodes {
+		names = append(names, n.Name)
+	}
+	if got := strings.Join(names, ","); got != "replica-eu,replica-us,primary" {
+		t.Errorf("unexpected node names %s", got)
+	}
+
+	err := cs.InitRequired(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "init replica-us: refused") {
+		t.Errorf("expected error naming the object, got %v", err)
+	}
+	if _, ok := cs.Get("replica-eu"); ok {
+		t.Error("expected name returned by Namer not to be a qualifier")
+	}
+}
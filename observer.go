@@ -0,0 +1,60 @@
+package sdi
+
+import "time"
+
+// Observer is the interface that wraps callbacks invoked by the container
+// around Init and Start of each containered object.
+//
+// BeforeInit and AfterInit are invocated inside InitRequired before and
+// after Init of the object. AfterInit receives error returned by Init
+// and its duration.
+//
+// BeforeStart and AfterStart are invocated inside StartRunners in
+// the same way around Start.
+//
+// Callbacks are called concurrently if the container inits or starts
+// objects concurrently.
+type Observer interface {
+	BeforeInit(obj interface{})
+	AfterInit(obj interface{}, err error, d time.Duration)
+	BeforeStart(obj interface{})
+	AfterStart(obj interface{}, err error, d time.Duration)
+}
+
+// ObserverFuncs implements Observer calling not nil functions.
+type ObserverFuncs struct {
+	BeforeInitFunc  func(obj interface{})
+	AfterInitFunc   func(obj interface{}, err error, d time.Duration)
+	BeforeStartFunc func(obj interface{})
+	AfterStartFunc  func(obj interface{}, err error, d time.Duration)
+}
+
+var _ Observer = ObserverFuncs{}
+
+// BeforeInit implements Observer interface.
+func (of ObserverFuncs) BeforeInit(obj interface{}) {
+	if of.BeforeInitFunc != nil {
+		of.BeforeInitFunc(obj)
+	}
+}
+
+// AfterInit implements Observer interface.
+func (of ObserverFuncs) AfterInit(obj interface{}, err error, d time.Duration) {
+	if of.AfterInitFunc != nil {
+		of.AfterInitFunc(obj, err, d)
+	}
+}
+
+// BeforeStart implements Observer interface.
+func (of ObserverFuncs) BeforeStart(obj interface{}) {
+	if of.BeforeStartFunc != nil {
+		of.BeforeStartFunc(obj)
+	}
+}
+
+// AfterStart implements Observer interface.
+func (of ObserverFuncs) AfterStart(obj interface{}, err error, d time.Duration) {
+	if of.AfterStartFunc != nil {
+		of.AfterStartFunc(obj, err, d)
+	}
+}
@@ -0,0 +1,48 @@
+package sdi_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+func TestObserver(t *testing.T) {
+	var (
+		jn     journal
+		events []string
+	)
+	ob := sdi.ObserverFuncs{
+		BeforeInitFunc: func(obj interface{}) {
+			events = append(events, fmt.Sprintf("before init %s", obj.(*journaled).name))
+		},
+		AfterInitFunc: func(obj interface{}, err error, d time.Duration) {
+			events = append(events, fmt.Sprintf("after init %s %v", obj.(*journaled).name, err))
+		},
+		AfterStartFunc: func(obj interface{}, err error, d time.Duration) {
+			events = append(events, fmt.Sprintf("after start %s %v", obj.(*journaled).name, err))
+		},
+	}
+
+	cs := sdi.New(sdi.WithObserver(ob))
+	cs.Add(&journaled{name: "a", journal: &jn}, &journaled{name: "b", journal: &jn})
+	cs.BuildDependencies()
+	if err := cs.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.StartRunners(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"before init a", "after init a <nil>",
+		"before init b", "after init b <nil>",
+		"after start a <nil>", "after start b <nil>",
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("expected %v, got %v", expected, events)
+	}
+}
@@ -0,0 +1,237 @@
+package sdi
+
+import (
+	"log/slog"
+	"reflect"
+	"time"
+)
+
+// Option configures SimpleContainer created by New.
+type Option func(*options)
+
+type options struct {
+	parallelInit     bool
+	startConcurrency int
+	initConcurrency  int
+	observers        []Observer
+	tracer           Tracer
+	initTimeout      time.Duration
+	managed          bool
+	restartPolicy    RestartPolicy
+
+	continueOnInitError  bool
+	continueOnStartError bool
+	deterministicOrder   bool
+	resolutionPolicy     ResolutionPolicy
+	logger               Logger
+	unresolvedPolicy     UnresolvedPolicy
+	shutdownTimeout      time.Duration
+	initRetry            RetryPolicy
+	slog                 *slog.Logger
+	watchdog             WatchdogPolicy
+	startTimeout         time.Duration
+	drainTimeout         time.Duration
+	profile              string
+	lazyCycles           bool
+	contextKeys          map[string]interface{}
+	initWarning          time.Duration
+	initDump             bool
+	progress             func(done, total int, current ObjectInfo)
+	trace                bool
+	startWarning         time.Duration
+	readinessGate        bool
+	readinessTimeout     time.Duration
+	buildInfo            *BuildInfo
+	recordPath           string
+	verifyPath           string
+	fallbackResolver     func(reflect.Type) (interface{}, bool)
+	waitPolicy           *RetryPolicy
+}
+
+// WithParallelInit makes InitRequired initialize objects concurrently.
+//
+// Objects are grouped into levels using dependencies discovered by
+// BuildDependencies: an object is placed one level above the highest level
+// of the objects injected into it. Levels are processed one after another,
+// objects within the same level are initialized concurrently, limited by
+// WithInitConcurrency. Therefore each object is initialized after all its
+// dependencies.
+func WithParallelInit() Option {
+	return func(o *options) {
+		o.parallelInit = true
+	}
+}
+
+// WithLazyCycles makes objects injected into each other's fields
+// acceptable for WithParallelInit. Instead of failing with ErrCycle,
+// the field dependency closing a cycle is left out of Init ordering and
+// logged. It only relaxes ordering and has no effect without
+// WithParallelInit: unlike Lazy, nothing is resolved lazily, the field
+// points to the object itself, which may be initialized after the
+// consumer, therefore the consumer must not use it in Init. Cycles
+// declared by DependsOn are still reported.
+func WithLazyCycles() Option {
+	return func(o *options) {
+		o.lazyCycles = true
+	}
+}
+
+// WithInitConcurrency limits number of Init calls running simultaneously
+// within a level of WithParallelInit, e.g. to avoid connection storms
+// tripping rate limits of LDAP or database servers. Zero or negative n
+// means no limit.
+func WithInitConcurrency(n int) Option {
+	return func(o *options) {
+		o.initConcurrency = n
+	}
+}
+
+// WithStartConcurrency limits number of Start calls running simultaneously
+// inside StartRunnersConcurrent. Zero or negative n means no limit.
+func WithStartConcurrency(n int) Option {
+	return func(o *options) {
+		o.startConcurrency = n
+	}
+}
+
+// WithObserver adds observer notified about lifecycle of containered
+// objects. The option can be used several times.
+func WithObserver(ob Observer) Option {
+	return func(o *options) {
+		o.observers = append(o.observers, ob)
+	}
+}
+
+// WithTracer sets tracer starting a span around Init and Start of each
+// containered object.
+func WithTracer(t Tracer) Option {
+	return func(o *options) {
+		o.tracer = t
+	}
+}
+
+// WithInitTimeout sets default maximum duration of Init of each object.
+// Objects implementing InitTimeouter override it.
+func WithInitTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.initTimeout = d
+	}
+}
+
+// WithStartTimeout sets default maximum duration of Start of each runner
+// called by StartRunners or StartRunnersConcurrent. Runners implementing
+// StartTimeouter override it. Runner not returning from Start in time
+// fails startup, its context is cancelled. The timeout is not applied to
+// managed runners, see WithManagedRunners.
+func WithStartTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.startTimeout = d
+	}
+}
+
+// WithManagedRunners makes StartRunners call Start of each runner in
+// a separate goroutine managed by the container. Start is expected to
+// block while the runner is running, its return means the runner exited.
+//
+// Errors returned by Start are reported by Errors, Wait blocks until
+// all runners exit. Runner state is reported by Objects.
+func WithManagedRunners() Option {
+	return func(o *options) {
+		o.managed = true
+	}
+}
+
+// WithSupervision makes StartRunners supervise runners.
+//
+// Runners are managed as with WithManagedRunners. In addition, if Start
+// returns an error before the context passed to StartRunners is done,
+// the runner is restarted after a backoff delay according to the policy p.
+// Start returning nil means the runner finished and it's not restarted.
+func WithSupervision(p RestartPolicy) Option {
+	return func(o *options) {
+		o.managed = true
+		o.restartPolicy = p
+	}
+}
+
+// WithContinueOnInitError makes InitRequired initialize all objects it can
+// instead of stopping at the first failed Init. Objects depending on
+// failed ones are skipped. InitRequired returns errors of all failed Init
+// calls joined by errors.Join, the container is initialized nevertheless:
+// StartRunners starts runners except failed and skipped ones.
+func WithContinueOnInitError() Option {
+	return func(o *options) {
+		o.continueOnInitError = true
+	}
+}
+
+// WithContinueOnStartError makes StartRunners and StartRunnersConcurrent
+// start all runners they can instead of stopping at the first failed
+// Start and stopping started runners. Runners depending on failed ones
+// are skipped by StartRunners. Failed and skipped runners are reported
+// failed by Status and unhealthy by Health. StartRunners returns errors
+// of all failed Start calls joined by errors.Join and leaves the container
+// started.
+func WithContinueOnStartError() Option {
+	return func(o *options) {
+		o.continueOnStartError = true
+	}
+}
+
+// WithResolutionPolicy sets how BuildDependencies chooses the object to
+// inject if several containered objects are assignable to the field.
+// The same choice is made by Resolve, As, GetByType and Lazy, parameters
+// of constructors added by Provide get objects chosen the same way.
+// By default ambiguity is an error.
+func WithResolutionPolicy(p ResolutionPolicy) Option {
+	return func(o *options) {
+		o.resolutionPolicy = p
+	}
+}
+
+// WithLogger makes the container log Init and Start of containered objects
+// and fields left nil by BuildDependencies.
+func WithLogger(l Logger) Option {
+	return func(o *options) {
+		o.logger = l
+		o.observers = append(o.observers, logObserver{l: l})
+	}
+}
+
+// WithStrictMode makes BuildDependencies fail if an exported interface,
+// pointer or function field, or a setter, is left without dependency.
+// Returned error wraps ErrUnresolvedDependency for each of them.
+// It's the same as WithUnresolvedPolicy(FailOnUnresolved).
+func WithStrictMode() Option {
+	return WithUnresolvedPolicy(FailOnUnresolved)
+}
+
+// WithUnresolvedPolicy sets what BuildDependencies does if an exported
+// interface, pointer or function field, or a setter, is left without
+// dependency. By default the field is logged and left nil. Fields
+// override the policy by tag option unresolved, e.g.
+// `sdi:"unresolved=strict"`.
+func WithUnresolvedPolicy(p UnresolvedPolicy) Option {
+	return func(o *options) {
+		o.unresolvedPolicy = p
+	}
+}
+
+// WithShutdownTimeout limits overall duration of Stop, including Stop
+// called by Run after its context is done. Objects not stopped in time
+// are abandoned and reported in the error returned by Stop.
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.shutdownTimeout = d
+	}
+}
+
+// WithInitRetry makes InitRequired retry failed Init of each object with
+// exponential backoff according to the policy p. Objects implementing
+// InitRetrier override it. Each attempt is limited by Init timeout,
+// panics are not retried.
+func WithInitRetry(p RetryPolicy) Option {
+	return func(o *options) {
+		o.initRetry = p
+	}
+}
@@ -0,0 +1,46 @@
+package sdi
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned by lifecycle methods when Init or Start of
+// a containered object panics, and by Build when a constructor registered
+// by Provide panics.
+type PanicError struct {
+	// Object is the name of the object.
+	Object string
+
+	// Phase is PhaseInit, PhaseStart or PhaseConstruct.
+	Phase string
+
+	// Value is the value passed to panic.
+	Value interface{}
+
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("sdi: %s.%s panicked: %v\n%s", e.Object, e.Phase, e.Value, e.Stack)
+}
+
+// recovered returns function calling f and converting its panic
+// into PanicError.
+func recovered(object, phase string, f func(context.Context) error) func(context.Context) error {
+	return func(ctx context.Context) (err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				err = &PanicError{
+					Object: object,
+					Phase:  phase,
+					Value:  v,
+					Stack:  debug.Stack(),
+				}
+			}
+		}()
+		return f(ctx)
+	}
+}
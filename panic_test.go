@@ -0,0 +1,51 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type panicky struct{}
+
+func (p *panicky) Init(ctx context.Context) error {
+	var m map[string]int
+	m["x"] = 1
+	return nil
+}
+
+type panickyRunner struct{}
+
+func (p *panickyRunner) Start(ctx context.Context) error {
+	panic("no listener")
+}
+
+func TestPanicRecovery(t *testing.T) {
+	cs := sdi.New()
+	cs.Add(&panicky{})
+	cs.BuildDependencies()
+
+	err := cs.InitRequired(context.Background())
+	var pe *sdi.PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected PanicError, got %v", err)
+	}
+	if pe.Object != "*sdi_test.panicky" || pe.Phase != sdi.PhaseInit {
+		t.Errorf("unexpected panic error %+v", pe)
+	}
+	if !strings.Contains(string(pe.Stack), "(*panicky).Init") {
+		t.Errorf("expected stack trace to mention Init, got %s", pe.Stack)
+	}
+
+	cs = sdi.New()
+	cs.Add(&panickyRunner{})
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+	err = cs.StartRunners(context.Background())
+	if !errors.As(err, &pe) || pe.Phase != sdi.PhaseStart || pe.Value != "no listener" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+package sdi
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// levels groups positions of containered objects by init phases and
+// dependency levels within a phase. Objects at level 0 of a phase have no
+// dependencies, objects at level N depend only on objects from levels
+// below N and from earlier phases.
+func (c *SimpleContainer) levels() ([][]int, error) {
+	providers := make([][]dependency, len(c.objects))
+	for _, d := range c.deps {
+		providers[d.consumer] = append(providers[d.consumer], d)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(c.objects))
+	level := make([]int, len(c.objects))
+
+	var visit func(int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return ErrCycle
+		}
+		state[i] = visiting
+		for _, d := range providers[i] {
+			p := d.provider
+			// WithLazyCycles relaxes ordering only, the field is
+			// injected as is.
+			if state[p] == visiting && !d.explicit && c.opts.lazyCycles {
+				c.logf("sdi: %s.%s is lazy, it closes dependency cycle", c.nameOf(i), d.field)
+				continue
+			}
+			if err := visit(p); err != nil {
+				return err
+			}
+			if level[p]+1 > level[i] {
+				level[i] = level[p] + 1
+			}
+		}
+		state[i] = visited
+		return nil
+	}
+
+	for i := range c.objects {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		res   [][]int
+		phase int
+		base  int // index in res of level 0 of the phase
+	)
+	for k, i := range c.byPhase() {
+		if p := c.phaseOf(i); k == 0 || p != phase {
+			phase = p
+			base = len(res)
+		}
+		for len(res) <= base+level[i] {
+			res = append(res, nil)
+		}
+		res[base+level[i]] = append(res[base+level[i]], i)
+	}
+	return res, nil
+}
+
+// initParallel inits containered objects level by level, concurrently
+// within a level. The first error cancels the context passed to
+// Init of other objects of the same level and stops initialization,
+// unless WithContinueOnInitError is used.
+func (c *SimpleContainer) initParallel(ctx context.Context) error {
+	levels, err := c.levels()
+	if err != nil {
+		return err
+	}
+
+	var (
+		mux    sync.Mutex
+		errs   []error
+		failed = make([]bool, len(c.objects))
+	)
+
+	for _, level := range levels {
+		g, gctx := newGroup(ctx, c.opts.initConcurrency)
+		for _, i := range level {
+			i := i
+			if c.providerFailed(i, failed) {
+				failed[i] = true
+				continue
+			}
+			s, ok := c.objects[i].(Initializer)
+			if !ok || c.stateOf(i).inited {
+				continue
+			}
+			g.Go(func() error {
+				err := c.initObject(gctx, i, s)
+				if err == nil || !c.opts.continueOnInitError {
+					return err
+				}
+				mux.Lock()
+				failed[i] = true
+				errs = append(errs, err)
+				mux.Unlock()
+				return nil
+			})
+		}
+		err := g.Wait()
+		g.cancel()
+		if err != nil {
+			return err
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// StartRunnersConcurrent starts runner of each containered object
+// implementing Runner interface concurrently. Number of simultaneously
+// running Start calls is limited by WithStartConcurrency option.
+//
+// The first error returned by Start cancels the context passed to
+// all runners and is returned after all Start calls have returned.
+// Started runners implementing Stopper are stopped in reverse order.
+// Containers created with WithContinueOnStartError start all runners
+// and return errors of all failed Start calls joined by errors.Join.
+//
+// Options opts select runners to start, see Groups.
+func (c *SimpleContainer) StartRunnersConcurrent(ctx context.Context, opts ...StartOption) error {
+	if err := c.begin("StartRunnersConcurrent", StateInitialized); err != nil {
+		return err
+	}
+	so := newStartOptions(opts)
+	if err := c.checkGroups(so); err != nil {
+		return c.end(StateInitialized, err)
+	}
+
+	c.track(func(i int) bool {
+		_, ok := c.objects[i].(Runner)
+		return ok && c.selected(i, so)
+	})
+	defer c.untrack()
+
+	var (
+		errMux sync.Mutex
+		errs   []error
+	)
+	g, gctx := newGroup(ctx, c.opts.startConcurrency)
+	defer g.cancel()
+	for i := range c.objects {
+		i := i
+		s, ok := c.objects[i].(Runner)
+		if !ok || !c.selected(i, so) {
+			continue
+		}
+		if c.notInited(i) {
+			c.advance(i)
+			continue
+		}
+		if c.gated(i) {
+			c.startGated(ctx, i, s)
+			c.advance(i)
+			continue
+		}
+		if c.blocking(i) {
+			c.startBlocking(ctx, i, s)
+			c.advance(i)
+			continue
+		}
+		g.Go(func() error {
+			// runners keep their context after StartRunnersConcurrent
+			// returns, so it's derived from ctx. A failed Start of
+			// another runner cancels it only while Start is running,
+			// runners started already are stopped by rollback.
+			stop := context.AfterFunc(gctx, func() {
+				c.setState(i, func(st *objectState) {
+					if st.cancel != nil {
+						st.cancel()
+					}
+				})
+			})
+			err := c.startObject(ctx, i, s)
+			stop()
+			if err != nil {
+				err = &StartError{Object: c.nameOf(i), Err: err}
+				if !c.opts.continueOnStartError {
+					return err
+				}
+				c.markFailed(i, err)
+				errMux.Lock()
+				errs = append(errs, err)
+				errMux.Unlock()
+			}
+			c.advance(i)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return c.end(StateStarted, c.rollback(err, func(s objectState) bool { return s.started }))
+	}
+	c.startWatchdog(ctx)
+	// failed runners don't prevent the container from being started.
+	return c.runnersStarted(errors.Join(errs...))
+}
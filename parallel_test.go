@@ -0,0 +1,227 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+type Pinger interface {
+	Ping() bool
+}
+
+// barrierNode waits in Init until all nodes sharing the same barrier
+// reached Init. It succeeds only when nodes are initialized concurrently.
+type barrierNode struct {
+	barrier *barrier
+	inited  int32
+}
+
+type barrier struct {
+	n       int32
+	arrived int32
+	ch      chan struct{}
+	once    sync.Once
+}
+
+func (b *barrier) wait(ctx context.Context) error {
+	if atomic.AddInt32(&b.arrived, 1) == b.n {
+		b.once.Do(func() { close(b.ch) })
+	}
+	select {
+	case <-b.ch:
+		return nil
+	case <-time.After(time.Second):
+		return errors.New("barrier timeout")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (n *barrierNode) Init(ctx context.Context) error {
+	if err := n.barrier.wait(ctx); err != nil {
+		return err
+	}
+	atomic.StoreInt32(&n.inited, 1)
+	return nil
+}
+
+func (n *barrierNode) Ping() bool {
+	return atomic.LoadInt32(&n.inited) == 1
+}
+
+type pingConsumer struct {
+	Dep Pinger
+}
+
+func (p *pingConsumer) Init(ctx context.Context) error {
+	if !p.Dep.Ping() {
+		return errors.New("dependency is not initialized")
+	}
+	return nil
+}
+
+func TestParallelInit(t *testing.T) {
+	b := &barrier{n: 2, ch: make(chan struct{})}
+	cs := sdi.New(sdi.WithParallelInit(), sdi.WithResolutionPolicy(sdi.PreferLast))
+	cs.Add(&pingConsumer{}, &barrierNode{barrier: b}, new(G))
+	cs.Add(&pingConsumer{}, &barrierNode{barrier: b})
+	cs.BuildDependencies()
+
+	if err := cs.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type cycleA struct {
+	B Pinger
+}
+
+func (a *cycleA) Init(ctx context.Context) error { return nil }
+func (a *cycleA) Global()                        {}
+
+type cycleB struct {
+	A Globalizer
+}
+
+type Globalizer interface {
+	Global()
+}
+
+func (b *cycleB) Init(ctx context.Context) error { return nil }
+func (b *cycleB) Ping() bool                     { return true }
+
+func TestParallelInitCycle(t *testing.T) {
+	cs := sdi.New(sdi.WithParallelInit())
+	cs.Add(&cycleA{}, &cycleB{})
+	cs.BuildDependencies()
+
+	if err := cs.InitRequired(context.Background()); !errors.Is(err, sdi.ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestParallelInitLazyCycles(t *testing.T) {
+	a, b := cycleA{}, cycleB{}
+	cs := sdi.New(sdi.WithParallelInit(), sdi.WithLazyCycles())
+	cs.Add(&a, &b)
+	cs.BuildDependencies()
+
+	if err := cs.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if a.B != &b || b.A != &a {
+		t.Error("expected objects to be injected into each other")
+	}
+}
+
+type barrierRunner struct {
+	barrier *barrier
+	err     error
+}
+
+func (r *barrierRunner) Start(ctx context.Context) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.barrier.wait(ctx)
+}
+
+func TestStartRunnersConcurrent(t *testing.T) {
+	b := &barrier{n: 3, ch: make(chan struct{})}
+	cs := sdi.New(sdi.WithStartConcurrency(3))
+	cs.Add(&barrierRunner{barrier: b}, &barrierRunner{barrier: b}, &barrierRunner{barrier: b})
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+	if err := cs.StartRunnersConcurrent(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStartRunnersConcurrentError(t *testing.T) {
+	errStart := errors.New("start failed")
+	b := &barrier{n: 3, ch: make(chan struct{})}
+	cs := sdi.New()
+	cs.Add(&barrierRunner{barrier: b}, &barrierRunner{barrier: b}, &barrierRunner{err: errStart})
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+
+	// third runner fails, barrier never opens and other runners
+	// must be released by context cancellation.
+	err := cs.StartRunnersConcurrent(context.Background())
+	if !errors.Is(err, errStart) {
+		t.Errorf("expected %v, got %v", errStart, err)
+	}
+}
+
+// ctxKeeper keeps the context passed to Start.
+type ctxKeeper struct {
+	ctx context.Context
+}
+
+func (k *ctxKeeper) Start(ctx context.Context) error {
+	k.ctx = ctx
+	return nil
+}
+
+func TestStartRunnersConcurrentContext(t *testing.T) {
+	k := ctxKeeper{}
+	cs := sdi.New()
+	cs.Add(&k)
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+	if err := cs.StartRunnersConcurrent(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.ctx.Err(); err != nil {
+		t.Fatalf("expected context of started runner to stay alive, got %v", err)
+	}
+	cs.Stop(context.Background())
+	if k.ctx.Err() == nil {
+		t.Error("expected context of runner to be cancelled by Stop")
+	}
+}
+
+// gauge records maximum number of concurrent Init calls.
+type gauge struct {
+	cur, max int32
+}
+
+type gaugedNode struct {
+	g *gauge
+}
+
+func (n *gaugedNode) Init(ctx context.Context) error {
+	cur := atomic.AddInt32(&n.g.cur, 1)
+	defer atomic.AddInt32(&n.g.cur, -1)
+	for {
+		max := atomic.LoadInt32(&n.g.max)
+		if cur <= max || atomic.CompareAndSwapInt32(&n.g.max, max, cur) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return nil
+}
+
+func TestParallelInitConcurrency(t *testing.T) {
+	var g gauge
+	cs := sdi.New(sdi.WithParallelInit(), sdi.WithInitConcurrency(2))
+	for k := 0; k < 6; k++ {
+		cs.Add(&gaugedNode{g: &g})
+	}
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if max := atomic.LoadInt32(&g.max); max > 2 || max < 1 {
+		t.Errorf("expected at most 2 concurrent Init calls, got %d", max)
+	}
+}
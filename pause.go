@@ -0,0 +1,89 @@
+package sdi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Pauser is the interface that wraps the basic Pause method.
+//
+// Pause makes the started object stop taking new work, e.g. a queue
+// consumer stops polling, without releasing its resources.
+type Pauser interface {
+	Pause(ctx context.Context) error
+}
+
+// Resumer is the interface that wraps the basic Resume method.
+//
+// Resume makes the object paused by Pause take new work again.
+type Resumer interface {
+	Resume(ctx context.Context) error
+}
+
+// Pause calls Pause of started objects implementing Pauser in the reverse
+// order they've been started. An error returned by Pause does not break
+// pausing of remaining objects, all errors are returned joined by
+// errors.Join, failed objects are not resumed by Resume.
+//
+// Pause must be called after StartRunners, otherwise error wrapping
+// ErrInvalidState is returned. The container moves to StatePaused.
+func (c *SimpleContainer) Pause(ctx context.Context) error {
+	if err := c.begin("Pause", StateStarted); err != nil {
+		return err
+	}
+
+	var errs []error
+	seq := c.startSequence()
+	for k := len(seq) - 1; k >= 0; k-- {
+		i := seq[k]
+		if !c.stateOf(i).started {
+			continue
+		}
+		var err error
+		if p, ok := c.objects[i].(Pauser); ok {
+			err = p.Pause(ctx)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("sdi: %s.Pause: %w", c.nameOf(i), err))
+			continue
+		}
+		c.setState(i, func(s *objectState) {
+			s.paused = true
+		})
+	}
+
+	c.end(StatePaused, nil)
+	return errors.Join(errs...)
+}
+
+// Resume calls Resume of objects implementing Resumer paused by Pause in
+// the order they've been started. An error returned by Resume does not
+// break resuming of remaining objects, all errors are returned joined by
+// errors.Join.
+//
+// Resume must be called after Pause, otherwise error wrapping
+// ErrInvalidState is returned. The container moves to StateStarted.
+func (c *SimpleContainer) Resume(ctx context.Context) error {
+	if err := c.begin("Resume", StatePaused); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, i := range c.startSequence() {
+		if !c.stateOf(i).paused {
+			continue
+		}
+		if r, ok := c.objects[i].(Resumer); ok {
+			if err := r.Resume(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("sdi: %s.Resume: %w", c.nameOf(i), err))
+			}
+		}
+		c.setState(i, func(s *objectState) {
+			s.paused = false
+		})
+	}
+
+	c.end(StateStarted, nil)
+	return errors.Join(errs...)
+}
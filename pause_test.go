@@ -0,0 +1,64 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type poller struct {
+	journaled
+	pauseErr error
+}
+
+func (p *poller) Pause(ctx context.Context) error {
+	if p.pauseErr != nil {
+		return p.pauseErr
+	}
+	p.journal.calls = append(p.journal.calls, "pause "+p.name)
+	return nil
+}
+
+func (p *poller) Resume(ctx context.Context) error {
+	p.journal.calls = append(p.journal.calls, "resume "+p.name)
+	return nil
+}
+
+func TestPauseResume(t *testing.T) {
+	var jn journal
+	ctx := context.Background()
+	cs := sdi.New()
+	cs.Add(&poller{journaled: journaled{name: "queue", journal: &jn}},
+		&journaled{name: "api", journal: &jn},
+		&poller{journaled: journaled{name: "cron", journal: &jn}, pauseErr: errors.New("job running")})
+	cs.BuildDependencies()
+
+	if err := cs.Pause(ctx); !errors.Is(err, sdi.ErrInvalidState) {
+		t.Errorf("expected ErrInvalidState, got %v", err)
+	}
+	cs.InitRequired(ctx)
+	cs.StartRunners(ctx)
+	jn.calls = nil
+
+	err := cs.Pause(ctx)
+	if err == nil || err.Error() != "sdi: *sdi_test.poller.Pause: job running" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if s := cs.State(); s != sdi.StatePaused {
+		t.Errorf("expected paused state, got %s", s)
+	}
+	if err := cs.Resume(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if s := cs.State(); s != sdi.StateStarted {
+		t.Errorf("expected started state, got %s", s)
+	}
+
+	expected := []string{"pause queue", "resume queue"}
+	if !reflect.DeepEqual(jn.calls, expected) {
+		t.Errorf("expected %v, got %v", expected, jn.calls)
+	}
+}
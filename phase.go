@@ -0,0 +1,49 @@
+package sdi
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Phaser is the interface that wraps the basic InitPhase method.
+//
+// InitPhase returns coarse init phase of the object, for instance 0 for
+// configuration, 10 for infrastructure, 20 for domain services and 30 for
+// transports. Objects are initialized and started in ascending order of
+// phases and in the order of dependencies within a phase. Objects not
+// implementing Phaser are in phase 0.
+type Phaser interface {
+	InitPhase() int
+}
+
+// phaseOf returns init phase of the object at position i.
+func (c *SimpleContainer) phaseOf(i int) int {
+	if p, ok := c.objects[i].(Phaser); ok {
+		return p.InitPhase()
+	}
+	return 0
+}
+
+// byPhase returns positions of objects stable sorted by init phase.
+func (c *SimpleContainer) byPhase() []int {
+	seq := make([]int, len(c.objects))
+	for i := range seq {
+		seq[i] = i
+	}
+	sort.SliceStable(seq, func(a, b int) bool {
+		return c.phaseOf(seq[a]) < c.phaseOf(seq[b])
+	})
+	return seq
+}
+
+// checkPhases returns error if an object depends on an object of a later
+// init phase.
+func (c *SimpleContainer) checkPhases() error {
+	for _, d := range c.deps {
+		if pc, pp := c.phaseOf(d.consumer), c.phaseOf(d.provider); pp > pc {
+			return fmt.Errorf("sdi: %s of phase %d depends on %s of later phase %d",
+				c.nameOf(d.consumer), pc, c.nameOf(d.provider), pp)
+		}
+	}
+	return nil
+}
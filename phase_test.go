@@ -0,0 +1,56 @@
+package sdi_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type phased struct {
+	journaled
+	phase int
+}
+
+func (p *phased) InitPhase() int {
+	return p.phase
+}
+
+func TestPhaser(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		var jn journal
+		var opts []sdi.Option
+		if parallel {
+			opts = append(opts, sdi.WithParallelInit())
+		}
+		cs := sdi.New(opts...)
+		transport := &phased{journaled: journaled{name: "transport", journal: &jn}, phase: 30}
+		domain := &phased{journaled: journaled{name: "domain", journal: &jn}, phase: 20}
+		db := &dependent{journaled: journaled{name: "db", journal: &jn}}
+		config := &journaled{name: "config", journal: &jn}
+		db.deps = []interface{}{config}
+
+		cs.Add(transport, domain, db, config)
+		if err := cs.Build(); err != nil {
+			t.Fatal(err)
+		}
+		if err := cs.InitRequired(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{"init config", "init db", "init domain", "init transport"}
+		if !reflect.DeepEqual(jn.calls, expected) {
+			t.Errorf("parallel=%v: expected %v, got %v", parallel, expected, jn.calls)
+		}
+	}
+}
+
+func TestPhaserConflict(t *testing.T) {
+	domain := &phased{phase: 20}
+	cs := sdi.New()
+	cs.Add(&dependent{deps: []interface{}{domain}}, domain)
+	if err := cs.Build(); err == nil {
+		t.Error("expected error for dependency on later phase")
+	}
+}
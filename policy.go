@@ -0,0 +1,92 @@
+package sdi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ResolutionPolicy defines which object is injected if several containered
+// objects are assignable to the same field.
+type ResolutionPolicy int
+
+const (
+	// FailOnAmbiguity makes Build return ErrAmbiguous.
+	FailOnAmbiguity ResolutionPolicy = iota
+
+	// PreferLast injects the last added object, or the last one in order
+	// of WithDeterministicOrder.
+	PreferLast
+
+	// PreferFirst injects the first added object, or the first one in
+	// order of WithDeterministicOrder.
+	PreferFirst
+)
+
+// choose returns one of the positions found according to the resolution
+// policy or -1 if found is empty.
+func (c *SimpleContainer) choose(ft reflect.Type, found []int) (int, error) {
+	switch {
+	case len(found) == 0:
+		return -1, nil
+	case len(found) == 1:
+		return found[0], nil
+	}
+
+	switch c.opts.resolutionPolicy {
+	case PreferLast:
+		return found[len(found)-1], nil
+	case PreferFirst:
+		return found[0], nil
+	}
+
+	names := make([]string, len(found))
+	for k, i := range found {
+		names[k] = c.nameOf(i)
+	}
+	return -1, fmt.Errorf("%w: %s is satisfied by %s", ErrAmbiguous, ft, strings.Join(names, ", "))
+}
+
+// UnresolvedPolicy defines what BuildDependencies does if no containered
+// object is assignable to an exported interface, pointer or function
+// field, or a setter.
+type UnresolvedPolicy int
+
+const (
+	// WarnOnUnresolved logs the field and leaves it nil.
+	WarnOnUnresolved UnresolvedPolicy = iota
+
+	// FailOnUnresolved makes Build return error wrapping
+	// ErrUnresolvedDependency. The error describes the field, its owner
+	// and type, and near-miss objects with the reason each of them does not
+	// match, e.g. a method with pointer receiver or a missing method.
+	FailOnUnresolved
+
+	// IgnoreUnresolved leaves the field nil silently.
+	IgnoreUnresolved
+)
+
+// unresolvedPolicies maps values of tag option unresolved to policies.
+var unresolvedPolicies = map[string]UnresolvedPolicy{
+	"warn":   WarnOnUnresolved,
+	"strict": FailOnUnresolved,
+	"ignore": IgnoreUnresolved,
+}
+
+// unresolvedPolicy returns the policy applied to the field with tag
+// options opts. Tag option unresolved overrides the container policy:
+//
+//	Cache CacheService `sdi:"unresolved=ignore"`
+//
+// Its value is one of "strict", "warn" or "ignore".
+func (c *SimpleContainer) unresolvedPolicy(opts map[string]string) (UnresolvedPolicy, error) {
+	s, ok := opts["unresolved"]
+	if !ok {
+		return c.opts.unresolvedPolicy, nil
+	}
+	p, ok := unresolvedPolicies[s]
+	if !ok {
+		return 0, fmt.Errorf("unknown unresolved policy %q", s)
+	}
+	return p, nil
+}
@@ -0,0 +1,113 @@
+package sdi_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestAmbiguousDependency(t *testing.T) {
+	cs := sdi.New()
+	cs.Add(&C{gender: "first"}, &B{}, &C{gender: "second"})
+
+	err := cs.Build()
+	if !errors.Is(err, sdi.ErrAmbiguous) {
+		t.Fatalf("expected ErrAmbiguous, got %v", err)
+	}
+}
+
+func TestResolutionPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		policy sdi.ResolutionPolicy
+		want   string
+	}{
+		{sdi.PreferFirst, "first"},
+		{sdi.PreferLast, "second"},
+	} {
+		cs := sdi.New(sdi.WithResolutionPolicy(tc.policy))
+		b := B{}
+		cs.Add(&C{gender: "first"}, &b, &C{gender: "second"})
+		if err := cs.Build(); err != nil {
+			t.Fatal(err)
+		}
+		if got := b.CService.(*C).gender; got != tc.want {
+			t.Errorf("expected %s, got %s", tc.want, got)
+		}
+	}
+}
+
+type optionalDeps struct {
+	Cache   Client `sdi:"unresolved=ignore"`
+	Metrics Pinger `sdi:"unresolved=strict"`
+	Tracer  Handler
+}
+
+func (o *optionalDeps) Global() {}
+
+func TestUnresolvedPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		policy   sdi.UnresolvedPolicy
+		expected []string
+	}{
+		{sdi.WarnOnUnresolved, []string{"Metrics"}},
+		{sdi.FailOnUnresolved, []string{"Metrics", "Tracer"}},
+		{sdi.IgnoreUnresolved, []string{"Metrics"}},
+	} {
+		cs := sdi.New(sdi.WithUnresolvedPolicy(tc.policy))
+		cs.Add(&optionalDeps{})
+		err := cs.Build()
+		if !errors.Is(err, sdi.ErrUnresolvedDependency) {
+			t.Fatalf("expected %v, got %v", sdi.ErrUnresolvedDependency, err)
+		}
+		for _, field := range []string{"Cache", "Metrics", "Tracer"} {
+			reported := strings.Contains(err.Error(), "optionalDeps."+field)
+			if expected := strings.Contains(strings.Join(tc.expected, " "), field); reported != expected {
+				t.Errorf("policy %d: field %s reported %t, expected %t", tc.policy, field, reported, expected)
+			}
+		}
+	}
+}
+
+type badPolicy struct {
+	Cache Client `sdi:"unresolved=maybe"`
+}
+
+func (b *badPolicy) Global() {}
+
+func TestUnresolvedPolicyTag(t *testing.T) {
+	cs := sdi.New()
+	cs.Add(&badPolicy{})
+	err := cs.Build()
+	if err == nil || !strings.Contains(err.Error(), `badPolicy.Cache: unknown unresolved policy "maybe"`) {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestResolveAmbiguous(t *testing.T) {
+	first, last := &client{name: "first"}, &client{name: "last"}
+	for _, tc := range []struct {
+		policy   sdi.ResolutionPolicy
+		expected Client
+	}{
+		{sdi.FailOnAmbiguity, nil},
+		{sdi.PreferFirst, first},
+		{sdi.PreferLast, last},
+	} {
+		cs := sdi.New(sdi.WithResolutionPolicy(tc.policy))
+		cs.Add(first, last)
+		cs.BuildDependencies()
+
+		cl, err := sdi.Resolve[Client](cs)
+		if tc.expected == nil {
+			if !errors.Is(err, sdi.ErrAmbiguous) {
+				t.Errorf("expected %v, got %v", sdi.ErrAmbiguous, err)
+			}
+			continue
+		}
+		if err != nil || cl != tc.expected {
+			t.Errorf("policy %d: expected %s client, got %v, %v", tc.policy, tc.expected.Call(), cl, err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package sdi
+
+import "sort"
+
+// StartPrioritizer is the interface that wraps the basic StartPriority
+// method.
+//
+// StartPriority returns start priority of the runner. Runners with higher
+// priority are started before runners with lower priority regardless of
+// the order they've been added in and their init phases, see Phaser.
+// Objects still start after objects they depend on by DependsOn. Objects
+// not implementing StartPrioritizer have priority 0.
+//
+// Objects are stopped in the reverse order of Start calls, but never
+// before objects depending on them.
+type StartPrioritizer interface {
+	StartPriority() int
+}
+
+// priorityOf returns start priority of the object at position i.
+func (c *SimpleContainer) priorityOf(i int) int {
+	if p, ok := c.objects[i].(StartPrioritizer); ok {
+		return p.StartPriority()
+	}
+	return 0
+}
+
+// byPriority returns copy of seq stable sorted by descending start priority.
+func (c *SimpleContainer) byPriority(seq []int) []int {
+	res := append([]int(nil), seq...)
+	sort.SliceStable(res, func(a, b int) bool {
+		return c.priorityOf(res[a]) > c.priorityOf(res[b])
+	})
+	return res
+}
+
+// startSequence returns positions of objects in the order of Start calls.
+func (c *SimpleContainer) startSequence() []int {
+	if c.startOrder != nil {
+		return c.startOrder
+	}
+	return c.sequence()
+}
+
+// stopSequence returns positions of objects in the order of Stop calls.
+func (c *SimpleContainer) stopSequence() []int {
+	if c.stopOrder != nil {
+		return c.stopOrder
+	}
+	return c.reverseOrder(c.startSequence())
+}
@@ -0,0 +1,48 @@
+package sdi_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type prioritized struct {
+	journaled
+	priority int
+}
+
+func (p *prioritized) StartPriority() int {
+	return p.priority
+}
+
+func TestStartPriority(t *testing.T) {
+	var jn journal
+	api := &journaled{name: "api", journal: &jn}
+	db := &journaled{name: "db", journal: &jn}
+	metrics := &prioritized{journaled: journaled{name: "metrics", journal: &jn}, priority: 100}
+	cs := sdi.New()
+	cs.Add(api, db, metrics)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.StartRunners(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"init api", "init db", "init metrics",
+		"start metrics", "start api", "start db",
+		"stop db", "stop api", "stop metrics",
+	}
+	if !reflect.DeepEqual(jn.calls, expected) {
+		t.Errorf("expected %v, got %v", expected, jn.calls)
+	}
+}
@@ -0,0 +1,40 @@
+package sdi
+
+// WithProfile sets the active profile, e.g. "prod" or "dev". Objects
+// added by AddForProfile for other profiles are removed by
+// BuildDependencies the same way as disabled ones, see Enabler.
+func WithProfile(name string) Option {
+	return func(o *options) {
+		o.profile = name
+	}
+}
+
+// AddForProfile adds objects into container if the profile is active,
+// see WithProfile. Objects are kept in container until BuildDependencies,
+// therefore it panics in the same cases as Add even if the profile is not
+// active. It panics if the profile is empty.
+//
+//	c.AddForProfile("prod", &smtpMailer{})
+//	c.AddForProfile("dev", &logMailer{})
+func (c *SimpleContainer) AddForProfile(profile string, o ...interface{}) {
+	if profile == "" {
+		panic("sdi: empty profile name")
+	}
+
+	o, r := withAddOptions(o, registration{profile: profile})
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for i := range o {
+		mustBeContainerable(o[i])
+		c.add(o[i], r)
+	}
+}
+
+// inactive reports whether the object at position i is added for
+// a profile other than the active one.
+func (c *SimpleContainer) inactive(i int) bool {
+	p := c.regs[i].profile
+	return p != "" && p != c.opts.profile
+}
@@ -0,0 +1,42 @@
+package sdi_test
+
+import (
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestProfile(t *testing.T) {
+	for _, tc := range []struct {
+		profile  string
+		expected string
+	}{
+		{"prod", "smtp"},
+		{"dev", "log"},
+	} {
+		cs := sdi.New(sdi.WithProfile(tc.profile))
+		g := gateway{}
+		cs.AddForProfile("prod", &client{name: "smtp"})
+		cs.AddForProfile("dev", &client{name: "log"})
+		cs.Add(&g)
+		if err := cs.Build(); err != nil {
+			t.Fatal(err)
+		}
+		if g.Client == nil || g.Client.Call() != tc.expected {
+			t.Errorf("profile %s: expected %s client, got %v", tc.profile, tc.expected, g.Client)
+		}
+		if n := len(cs.Objects()); n != 2 {
+			t.Errorf("profile %s: expected 2 objects, got %d", tc.profile, n)
+		}
+	}
+}
+
+func TestProfileNotSet(t *testing.T) {
+	cs := sdi.New()
+	cs.AddForProfile("dev", &client{name: "log"})
+	cs.Add(&A{})
+	cs.BuildDependencies()
+	if n := len(cs.Objects()); n != 1 {
+		t.Errorf("expected only object without profile, got %d objects", n)
+	}
+}
@@ -0,0 +1,73 @@
+package sdi
+
+import "sync"
+
+// WithProgress makes the container call fn as startup advances, e.g. to
+// render a progress bar.
+//
+// InitRequired calls fn after each Init call, StartRunners and
+// StartRunnersConcurrent after each successful Start call or, if
+// the container created with WithManagedRunners or WithSupervision option,
+// after each runner is started in its goroutine. Done counts calls made
+// by the method so far, total is the number of objects the method is
+// going to initialize or start, current is the object just processed.
+// If some objects are skipped, e.g. after failure, done does not reach
+// total.
+//
+// Calls of fn are serialized, even if objects are initialized or started
+// concurrently.
+func WithProgress(fn func(done, total int, current ObjectInfo)) Option {
+	return func(o *options) {
+		o.progress = fn
+	}
+}
+
+// progress tracks advance of a lifecycle method, see WithProgress.
+type progress struct {
+	mux   sync.Mutex
+	done  int
+	total int
+}
+
+// track starts tracking progress of a lifecycle method processing
+// objects at positions for which count returns true.
+func (c *SimpleContainer) track(count func(i int) bool) {
+	if c.opts.progress == nil {
+		return
+	}
+	p := &progress{}
+	for i := range c.objects {
+		if count(i) {
+			p.total++
+		}
+	}
+	c.mux.Lock()
+	c.progress = p
+	c.mux.Unlock()
+}
+
+// untrack stops tracking progress.
+func (c *SimpleContainer) untrack() {
+	c.mux.Lock()
+	c.progress = nil
+	c.mux.Unlock()
+}
+
+// advance reports the object at position i processed.
+func (c *SimpleContainer) advance(i int) {
+	c.mux.RLock()
+	p := c.progress
+	var oi ObjectInfo
+	if p != nil {
+		oi = c.objectInfo(i)
+	}
+	c.mux.RUnlock()
+	if p == nil {
+		return
+	}
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.done++
+	c.opts.progress(p.done, p.total, oi)
+}
@@ -0,0 +1,39 @@
+package sdi_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestWithProgress(t *testing.T) {
+	var (
+		jn    journal
+		steps []string
+	)
+	ctx := context.Background()
+	cs := sdi.New(sdi.WithProgress(func(done, total int, current sdi.ObjectInfo) {
+		steps = append(steps, fmt.Sprintf("%d/%d %s", done, total, current.Name))
+	}))
+	cs.AddNamed("db", &journaled{name: "db", journal: &jn})
+	cs.AddNamed("pool", &connPool{})
+	cs.AddNamed("api", &journaled{name: "api", journal: &jn})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Stop(ctx)
+
+	expected := []string{"1/3 db", "2/3 pool", "3/3 api", "1/2 db", "2/2 api"}
+	if !reflect.DeepEqual(steps, expected) {
+		t.Errorf("expected %v, got %v", expected, steps)
+	}
+}
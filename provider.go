@@ -0,0 +1,267 @@
+package sdi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+
+	cleanupFuncType    = reflect.TypeOf(func() {})
+	cleanupCtxFuncType = reflect.TypeOf(func(context.Context) error { return nil })
+)
+
+// provider is a constructor registered by Provide.
+type provider struct {
+	fn      reflect.Value
+	types   []reflect.Type // types of the constructed objects
+	ctx     bool           // the first parameter is context.Context
+	cleanup bool           // the result after objects is a cleanup function
+	err     bool           // the last result is error
+}
+
+// cleanup is a cleanup function returned by a constructor, name is
+// the name of the first constructed object.
+type cleanup struct {
+	name string
+	fn   func(context.Context) error
+}
+
+// Provide registers constructors of containered objects. A constructor
+// is a function taking dependencies of the object as parameters,
+// optionally preceded by context.Context, and returning the object,
+// optionally followed by a cleanup function and error, e.g.
+//
+//	func(ctx context.Context, cfg *Config) (*DB, func(), error)
+//
+// A constructor may return a bundle of related objects of distinct types,
+// all of them are containered in the order of results:
+//
+//	func(cfg *Config) (*Pool, *Migrator, *Metrics, error)
+//
+// The cleanup function is either func() or func(context.Context) error.
+// It's the last result before error, therefore a constructor can't return
+// an object of these types. See package sdifx for go.uber.org/fx.
+//
+// Constructors are called by BuildDependencies before fields of other
+// objects are injected. Parameters get objects resolved the same way as
+// by Resolve; constructors of objects assignable to a parameter are
+// called first. Fields of constructed objects are not injected, but
+// constructed objects are initialized, started and stopped the same way
+// as objects added by Add.
+//
+// Cleanup functions are called by Stop in reverse order of construction
+// after all objects are stopped. If a constructor fails, Build calls
+// cleanup functions of already constructed objects and returns the error.
+// A panicking constructor fails with PanicError.
+//
+// Provide panics if a constructor has wrong signature or
+// BuildDependencies has been called already.
+func (c *SimpleContainer) Provide(constructor ...interface{}) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for _, f := range constructor {
+		p, ok := newProvider(f)
+		if !ok {
+			panic(fmt.Sprintf("sdi: %T is not a constructor function", f))
+		}
+		if c.built {
+			panic(fmt.Sprintf("sdi: %T added after BuildDependencies", f))
+		}
+		c.providers = append(c.providers, p)
+	}
+}
+
+// newProvider returns provider calling constructor f or false if f has
+// wrong signature.
+func newProvider(f interface{}) (provider, bool) {
+	v := reflect.ValueOf(f)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return provider{}, false
+	}
+	ft := v.Type()
+	p := provider{fn: v}
+	if ft.NumIn() > 0 && ft.In(0) == contextType {
+		p.ctx = true
+	}
+
+	out := ft.NumOut()
+	if out > 0 && ft.Out(out-1) == errorType {
+		p.err = true
+		out--
+	}
+	if out > 1 && (ft.Out(out-1) == cleanupFuncType || ft.Out(out-1) == cleanupCtxFuncType) {
+		p.cleanup = true
+		out--
+	}
+	if out == 0 || ft.IsVariadic() {
+		return provider{}, false
+	}
+	for k := 0; k < out; k++ {
+		t := ft.Out(k)
+		for _, prev := range p.types {
+			if prev == t {
+				return provider{}, false
+			}
+		}
+		p.types = append(p.types, t)
+	}
+	return p, true
+}
+
+// construct calls constructors registered by Provide and adds constructed
+// objects into container. The caller must hold write lock.
+func (c *SimpleContainer) construct() error {
+	pending := c.providers
+	for len(pending) > 0 {
+		var next []provider
+		for k, p := range pending {
+			if c.awaits(p, pending[:k], pending[k+1:]) {
+				next = append(next, p)
+				continue
+			}
+			if err := c.callProvider(p); err != nil {
+				return c.cleanupAfter(err)
+			}
+		}
+		if len(next) == len(pending) {
+			var types []string
+			for _, p := range next {
+				for _, t := range p.types {
+					types = append(types, t.String())
+				}
+			}
+			return c.cleanupAfter(fmt.Errorf("%w: constructors of %v depend on each other", ErrCycle, types))
+		}
+		pending = next
+	}
+	return nil
+}
+
+// awaits reports whether a parameter of p is assignable from an object
+// of other constructors not called yet.
+func (c *SimpleContainer) awaits(p provider, others ...[]provider) bool {
+	ft := p.fn.Type()
+	for n := 0; n < ft.NumIn(); n++ {
+		for _, ps := range others {
+			for _, o := range ps {
+				for _, t := range o.types {
+					if t.AssignableTo(ft.In(n)) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// callProvider calls constructor p and adds the constructed objects.
+func (c *SimpleContainer) callProvider(p provider) error {
+	ft := p.fn.Type()
+	args := make([]reflect.Value, ft.NumIn())
+	var providers []int
+	for n := range args {
+		t := ft.In(n)
+		if n == 0 && p.ctx {
+			args[n] = reflect.ValueOf(context.Background())
+			continue
+		}
+		k, err := c.chosen(t)
+		if err != nil {
+			return fmt.Errorf("sdi: %s: parameter %d: %w", ft, n, err)
+		}
+		if k >= 0 {
+			args[n] = c.provide(t, k)
+			providers = append(providers, k)
+			continue
+		}
+		o, err := c.lookup(t, nil)
+		if err != nil {
+			return fmt.Errorf("sdi: %s: parameter %d: %w", ft, n, err)
+		}
+		args[n] = reflect.ValueOf(o)
+	}
+
+	var out []reflect.Value
+	err := recovered(ft.String(), PhaseConstruct, func(context.Context) error {
+		out = p.fn.Call(args)
+		return nil
+	})(context.Background())
+	if err != nil {
+		return err
+	}
+	if p.err && !out[len(out)-1].IsNil() {
+		return fmt.Errorf("sdi: %s: %w", ft, out[len(out)-1].Interface().(error))
+	}
+	for k := range p.types {
+		if v := out[k]; !v.IsValid() || (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+			return fmt.Errorf("%w: %s returned nil", ErrNotContainerable, ft)
+		}
+	}
+
+	first := len(c.objects)
+	for k := range p.types {
+		c.objects = append(c.objects, out[k].Interface())
+		c.regs = append(c.regs, registration{constructed: true})
+		i := len(c.objects) - 1
+		for _, pk := range providers {
+			c.deps = append(c.deps, dependency{consumer: i, provider: pk, explicit: true})
+		}
+		c.emit(Event{Type: ObjectAdded, Object: c.nameOf(i)})
+	}
+
+	if !p.cleanup {
+		return nil
+	}
+	if cf := out[len(p.types)]; !cf.IsNil() {
+		cl := cleanup{name: c.nameOf(first)}
+		switch f := cf.Interface().(type) {
+		case func():
+			cl.fn = func(context.Context) error { f(); return nil }
+		case func(context.Context) error:
+			cl.fn = f
+		}
+		c.cleanups = append(c.cleanups, cl)
+	}
+	return nil
+}
+
+// cleanupAfter calls cleanup functions after failure err and returns err
+// joined with cleanup errors. The caller must hold write lock.
+func (c *SimpleContainer) cleanupAfter(err error) error {
+	cleanups := c.cleanups
+	c.cleanups = nil
+	if cerr := runCleanups(context.Background(), cleanups); cerr != nil {
+		return errors.Join(err, cerr)
+	}
+	return err
+}
+
+// cleanup calls cleanup functions returned by constructors in reverse
+// order. Each function is called once.
+func (c *SimpleContainer) cleanup(ctx context.Context) error {
+	c.mux.Lock()
+	cleanups := c.cleanups
+	c.cleanups = nil
+	c.cleaned = c.cleaned || len(cleanups) > 0
+	c.mux.Unlock()
+
+	return runCleanups(ctx, cleanups)
+}
+
+func runCleanups(ctx context.Context, cleanups []cleanup) error {
+	var errs []error
+	for k := len(cleanups) - 1; k >= 0; k-- {
+		cl := cleanups[k]
+		if err := callUntilDone(ctx, cl.name+" cleanup", cl.fn); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
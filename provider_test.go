@@ -0,0 +1,190 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type connPool struct {
+	dsn    string
+	inited bool
+}
+
+func (p *connPool) Init(ctx context.Context) error {
+	p.inited = true
+	return nil
+}
+
+type userStore struct {
+	pool *connPool
+}
+
+func TestProvide(t *testing.T) {
+	var calls []string
+	ctx := context.Background()
+	cs := sdi.New()
+	cs.Add(&client{name: "config"})
+	cs.Provide(
+		func(p *connPool) (*userStore, func(context.Context) error) {
+			calls = append(calls, "new store")
+			return &userStore{pool: p}, func(context.Context) error {
+				calls = append(calls, "close store")
+				return nil
+			}
+		},
+		func(ctx context.Context, cl Client) (*connPool, func(), error) {
+			calls = append(calls, "new pool")
+			return &connPool{dsn: cl.Call()}, func() { calls = append(calls, "close pool") }, nil
+		})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	r := sdi.MustResolve[*userStore](cs)
+	if r.pool.dsn != "config" || !r.pool.inited {
+		t.Errorf("expected constructed and initialized pool, got %+v", r.pool)
+	}
+	if err := cs.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"new pool", "new store", "close store", "close pool"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected %v, got %v", expected, calls)
+	}
+	if err := cs.InitRequired(ctx); !errors.Is(err, sdi.ErrInvalidState) {
+		t.Errorf("expected restart of cleaned up objects to fail with %v, got %v", sdi.ErrInvalidState, err)
+	}
+	if s := cs.State(); s != sdi.StateStopped {
+		t.Errorf("expected %s, got %s", sdi.StateStopped, s)
+	}
+}
+
+func TestProvideFailure(t *testing.T) {
+	var closed bool
+	errDial := errors.New("dial failed")
+	cs := sdi.New()
+	cs.Provide(
+		func() (*connPool, func()) { return &connPool{}, func() { closed = true } },
+		func(p *connPool) (*userStore, error) { return nil, errDial })
+
+	err := cs.Build()
+	if !errors.Is(err, errDial) {
+		t.Errorf("expected %v, got %v", errDial, err)
+	}
+	if !closed {
+		t.Error("expected cleanup of constructed pool")
+	}
+}
+
+func TestProvideUnresolved(t *testing.T) {
+	cs := sdi.New()
+	cs.Provide(func(cl Client) *connPool { return &connPool{} })
+
+	err := cs.Build()
+	if !errors.Is(err, sdi.ErrUnresolvedDependency) || !strings.Contains(err.Error(), "sdi_test.Client") {
+		t.Errorf("expected unresolved Client, got %v", err)
+	}
+}
+
+func TestProvideCycle(t *testing.T) {
+	cs := sdi.New()
+	cs.Provide(
+		func(r *userStore) *connPool { return &connPool{} },
+		func(p *connPool) *userStore { return &userStore{} })
+
+	if err := cs.Build(); !errors.Is(err, sdi.ErrCycle) {
+		t.Errorf("expected %v, got %v", sdi.ErrCycle, err)
+	}
+}
+
+func TestProvideSingleResult(t *testing.T) {
+	cs := sdi.New()
+	cs.Provide(func() *connPool { return &connPool{dsn: "single"} })
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if p := sdi.MustResolve[*connPool](cs); p.dsn != "single" {
+		t.Errorf("expected constructed pool, got %+v", p)
+	}
+}
+
+func TestProvideWrongSignature(t *testing.T) {
+	for _, f := range []interface{}{nil, 42, func() {}, func() (*connPool, *connPool) { return nil, nil }} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for %T", f)
+				}
+			}()
+			sdi.New().Provide(f)
+		}()
+	}
+}
+
+type migrator struct {
+	pool *connPool
+}
+
+func (m *migrator) Init(ctx context.Context) error { return nil }
+
+type poolConsumer struct {
+	Pool     *connPool
+	Migrator *migrator
+}
+
+func (p *poolConsumer) Init(ctx context.Context) error { return nil }
+
+func TestProvideBundle(t *testing.T) {
+	var closed int
+	pc := &poolConsumer{}
+	cs := sdi.New()
+	cs.Add(pc, &client{name: "config"})
+	cs.Provide(func(cl Client) (*connPool, *migrator, func(), error) {
+		p := &connPool{dsn: cl.Call()}
+		return p, &migrator{pool: p}, func() { closed++ }, nil
+	})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if pc.Pool == nil || pc.Migrator == nil || pc.Migrator.pool != pc.Pool {
+		t.Fatalf("expected objects of the bundle to be injected, got %+v", pc)
+	}
+
+	ctx := context.Background()
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !pc.Pool.inited {
+		t.Error("expected objects of the bundle to be initialized")
+	}
+	if err := cs.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if closed != 1 {
+		t.Errorf("expected cleanup to be called once, got %d", closed)
+	}
+}
+
+func TestProvidePanic(t *testing.T) {
+	var closed bool
+	cs := sdi.New()
+	cs.Provide(
+		func() (*connPool, func()) { return &connPool{}, func() { closed = true } },
+		func(p *connPool) *userStore { panic("no store") })
+
+	var pe *sdi.PanicError
+	if err := cs.Build(); !errors.As(err, &pe) || pe.Phase != sdi.PhaseConstruct || pe.Value != "no store" {
+		t.Fatalf("expected constructor panic, got %v", err)
+	}
+	if !closed {
+		t.Error("expected cleanup of constructed pool")
+	}
+}
@@ -0,0 +1,46 @@
+package sdi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// setQualified injects into field fs the object added by AddNamed with name
+// qualifier. Objects of the parent container are used if there is no such
+// own object.
+func (c *SimpleContainer) setQualified(pos int, fs reflect.Value, field, qualifier string) error {
+	ft := fs.Type()
+	for i := range c.objects {
+		if pos == i || c.regs[i].name != qualifier {
+			continue
+		}
+		if !c.isAssignable(reflect.TypeOf(c.objects[i]), ft) {
+			return fmt.Errorf("sdi: %s.%s: object %q of type %T is not assignable to %s",
+				c.nameOf(pos), field, qualifier, c.objects[i], ft)
+		}
+		c.deps = append(c.deps, dependency{consumer: pos, provider: i, field: field, target: fs})
+		fs.Set(c.provide(ft, i))
+		return nil
+	}
+
+	if c.parent != nil {
+		if o, ok := c.parent.Get(qualifier); ok && reflect.TypeOf(o).AssignableTo(ft) {
+			fs.Set(reflect.ValueOf(o))
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s.%s: no object named %q", ErrUnresolvedDependency, c.nameOf(pos), field, qualifier)
+}
+
+// named returns the object added by AddNamed with the name.
+func (c *SimpleContainer) named(name string) (interface{}, bool) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	for i := range c.regs {
+		if c.regs[i].name == name {
+			return c.objects[i], true
+		}
+	}
+	return nil, false
+}
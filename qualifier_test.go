@@ -0,0 +1,57 @@
+package sdi_test
+
+import (
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type replicated struct {
+	Primary CI `sdi:"qualifier=primary"`
+	Replica CI `sdi:"qualifier=replica"`
+}
+
+func (r *replicated) Global() {}
+
+func TestQualifier(t *testing.T) {
+	cs := sdi.New()
+	primary, replica := C{gender: "primary"}, C{gender: "replica"}
+	r := replicated{}
+	cs.AddNamed("primary", &primary)
+	cs.AddNamed("replica", &replica)
+	cs.Add(&r)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Primary != &primary || r.Replica != &replica {
+		t.Error("expected qualified objects to be injected")
+	}
+}
+
+func TestQualifierNotFound(t *testing.T) {
+	cs := sdi.New()
+	cs.AddNamed("primary", &C{})
+	cs.Add(&replicated{})
+	if err := cs.Build(); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestQualifierParent(t *testing.T) {
+	parent := sdi.New()
+	primary, replica := C{}, C{}
+	parent.AddNamed("primary", &primary)
+	parent.AddNamed("replica", &replica)
+	parent.BuildDependencies()
+
+	child := parent.NewChild()
+	r := replicated{}
+	child.Add(&r)
+	if err := child.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if r.Primary != &primary || r.Replica != &replica {
+		t.Error("expected qualified objects of parent to be injected")
+	}
+}
@@ -0,0 +1,101 @@
+package sdi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Readier is the interface that wraps the basic Ready method.
+//
+// Ready returns channel closed when the object is ready to serve, e.g.
+// its listener accepts connections.
+type Readier interface {
+	Ready() <-chan struct{}
+}
+
+// WaitReady blocks until all containered objects implementing Readier
+// are ready or ctx is done. In the latter case error naming the first
+// not ready object is returned.
+func (c *SimpleContainer) WaitReady(ctx context.Context) error {
+	c.mux.RLock()
+	var (
+		rs    []Readier
+		names []string
+	)
+	for i := range c.objects {
+		if r, ok := c.objects[i].(Readier); ok {
+			rs = append(rs, r)
+			names = append(names, c.nameOf(i))
+		}
+	}
+	c.mux.RUnlock()
+
+	for i, r := range rs {
+		select {
+		case <-r.Ready():
+		case <-ctx.Done():
+			return fmt.Errorf("sdi: %s is not ready: %w", names[i], ctx.Err())
+		}
+	}
+	return nil
+}
+
+// WithReadinessGate delays Start of a runner until objects injected into
+// it or declared by its DependsOn, which implement Readier, are ready, e.g.
+// an API server waits for the cache warmer it depends on. If they are not
+// ready within timeout d, Start fails with error wrapping
+// context.DeadlineExceeded. Zero d means waiting until the context passed
+// to StartRunners is done.
+//
+// Runners are started after objects implementing Readier injected into
+// them, even if these have been added later. Build returns error wrapping
+// ErrCycle if such objects depend on each other.
+func WithReadinessGate(d time.Duration) Option {
+	return func(o *options) {
+		o.readinessGate = true
+		o.readinessTimeout = d
+	}
+}
+
+// awaitProviders waits until objects the runner at position i depends on,
+// which implement Readier, are ready, see WithReadinessGate.
+func (c *SimpleContainer) awaitProviders(ctx context.Context, i int) error {
+	if !c.opts.readinessGate {
+		return nil
+	}
+	c.mux.RLock()
+	var (
+		rs    []Readier
+		names []string
+		seen  = make(map[int]bool)
+	)
+	for _, d := range c.deps {
+		if d.consumer != i || seen[d.provider] {
+			continue
+		}
+		seen[d.provider] = true
+		if r, ok := c.objects[d.provider].(Readier); ok {
+			rs = append(rs, r)
+			names = append(names, c.nameOf(d.provider))
+		}
+	}
+	c.mux.RUnlock()
+	if len(rs) == 0 {
+		return nil
+	}
+
+	if d := c.opts.readinessTimeout; d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+	for k, r := range rs {
+		select {
+		case <-r.Ready():
+		case <-ctx.Done():
+			return fmt.Errorf("sdi: %s waits for %s to be ready: %w", c.nameOf(i), names[k], ctx.Err())
+		}
+	}
+	return nil
+}
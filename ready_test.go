@@ -0,0 +1,126 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+type listener struct {
+	ready chan struct{}
+	delay time.Duration
+}
+
+func newListener(delay time.Duration) *listener {
+	return &listener{ready: make(chan struct{}), delay: delay}
+}
+
+func (l *listener) Start(ctx context.Context) error {
+	go func() {
+		time.Sleep(l.delay)
+		close(l.ready)
+	}()
+	return nil
+}
+
+func (l *listener) Ready() <-chan struct{} {
+	return l.ready
+}
+
+func TestWaitReady(t *testing.T) {
+	cs := sdi.New()
+	cs.Add(newListener(time.Millisecond), newListener(5*time.Millisecond), &C{})
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+	if err := cs.StartRunners(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := cs.WaitReady(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWaitReadyTimeout(t *testing.T) {
+	cs := sdi.New()
+	cs.Add(newListener(time.Hour))
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+	if err := cs.StartRunners(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if err := cs.WaitReady(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+// gatedAPI records whether the cache was ready when it started.
+type gatedAPI struct {
+	Cache      sdi.Readier
+	readyFirst bool
+}
+
+func (a *gatedAPI) Start(ctx context.Context) error {
+	select {
+	case <-a.Cache.Ready():
+		a.readyFirst = true
+	default:
+	}
+	return nil
+}
+
+func TestReadinessGate(t *testing.T) {
+	ctx := context.Background()
+	api := &gatedAPI{}
+	cs := sdi.New(sdi.WithReadinessGate(time.Second))
+	cs.Add(newListener(10*time.Millisecond), api)
+	cs.BuildDependencies()
+	cs.InitRequired(ctx)
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !api.readyFirst {
+		t.Error("expected api started after cache is ready")
+	}
+}
+
+func TestReadinessGateOrder(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	api := &gatedAPI{}
+	cs := sdi.New(sdi.WithReadinessGate(0))
+	cs.Add(api, newListener(time.Millisecond))
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	cs.InitRequired(ctx)
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !api.readyFirst {
+		t.Error("expected api started after cache added later is ready")
+	}
+}
+
+func TestReadinessGateTimeout(t *testing.T) {
+	ctx := context.Background()
+	cs := sdi.New(sdi.WithReadinessGate(5 * time.Millisecond))
+	cs.AddNamed("cache", newListener(time.Hour))
+	cs.AddNamed("api", &gatedAPI{})
+	cs.BuildDependencies()
+	cs.InitRequired(ctx)
+
+	err := cs.StartRunners(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "api waits for cache to be ready") {
+		t.Errorf("expected readiness timeout, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package sdi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Reconfigurer is the interface that wraps the basic Reconfigure method.
+//
+// Reconfigure makes the object reload its configuration, e.g. log level
+// or rate limits, without restart.
+type Reconfigurer interface {
+	Reconfigure(ctx context.Context) error
+}
+
+// Reconfigure calls Reconfigure of objects implementing Reconfigurer,
+// except objects not initialized or stopped, in the order of Init calls,
+// so an object is reconfigured after objects it depends on. It's
+// typically called on SIGHUP or a config watcher event:
+//
+//	hup := make(chan os.Signal, 1)
+//	signal.Notify(hup, syscall.SIGHUP)
+//	for range hup {
+//		if err := c.Reconfigure(ctx); err != nil {
+//			log.Println(err)
+//		}
+//	}
+//
+// An error returned by Reconfigure does not break reconfiguring of
+// remaining objects, all errors are returned joined by errors.Join.
+//
+// Reconfigure emits Reconfiguring event before the calls and Reconfigured
+// event after them.
+//
+// Reconfigure must be called after InitRequired and before Stop, otherwise
+// error wrapping ErrInvalidState is returned. The container state is not
+// changed.
+func (c *SimpleContainer) Reconfigure(ctx context.Context) error {
+	if err := c.begin("Reconfigure", StateInitialized, StateStarted, StatePaused); err != nil {
+		return err
+	}
+	state := c.State()
+	c.emit(Event{Type: Reconfiguring})
+
+	var errs []error
+	for _, i := range c.sequence() {
+		r, ok := c.objects[i].(Reconfigurer)
+		if !ok {
+			continue
+		}
+		if _, in := c.objects[i].(Initializer); in && !c.stateOf(i).inited || c.stateOf(i).stopped {
+			continue
+		}
+		if err := r.Reconfigure(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("sdi: %s.Reconfigure: %w", c.nameOf(i), err))
+		}
+	}
+
+	c.end(state, nil)
+	err := errors.Join(errs...)
+	c.emit(Event{Type: Reconfigured, Err: err})
+	return err
+}
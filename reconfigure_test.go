@@ -0,0 +1,64 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type reconfigurable struct {
+	journaled
+	err error
+}
+
+func (r *reconfigurable) Reconfigure(ctx context.Context) error {
+	r.journal.calls = append(r.journal.calls, "reconfigure "+r.name)
+	return r.err
+}
+
+// reconfigurableAPI is reconfigured after objects it depends on.
+type reconfigurableAPI struct {
+	reconfigurable
+	deps []interface{}
+}
+
+func (r *reconfigurableAPI) DependsOn() []interface{} {
+	return r.deps
+}
+
+func TestReconfigure(t *testing.T) {
+	var jn journal
+	ctx := context.Background()
+	errLimits := errors.New("invalid limits")
+	api := &reconfigurableAPI{reconfigurable: reconfigurable{journaled: journaled{name: "api", journal: &jn}}}
+	limiter := &reconfigurable{journaled: journaled{name: "limiter", journal: &jn}, err: errLimits}
+	logger := &reconfigurable{journaled: journaled{name: "logger", journal: &jn}}
+	api.deps = []interface{}{logger}
+
+	cs := sdi.New()
+	cs.Add(limiter, api, logger)
+	if err := cs.Reconfigure(ctx); !errors.Is(err, sdi.ErrInvalidState) {
+		t.Errorf("expected %v, got %v", sdi.ErrInvalidState, err)
+	}
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	jn.calls = nil
+
+	if err := cs.Reconfigure(ctx); !errors.Is(err, errLimits) {
+		t.Errorf("expected %v, got %v", errLimits, err)
+	}
+	expected := []string{"reconfigure limiter", "reconfigure logger", "reconfigure api"}
+	if !reflect.DeepEqual(jn.calls, expected) {
+		t.Errorf("expected %v, got %v", expected, jn.calls)
+	}
+	if s := cs.State(); s != sdi.StateInitialized {
+		t.Errorf("expected %s, got %s", sdi.StateInitialized, s)
+	}
+}
@@ -0,0 +1,101 @@
+package sdi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// WiringRecord is the wiring resolved by BuildDependencies as written by
+// WithWiringRecord.
+type WiringRecord struct {
+	// Graph lists objects, injected fields and chosen objects.
+	Graph Graph `json:"graph"`
+
+	// InitOrder and StartOrder list names of objects in the order of Init
+	// and Start calls.
+	InitOrder  []string `json:"init_order"`
+	StartOrder []string `json:"start_order"`
+}
+
+// WithWiringRecord makes BuildDependencies write resolved wiring, see
+// WiringRecord, as JSON into file path, e.g. to keep it as evidence that
+// automatic injection resolves identically between builds. Failure to
+// write the file is returned by Build.
+func WithWiringRecord(path string) Option {
+	return func(o *options) {
+		o.recordPath = path
+	}
+}
+
+// WithWiringVerify makes BuildDependencies compare resolved wiring with
+// the one recorded into file path by WithWiringRecord. If they differ,
+// Build returns error wrapping ErrWiringChanged describing differences,
+// see Diff.
+func WithWiringVerify(path string) Option {
+	return func(o *options) {
+		o.verifyPath = path
+	}
+}
+
+// wiringRecord returns resolved wiring. The caller must hold read lock.
+func (c *SimpleContainer) wiringRecord() WiringRecord {
+	r := WiringRecord{Graph: c.graph()}
+	for _, i := range c.sequence() {
+		r.InitOrder = append(r.InitOrder, c.nameOf(i))
+	}
+	for _, i := range c.startSequence() {
+		r.StartOrder = append(r.StartOrder, c.nameOf(i))
+	}
+	return r
+}
+
+// recordWiring writes or verifies resolved wiring according to options.
+// The caller must hold write lock.
+func (c *SimpleContainer) recordWiring() error {
+	if c.opts.recordPath == "" && c.opts.verifyPath == "" {
+		return nil
+	}
+	r := c.wiringRecord()
+
+	if path := c.opts.recordPath; path != "" {
+		b, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return fmt.Errorf("sdi: wiring record: %w", err)
+		}
+		if err := os.WriteFile(path, append(b, '\n'), 0o644); err != nil {
+			return fmt.Errorf("sdi: wiring record: %w", err)
+		}
+	}
+
+	if path := c.opts.verifyPath; path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("sdi: wiring verify: %w", err)
+		}
+		var recorded WiringRecord
+		if err := json.Unmarshal(b, &recorded); err != nil {
+			return fmt.Errorf("sdi: wiring verify: %s: %w", path, err)
+		}
+		return verifyWiring(recorded, r)
+	}
+	return nil
+}
+
+// verifyWiring returns error wrapping ErrWiringChanged if wiring r differs
+// from recorded.
+func verifyWiring(recorded, r WiringRecord) error {
+	var errs []error
+	if d := DiffGraphs(recorded.Graph, r.Graph); !d.Empty() {
+		errs = append(errs, fmt.Errorf("%w:\n%s", ErrWiringChanged, strings.TrimSuffix(d.String(), "\n")))
+	}
+	if strings.Join(recorded.InitOrder, ",") != strings.Join(r.InitOrder, ",") {
+		errs = append(errs, fmt.Errorf("%w: init order %v, recorded %v", ErrWiringChanged, r.InitOrder, recorded.InitOrder))
+	}
+	if strings.Join(recorded.StartOrder, ",") != strings.Join(r.StartOrder, ",") {
+		errs = append(errs, fmt.Errorf("%w: start order %v, recorded %v", ErrWiringChanged, r.StartOrder, recorded.StartOrder))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,56 @@
+package sdi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestWiringRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wiring.json")
+
+	cs := sdi.New(sdi.WithWiringRecord(path))
+	cs.AddNamed("a", &A{})
+	cs.AddNamed("b", &B{})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r sdi.WiringRecord
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.InitOrder, []string{"a", "b"}) || len(r.Graph.Edges) != 1 {
+		t.Errorf("unexpected record %s", b)
+	}
+
+	same := sdi.New(sdi.WithWiringVerify(path))
+	same.AddNamed("a", &A{})
+	same.AddNamed("b", &B{})
+	if err := same.Build(); err != nil {
+		t.Errorf("expected the same wiring, got %v", err)
+	}
+
+	changed := sdi.New(sdi.WithWiringVerify(path))
+	changed.AddNamed("b", &B{})
+	changed.AddNamed("a2", &A{})
+	err = changed.Build()
+	if !errors.Is(err, sdi.ErrWiringChanged) {
+		t.Fatalf("expected %v, got %v", sdi.ErrWiringChanged, err)
+	}
+	for _, s := range []string{"+ a2", "- a", "~ b.AService: a -> a2", "init order [b a2], recorded [a b]"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("expected %q in %q", s, err)
+		}
+	}
+}
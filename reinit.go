@@ -0,0 +1,23 @@
+package sdi
+
+// resetStates forgets lifecycle state of containered objects, and the
+// fatal runner error not received from Done, before InitRequired is
+// called again after Stop, so the container is brought up as if it's
+// never been started. Times of the last Stop are kept for Status.
+func (c *SimpleContainer) resetStates() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for i := range c.states {
+		if c.states[i].cancel != nil {
+			c.states[i].cancel()
+		}
+		c.states[i] = objectState{stoppedAt: c.states[i].stoppedAt}
+	}
+	if c.done != nil {
+		select {
+		case <-c.done:
+		default:
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+func TestReinitAfterStop(t *testing.T) {
+	ctx := context.Background()
+	var jn journal
+	ic := initCounter{}
+	cs := sdi.New()
+	cs.Add(&journaled{name: "a", journal: &jn}, &ic, &C{})
+	cs.Provide(func() *connPool { return &connPool{} })
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	for round := 1; round <= 3; round++ {
+		jn.calls = nil
+		if err := cs.InitRequired(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := cs.StartRunners(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if st := cs.Statuses()[0]; !st.Started || st.Stopped {
+			t.Errorf("round %d: expected object to be reported started, got %+v", round, st)
+		}
+		if err := cs.Stop(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"init a", "start a", "stop a"}; !reflect.DeepEqual(jn.calls, want) {
+			t.Errorf("round %d: expected %v, got %v", round, want, jn.calls)
+		}
+		if ic.inits != round {
+			t.Errorf("round %d: expected object without Stop to be initialized again, got %d inits", round, ic.inits)
+		}
+	}
+}
+
+// fatalRunner fails once and is not restarted.
+type fatalRunner struct {
+	fail bool
+}
+
+func (f *fatalRunner) Start(ctx context.Context) error {
+	if f.fail {
+		f.fail = false
+		return errors.New("broken pipe")
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func TestRunAgain(t *testing.T) {
+	fr := fatalRunner{fail: true}
+	cs := sdi.New(sdi.WithManagedRunners())
+	cs.Add(&fr)
+
+	if err := cs.Run(context.Background()); err == nil {
+		t.Fatal("expected error of the fatal runner")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := cs.Run(ctx); err != nil {
+		t.Fatalf("expected fatal error of previous run to be forgotten, got %v", err)
+	}
+	if s := cs.State(); s != sdi.StateStopped {
+		t.Errorf("expected %s, got %s", sdi.StateStopped, s)
+	}
+}
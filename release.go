@@ -0,0 +1,53 @@
+package sdi
+
+import "fmt"
+
+// Release drops references to containered objects, their dependencies,
+// constructors, bindings, decorators, subscribers, observers and cached
+// reflection state, as well as objects of the original container if
+// the container is a clone, so objects can be garbage collected even if
+// the container itself is still referenced, e.g. by supervisors building
+// many short-lived containers.
+//
+// Release must be called after Stop, otherwise error wrapping
+// ErrInvalidState is returned. Other options of the container are kept.
+// The container stays in StateStopped, has no objects and can not be
+// built again.
+func (c *SimpleContainer) Release() error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.state != StateStopped || c.running != "" {
+		return fmt.Errorf("%w: Release called in state %s", ErrInvalidState, c.state)
+	}
+
+	c.cache = nil
+	c.objects = nil
+	c.regs = nil
+	c.deps = nil
+	c.missing = nil
+	c.parent = nil
+	c.states = nil
+	c.order = nil
+	c.startOrder = nil
+	c.stopOrder = nil
+	c.bindings = nil
+	c.loggers = nil
+	c.gates = nil
+	c.transients = nil
+	c.providers = nil
+	c.cleanups = nil
+	c.ctxFields = nil
+	c.decorators = nil
+	c.copies = nil
+	c.opts.observers = nil
+
+	c.decoMux.Lock()
+	c.decorated = nil
+	c.decoMux.Unlock()
+
+	c.subs.mux.Lock()
+	c.subs.fns = nil
+	c.subs.mux.Unlock()
+	return nil
+}
@@ -0,0 +1,90 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+func TestRelease(t *testing.T) {
+	ctx := context.Background()
+	cs := sdi.New()
+	cs.Add(&A{}, &B{})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.Release(); !errors.Is(err, sdi.ErrInvalidState) {
+		t.Errorf("expected %v before Stop, got %v", sdi.ErrInvalidState, err)
+	}
+	if n := len(cs.Objects()); n != 2 {
+		t.Fatalf("expected 2 objects, got %d", n)
+	}
+
+	if err := cs.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(cs.Objects()); n != 0 {
+		t.Errorf("expected no objects after Release, got %d", n)
+	}
+	if s := cs.State(); s != sdi.StateStopped {
+		t.Errorf("expected %s, got %s", sdi.StateStopped, s)
+	}
+	if err := cs.Stop(ctx); err != nil {
+		t.Errorf("expected released container to stop, got %v", err)
+	}
+}
+
+type releasable struct {
+	buf [64]byte
+}
+
+func (r *releasable) Global() {}
+
+func TestReleaseClone(t *testing.T) {
+	var collected atomic.Int32
+	finalized := func(*releasable) { collected.Add(1) }
+	ctx := context.Background()
+
+	cs := func() *sdi.SimpleContainer {
+		orig := &releasable{}
+		runtime.SetFinalizer(orig, finalized)
+		base := sdi.New()
+		base.Add(orig)
+
+		cs := base.Clone()
+		if err := cs.Build(); err != nil {
+			t.Fatal(err)
+		}
+		cp := sdi.MustResolve[*releasable](cs)
+		runtime.SetFinalizer(cp, finalized)
+		cs.Subscribe(func(sdi.Event) { _ = cp })
+		cs.InitRequired(ctx)
+		if err := cs.Stop(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := cs.Release(); err != nil {
+			t.Fatal(err)
+		}
+		return cs
+	}()
+
+	for k := 0; k < 50 && collected.Load() < 2; k++ {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	if n := collected.Load(); n != 2 {
+		t.Errorf("expected original object and its copy to be collected, got %d collected", n)
+	}
+	runtime.KeepAlive(cs)
+}
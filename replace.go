@@ -0,0 +1,46 @@
+package sdi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Replace replaces containered object old with object new. The new object
+// takes position and registration details (name, module) of the old one,
+// therefore it's injected everywhere the old one would be.
+//
+// Replace is intended for tests substituting fakes for production
+// implementations and must be called before BuildDependencies, otherwise
+// error wrapping ErrInvalidState is returned. If new can't be containered
+// error wrapping ErrNotContainerable is returned.
+func (c *SimpleContainer) Replace(old, new interface{}) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.built {
+		return fmt.Errorf("%w: %T replaced after BuildDependencies", ErrInvalidState, old)
+	}
+	if err := containerable(new); err != nil {
+		return err
+	}
+
+	i := c.indexOf(old)
+	if i < 0 {
+		return fmt.Errorf("sdi: %T is not containered", old)
+	}
+	c.objects[i] = new
+	return nil
+}
+
+// indexOf returns position of the object o or -1 if o is not containered.
+func (c *SimpleContainer) indexOf(o interface{}) int {
+	if o == nil || !reflect.TypeOf(o).Comparable() {
+		return -1
+	}
+	for i := range c.objects {
+		if reflect.TypeOf(c.objects[i]).Comparable() && c.objects[i] == o {
+			return i
+		}
+	}
+	return -1
+}
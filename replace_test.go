@@ -0,0 +1,40 @@
+package sdi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type fakeC struct {
+	C
+}
+
+func TestReplace(t *testing.T) {
+	cs := sdi.New()
+	b := B{}
+	c := C{}
+	cs.Add(&A{}, &b, &c)
+
+	if err := cs.Replace(&A{}, &fakeC{}); err == nil {
+		t.Error("expected error replacing not containered object")
+	}
+	if err := cs.Replace(&c, &struct{}{}); !errors.Is(err, sdi.ErrNotContainerable) {
+		t.Errorf("expected ErrNotContainerable, got %v", err)
+	}
+
+	fake := fakeC{}
+	if err := cs.Replace(&c, &fake); err != nil {
+		t.Fatal(err)
+	}
+	cs.BuildDependencies()
+
+	if b.CService != &fake {
+		t.Error("expected fake to be injected")
+	}
+
+	if err := cs.Replace(&c, &fake); !errors.Is(err, sdi.ErrInvalidState) {
+		t.Errorf("expected ErrInvalidState replacing after BuildDependencies, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package sdi
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Report describes startup of the container, see StartupReport.
+type Report struct {
+	// Objects are listed in the order of Init calls.
+	Objects []ObjectReport
+}
+
+// ObjectReport describes startup of a containered object.
+type ObjectReport struct {
+	Name string
+
+	// Durations of the last Init and Start calls, zero if not called.
+	Init  time.Duration
+	Start time.Duration
+
+	// Dependencies lists objects injected into the object's fields.
+	Dependencies []Injection
+
+	// Warnings lists problems worth attention, e.g. fields left nil
+	// by BuildDependencies or failed Init.
+	Warnings []string
+}
+
+// StartupReport returns report about containered objects: durations
+// of Init and Start, injected dependencies and warnings. It's intended
+// to be logged after InitRequired and StartRunners.
+func (c *SimpleContainer) StartupReport() Report {
+	objects := c.Objects()
+
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	var r Report
+	for _, i := range c.sequence() {
+		or := ObjectReport{
+			Name:         objects[i].Name,
+			Dependencies: objects[i].Dependencies,
+		}
+		if i < len(c.states) {
+			s := c.states[i]
+			or.Init, or.Start = s.initTime, s.startTime
+			if s.initErr != nil {
+				or.Warnings = append(or.Warnings, fmt.Sprintf("Init failed: %v", s.initErr))
+			}
+			if s.lastErr != nil {
+				or.Warnings = append(or.Warnings, fmt.Sprintf("Start failed: %v", s.lastErr))
+			}
+		}
+		if !c.used(i) {
+			or.Warnings = append(or.Warnings, "not used: neither injected nor Initializer or Runner")
+		}
+		for _, m := range c.missing {
+			if m.consumer == i {
+				or.Warnings = append(or.Warnings, fmt.Sprintf("%s is not injected", m.field))
+			}
+		}
+		r.Objects = append(r.Objects, or)
+	}
+	return r
+}
+
+// String returns the report formatted as a table.
+func (r Report) String() string {
+	var b strings.Builder
+	r.WriteTo(&b)
+	return b.String()
+}
+
+// WriteTo writes the report formatted as a table into w.
+func (r Report) WriteTo(w io.Writer) (int64, error) {
+	cw := countingWriter{w: w}
+	tw := tabwriter.NewWriter(&cw, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "OBJECT\tINIT\tSTART\tDEPENDENCIES")
+	for _, o := range r.Objects {
+		deps := make([]string, len(o.Dependencies))
+		for k, d := range o.Dependencies {
+			deps[k] = d.Field + "=" + d.Provider
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", o.Name, o.Init, o.Start, strings.Join(deps, ", "))
+	}
+	if err := tw.Flush(); err != nil {
+		return cw.n, err
+	}
+	for _, o := range r.Objects {
+		for _, wr := range o.Warnings {
+			if _, err := fmt.Fprintf(&cw, "warning: %s: %s\n", o.Name, wr); err != nil {
+				return cw.n, err
+			}
+		}
+	}
+	return cw.n, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,41 @@
+package sdi_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestStartupReport(t *testing.T) {
+	cs := sdi.New()
+	cs.AddNamed("counter", &initCounter{})
+	cs.AddNamed("node", &barrierNode{barrier: &barrier{n: 1, ch: make(chan struct{})}})
+	cs.AddNamed("bus", &eventBus{})
+	cs.BuildDependencies()
+	ctx := context.Background()
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	r := cs.StartupReport()
+	if len(r.Objects) != 3 {
+		t.Fatalf("expected 3 objects, got %d", len(r.Objects))
+	}
+	counter := r.Objects[0]
+	if counter.Name != "counter" || len(counter.Dependencies) != 1 || counter.Dependencies[0].Provider != "node" {
+		t.Errorf("unexpected report %+v", counter)
+	}
+
+	if w := r.Objects[2].Warnings; len(w) != 2 || w[1] != "Handle is not injected" {
+		t.Errorf("unexpected warnings %v", w)
+	}
+
+	s := r.String()
+	for _, line := range []string{"OBJECT", "Dep=node", "warning: bus: Handle is not injected"} {
+		if !strings.Contains(s, line) {
+			t.Errorf("expected report to contain %q:\n%s", line, s)
+		}
+	}
+}
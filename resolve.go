@@ -0,0 +1,138 @@
+package sdi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// resolver is implemented by containers able to look up containered
+// objects by type.
+type resolver interface {
+	resolve(reflect.Type) (interface{}, error)
+}
+
+// Resolve returns containered object assignable to type T. T is usually
+// an interface type, but a pointer to a concrete type works as well.
+//
+// If several objects are assignable to T, the object bound to T by Bind
+// is returned, otherwise the choice is made according to the resolution
+// policy, see WithResolutionPolicy. By default error wrapping
+// ErrAmbiguous is returned. If no containered object is assignable to T,
+// a new object is created by the factory registered by AddTransient.
+// Child container falls back to its parent if no own object is
+// assignable to T.
+func Resolve[T any](c Container) (T, error) {
+	var zero T
+
+	r, ok := c.(resolver)
+	if !ok {
+		return zero, fmt.Errorf("sdi: %T does not support resolving", c)
+	}
+
+	o, err := r.resolve(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return zero, err
+	}
+	return o.(T), nil
+}
+
+// MustResolve is like Resolve but panics if the object can not be resolved.
+func MustResolve[T any](c Container) T {
+	o, err := Resolve[T](c)
+	if err != nil {
+		panic(err)
+	}
+	return o
+}
+
+func (c *SimpleContainer) resolve(t reflect.Type) (interface{}, error) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	return c.lookup(t, nil)
+}
+
+// lookup returns containered object or new transient object assignable
+// to type t. Types of transient objects being created are listed in
+// creating. The caller must hold read lock.
+func (c *SimpleContainer) lookup(t reflect.Type, creating []reflect.Type) (interface{}, error) {
+	i, err := c.chosen(t)
+	if err != nil {
+		return nil, err
+	}
+	if i >= 0 {
+		return c.provide(t, i).Interface(), nil
+	}
+	if o, ok := c.newTransient(t, creating); ok {
+		return o, nil
+	}
+	if c.parent != nil {
+		return c.parent.resolve(t)
+	}
+	if b, ok := c.buildInfoFor(t); ok {
+		return b, nil
+	}
+	if o, ok, err := c.fallback(t); ok || err != nil {
+		return o, err
+	}
+	return nil, fmt.Errorf("%w: no object assignable to %s", ErrUnresolvedDependency, t)
+}
+
+// chosen returns position of the object assignable to type t chosen
+// the same way as by BuildDependencies or -1 if there is no such object.
+// Unlike candidate it doesn't use type cache, therefore the caller must
+// hold only read lock.
+func (c *SimpleContainer) chosen(t reflect.Type) (int, error) {
+	if i, ok := c.bound(t); ok {
+		return i, nil
+	}
+
+	var found []int
+	for i := range c.objects {
+		if !c.regs[i].notInjectable && reflect.TypeOf(c.objects[i]).AssignableTo(t) {
+			found = append(found, i)
+		}
+	}
+	return c.choose(t, found)
+}
+
+// Get returns the object added by AddNamed with the name. Child container
+// falls back to its parent if it has no object with the name.
+func (c *SimpleContainer) Get(name string) (interface{}, bool) {
+	for p := c; p != nil; p = p.parent {
+		if o, ok := p.named(name); ok {
+			return o, true
+		}
+	}
+	return nil, false
+}
+
+// GetByType returns containered object assignable to type t.
+// It returns false if there is no such object or the choice among several
+// objects is ambiguous, see Resolve.
+func (c *SimpleContainer) GetByType(t reflect.Type) (interface{}, bool) {
+	o, err := c.resolve(t)
+	return o, err == nil
+}
+
+// As finds containered object assignable to the value pointed to by target,
+// and if one is found, sets target to that object and returns true.
+// Otherwise, it returns false.
+//
+// As panics if target is not a non-nil pointer, the same way errors.As does.
+func (c *SimpleContainer) As(target interface{}) bool {
+	if target == nil {
+		panic("sdi: target cannot be nil")
+	}
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		panic("sdi: target must be a non-nil pointer")
+	}
+
+	o, err := c.resolve(v.Type().Elem())
+	if err != nil {
+		return false
+	}
+	v.Elem().Set(reflect.ValueOf(o))
+	return true
+}
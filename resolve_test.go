@@ -0,0 +1,83 @@
+package sdi_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestResolve(t *testing.T) {
+	cs := sdi.New()
+	a := A{age: 7}
+	c := C{}
+	cs.Add(&a, &c)
+
+	ai, err := sdi.Resolve[AI](cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ai.Age() != 7 {
+		t.Errorf("expected age 7, got %d", ai.Age())
+	}
+
+	if pc := sdi.MustResolve[*C](cs); pc != &c {
+		t.Error("expected pointer to c")
+	}
+
+	if _, err := sdi.Resolve[BI](cs); err == nil {
+		t.Error("expected error for unresolvable type")
+	}
+}
+
+func TestMustResolvePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	sdi.MustResolve[BI](sdi.New())
+}
+
+func TestAs(t *testing.T) {
+	cs := sdi.New()
+	c := C{gender: "F"}
+	cs.Add(&c)
+
+	var ci CI
+	if !cs.As(&ci) {
+		t.Fatal("expected CI to be found")
+	}
+	if ci.Gender() != "F" {
+		t.Errorf("expected gender F, got %q", ci.Gender())
+	}
+
+	var ai AI
+	if cs.As(&ai) {
+		t.Error("expected AI not to be found")
+	}
+
+	o, ok := cs.GetByType(reflect.TypeOf((*CI)(nil)).Elem())
+	if !ok || o != &c {
+		t.Error("expected GetByType to return c")
+	}
+}
+
+func TestGet(t *testing.T) {
+	parent := sdi.New()
+	db := C{}
+	parent.AddNamed("db", &db)
+	child := parent.NewChild()
+	cache := C{}
+	child.AddNamed("cache", &cache)
+
+	if o, ok := child.Get("cache"); !ok || o != &cache {
+		t.Error("expected own named object")
+	}
+	if o, ok := child.Get("db"); !ok || o != &db {
+		t.Error("expected named object of parent")
+	}
+	if _, ok := parent.Get("cache"); ok {
+		t.Error("expected no object")
+	}
+}
@@ -0,0 +1,74 @@
+package sdi
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RetryPolicy configures retries of failed Init calls, see WithInitRetry.
+type RetryPolicy struct {
+	// MaxRetries limits number of retries of Init. Zero means Init is
+	// not retried, negative value means no limit.
+	MaxRetries int
+
+	// InitialBackoff is the delay before the first retry. Every next
+	// delay is doubled up to MaxBackoff. Defaults are 100ms and 30s.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// InitRetrier is the interface that wraps the basic InitRetryPolicy method.
+//
+// InitRetryPolicy returns retry policy of Init of the object. It overrides
+// the container default set by WithInitRetry.
+type InitRetrier interface {
+	InitRetryPolicy() RetryPolicy
+}
+
+// initRetryPolicy returns Init retry policy of the object o.
+func (c *SimpleContainer) initRetryPolicy(o interface{}) RetryPolicy {
+	if ir, ok := o.(InitRetrier); ok {
+		return ir.InitRetryPolicy()
+	}
+	return c.opts.initRetry
+}
+
+// callWithRetry calls f until it returns nil, panics or retries allowed
+// by policy p are exhausted, waiting with exponential backoff between
+// calls. If ctx is done while waiting, the last error is returned.
+func (c *SimpleContainer) callWithRetry(ctx context.Context, p RetryPolicy, call string, f func(context.Context) error) error {
+	backoff := p.InitialBackoff
+	if backoff <= 0 {
+		backoff = defaultInitialBackoff
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	for retries := 0; ; retries++ {
+		err := f(ctx)
+		var pe *PanicError
+		if err == nil || errors.As(err, &pe) || ctx.Err() != nil {
+			return err
+		}
+		if p.MaxRetries >= 0 && retries >= p.MaxRetries {
+			return err
+		}
+
+		c.logf("sdi: %s failed, retrying in %s: %v", call, backoff, err)
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
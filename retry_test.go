@@ -0,0 +1,77 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+// unreachable fails Init several times.
+type unreachable struct {
+	failures int
+	calls    int
+}
+
+func (u *unreachable) Init(ctx context.Context) error {
+	u.calls++
+	if u.calls <= u.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+type retrying struct {
+	unreachable
+}
+
+func (r *retrying) InitRetryPolicy() sdi.RetryPolicy {
+	return sdi.RetryPolicy{MaxRetries: 5, InitialBackoff: time.Millisecond}
+}
+
+func TestInitRetryPolicy(t *testing.T) {
+	u := unreachable{failures: 2}
+	cs := sdi.New(sdi.WithInitRetry(sdi.RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond}))
+	cs.Add(&u)
+	cs.BuildDependencies()
+	if err := cs.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if u.calls != 3 {
+		t.Errorf("expected 3 Init calls, got %d", u.calls)
+	}
+
+	u = unreachable{failures: 3}
+	cs = sdi.New(sdi.WithInitRetry(sdi.RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond}))
+	cs.Add(&u)
+	cs.BuildDependencies()
+	if err := cs.InitRequired(context.Background()); err == nil {
+		t.Error("expected error after retries are exhausted")
+	}
+	if u.calls != 3 {
+		t.Errorf("expected 3 Init calls, got %d", u.calls)
+	}
+}
+
+func TestInitRetrier(t *testing.T) {
+	r := retrying{unreachable{failures: 4}}
+	u := unreachable{failures: 1}
+	cs := sdi.New()
+	cs.Add(&r)
+	cs.BuildDependencies()
+	if err := cs.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if r.calls != 5 {
+		t.Errorf("expected 5 Init calls, got %d", r.calls)
+	}
+
+	cs = sdi.New()
+	cs.Add(&u)
+	cs.BuildDependencies()
+	if err := cs.InitRequired(context.Background()); err == nil || u.calls != 1 {
+		t.Errorf("expected Init not to be retried by default, got %v after %d calls", err, u.calls)
+	}
+}
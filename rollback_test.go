@@ -0,0 +1,131 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type failingJournaled struct {
+	journaled
+	initErr  error
+	startErr error
+}
+
+func (f *failingJournaled) Init(ctx context.Context) error {
+	if f.initErr != nil {
+		return f.initErr
+	}
+	return f.journaled.Init(ctx)
+}
+
+func (f *failingJournaled) Start(ctx context.Context) error {
+	if f.startErr != nil {
+		return f.startErr
+	}
+	return f.journaled.Start(ctx)
+}
+
+func TestRollbackOnStartFailure(t *testing.T) {
+	var jn journal
+	errStart := errors.New("address in use")
+
+	cs := sdi.New()
+	cs.Add(&journaled{name: "a", journal: &jn}, &journaled{name: "b", journal: &jn})
+	cs.Add(&failingJournaled{journaled: journaled{name: "c", journal: &jn}, startErr: errStart})
+	cs.Add(&journaled{name: "d", journal: &jn})
+	cs.BuildDependencies()
+
+	if err := cs.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.StartRunners(context.Background()); !errors.Is(err, errStart) {
+		t.Fatalf("expected %v, got %v", errStart, err)
+	}
+
+	expected := []string{
+		"init a", "init b", "init c", "init d",
+		"start a", "start b",
+		"stop b", "stop a",
+	}
+	if !reflect.DeepEqual(jn.calls, expected) {
+		t.Errorf("expected %v, got %v", expected, jn.calls)
+	}
+
+	// Stop stops the remaining objects only.
+	jn.calls = nil
+	if err := cs.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"stop d", "stop c"}; !reflect.DeepEqual(jn.calls, expected) {
+		t.Errorf("expected %v, got %v", expected, jn.calls)
+	}
+}
+
+func TestRollbackOnInitFailure(t *testing.T) {
+	var jn journal
+	errInit := errors.New("bad config")
+
+	cs := sdi.New()
+	cs.Add(&journaled{name: "a", journal: &jn}, &journaled{name: "b", journal: &jn})
+	cs.Add(&failingJournaled{journaled: journaled{name: "c", journal: &jn}, initErr: errInit})
+	cs.BuildDependencies()
+
+	if err := cs.InitRequired(context.Background()); !errors.Is(err, errInit) {
+		t.Fatalf("expected %v, got %v", errInit, err)
+	}
+
+	expected := []string{"init a", "init b", "stop b", "stop a"}
+	if !reflect.DeepEqual(jn.calls, expected) {
+		t.Errorf("expected %v, got %v", expected, jn.calls)
+	}
+}
+
+func TestContinueOnStartError(t *testing.T) {
+	errBroker := errors.New("no broker")
+	errCache := errors.New("no cache")
+	for _, concurrent := range []bool{false, true} {
+		var jn journal
+		broker := &failingJournaled{journaled: journaled{name: "broker", journal: &jn}, startErr: errBroker}
+		cache := &failingJournaled{journaled: journaled{name: "cache", journal: &jn}, startErr: errCache}
+		consumer := &dependent{journaled: journaled{name: "consumer", journal: &jn}, deps: []interface{}{broker}}
+		api := &journaled{name: "api", journal: &jn}
+
+		ctx := context.Background()
+		cs := sdi.New(sdi.WithContinueOnStartError())
+		cs.Add(broker, cache, consumer, api)
+		cs.BuildDependencies()
+		cs.InitRequired(ctx)
+
+		var err error
+		if concurrent {
+			err = cs.StartRunnersConcurrent(ctx)
+		} else {
+			err = cs.StartRunners(ctx)
+		}
+		if !errors.Is(err, errBroker) || !errors.Is(err, errCache) {
+			t.Errorf("expected both errors, got %v", err)
+		}
+		if cs.State() != sdi.StateStarted {
+			t.Errorf("expected state %s, got %s", sdi.StateStarted, cs.State())
+		}
+		if st := cs.Status(api); !st.Started || st.Failed {
+			t.Errorf("expected api to be started, got %+v", st)
+		}
+		if st := cs.Status(cache); !st.Failed || !errors.Is(st.FailError, errCache) {
+			t.Errorf("expected cache to be failed, got %+v", st)
+		}
+		if concurrent {
+			continue
+		}
+		if st := cs.Status(consumer); st.Started || !st.Failed {
+			t.Errorf("expected consumer of failed broker to be skipped, got %+v", st)
+		}
+		if h := cs.Health(ctx); h["consumer"] == nil && h["*sdi_test.dependent"] == nil {
+			t.Errorf("expected skipped consumer to be unhealthy, got %v", h)
+		}
+	}
+}
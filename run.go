@@ -0,0 +1,68 @@
+package sdi
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Run performs complete container lifecycle: links objects, inits
+// them, starts runners and blocks until ctx is done or a managed runner
+// fails, see Done. After that all objects implementing Stopper are
+// stopped in reverse order and the runner error, if any, is returned
+// joined with Stop errors.
+//
+// If Init or Start fails, objects initialized or started so far are
+// stopped and the error is returned.
+//
+// Context passed to Init and Start is ctx, therefore runners observe its
+// cancellation as a signal for graceful shutdown. Options opts are passed
+// to StartRunnersWith.
+//
+// Run can be called again after it returns, dependencies are not built
+// again then, see InitRequired.
+func (c *SimpleContainer) Run(ctx context.Context, opts ...StartOption) error {
+	if c.State() != StateStopped {
+		if err := c.Build(); err != nil {
+			return err
+		}
+	}
+
+	if err := c.InitRequired(ctx); err != nil {
+		return err
+	}
+
+	if err := c.StartRunnersWith(ctx, opts...); err != nil {
+		if serr := c.Stop(context.Background()); serr != nil {
+			return errors.Join(err, serr)
+		}
+		return err
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-c.Done():
+	}
+
+	if serr := c.Stop(context.Background()); serr != nil {
+		return errors.Join(err, serr)
+	}
+	return err
+}
+
+// RunUntilSignal is like Run but runs the container until one of
+// the signals sig arrives. If no signals are passed os.Interrupt and
+// syscall.SIGTERM are used.
+func (c *SimpleContainer) RunUntilSignal(sig ...os.Signal) error {
+	if len(sig) == 0 {
+		sig = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), sig...)
+	defer stop()
+
+	return c.Run(ctx)
+}
@@ -0,0 +1,53 @@
+package sdi_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+// journal records lifecycle calls of journaled objects.
+type journal struct {
+	calls []string
+}
+
+type journaled struct {
+	name    string
+	journal *journal
+}
+
+func (j *journaled) Init(ctx context.Context) error {
+	j.journal.calls = append(j.journal.calls, "init "+j.name)
+	return nil
+}
+
+func (j *journaled) Start(ctx context.Context) error {
+	j.journal.calls = append(j.journal.calls, "start "+j.name)
+	return nil
+}
+
+func (j *journaled) Stop(ctx context.Context) error {
+	j.journal.calls = append(j.journal.calls, "stop "+j.name)
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	var jn journal
+	cs := sdi.New()
+	cs.Add(&journaled{name: "a", journal: &jn}, &journaled{name: "b", journal: &jn})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := cs.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"init a", "init b", "start a", "start b", "stop b", "stop a"}
+	if !reflect.DeepEqual(jn.calls, expected) {
+		t.Errorf("expected %v, got %v", expected, jn.calls)
+	}
+}
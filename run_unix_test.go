@@ -0,0 +1,30 @@
+//go:build linux || darwin || freebsd
+
+package sdi_test
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+func TestRunUntilSignal(t *testing.T) {
+	var jn journal
+	cs := sdi.New()
+	cs.Add(&journaled{name: "a", journal: &jn})
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	}()
+
+	if err := cs.RunUntilSignal(syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(jn.calls) != 3 || jn.calls[2] != "stop a" {
+		t.Errorf("unexpected calls %v", jn.calls)
+	}
+}
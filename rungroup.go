@@ -0,0 +1,79 @@
+package sdi
+
+import "fmt"
+
+// StartOption configures a StartRunnersWith call.
+type StartOption func(*startOptions)
+
+type startOptions struct {
+	groups map[string]bool
+}
+
+// Groups makes StartRunnersWith start only runners added by AddGrouped into
+// one of groups and runners added without group. Other runners are
+// neither started nor reported as failed.
+//
+//	c.StartRunnersWith(ctx, sdi.Groups("http"))
+func Groups(groups ...string) StartOption {
+	return func(so *startOptions) {
+		if so.groups == nil {
+			so.groups = make(map[string]bool, len(groups))
+		}
+		for _, g := range groups {
+			so.groups[g] = true
+		}
+	}
+}
+
+func newStartOptions(opts []StartOption) startOptions {
+	var so startOptions
+	for _, opt := range opts {
+		opt(&so)
+	}
+	return so
+}
+
+// AddGrouped adds objects into container as a part of the group, e.g.
+// "http", "workers" or "cron". Runners of the group can be started
+// selectively, see Groups. It panics if the group is empty, and in
+// the same cases as Add.
+func (c *SimpleContainer) AddGrouped(group string, o ...interface{}) {
+	if group == "" {
+		panic("sdi: empty group name")
+	}
+
+	o, r := withAddOptions(o, registration{group: group})
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for i := range o {
+		mustBeContainerable(o[i])
+		c.add(o[i], r)
+	}
+}
+
+// selected reports whether the runner at position i is started with
+// options so.
+func (c *SimpleContainer) selected(i int, so startOptions) bool {
+	g := c.regs[i].group
+	return so.groups == nil || g == "" || so.groups[g]
+}
+
+// checkGroups returns error if no object is added into a group selected
+// by options so.
+func (c *SimpleContainer) checkGroups(so startOptions) error {
+	for g := range so.groups {
+		found := false
+		for i := range c.regs {
+			if c.regs[i].group == g {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("sdi: unknown group %q", g)
+		}
+	}
+	return nil
+}
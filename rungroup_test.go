@@ -0,0 +1,49 @@
+package sdi_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestGroups(t *testing.T) {
+	var jn journal
+	ctx := context.Background()
+	cs := sdi.New()
+	cs.AddGrouped("http", &journaled{name: "api", journal: &jn})
+	cs.AddGrouped("workers", &journaled{name: "worker", journal: &jn})
+	cs.Add(&journaled{name: "metrics", journal: &jn})
+	cs.BuildDependencies()
+	cs.InitRequired(ctx)
+	jn.calls = nil
+
+	if err := cs.StartRunnersWith(ctx, sdi.Groups("http")); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"start api", "start metrics"}
+	if !reflect.DeepEqual(jn.calls, expected) {
+		t.Errorf("expected %v, got %v", expected, jn.calls)
+	}
+	if g := cs.Objects()[1].Group; g != "workers" {
+		t.Errorf("expected group workers, got %q", g)
+	}
+}
+
+func TestGroupsUnknown(t *testing.T) {
+	ctx := context.Background()
+	cs := sdi.New()
+	cs.AddGrouped("http", &A{})
+	cs.BuildDependencies()
+	cs.InitRequired(ctx)
+
+	err := cs.StartRunnersWith(ctx, sdi.Groups("cron"))
+	if err == nil || !strings.Contains(err.Error(), `unknown group "cron"`) {
+		t.Errorf("expected unknown group error, got %v", err)
+	}
+	if s := cs.State(); s != sdi.StateInitialized {
+		t.Errorf("expected state %s, got %s", sdi.StateInitialized, s)
+	}
+}
@@ -0,0 +1,137 @@
+package sdi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// Scope is a lightweight resolver of short-lived objects, e.g. request ID,
+// authenticated principal or transaction of an HTTP request, backed by
+// the container. Objects of the scope take precedence over objects of
+// the container. Scopes have no lifecycle: objects added into a scope are
+// neither initialized nor started or stopped.
+type Scope struct {
+	c *SimpleContainer
+
+	mux     sync.RWMutex
+	objects []interface{}
+}
+
+// NewScope returns empty scope backed by the container.
+func (c *SimpleContainer) NewScope() *Scope {
+	return &Scope{c: c}
+}
+
+// Add adds objects into the scope. Unlike SimpleContainer.Add any non-nil
+// values are accepted. It panics if an object is nil.
+func (s *Scope) Add(o ...interface{}) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for _, obj := range o {
+		if v := reflect.ValueOf(obj); !v.IsValid() || v.Kind() == reflect.Ptr && v.IsNil() {
+			panic(fmt.Sprintf("sdi: nil %T added into scope", obj))
+		}
+		s.objects = append(s.objects, obj)
+	}
+}
+
+func (s *Scope) resolve(t reflect.Type) (interface{}, error) {
+	s.mux.RLock()
+	for i := len(s.objects) - 1; i >= 0; i-- {
+		if reflect.TypeOf(s.objects[i]).AssignableTo(t) {
+			s.mux.RUnlock()
+			return s.objects[i], nil
+		}
+	}
+	s.mux.RUnlock()
+
+	return s.c.resolve(t)
+}
+
+// Inject sets nil exported interface, pointer and function fields of
+// the struct pointed to by target to objects of the scope or the container
+// assignable to them, e.g. dependencies of an HTTP handler created per
+// request. Fields left nil are reported by the returned error wrapping
+// ErrUnresolvedDependency. It panics if target is not a pointer to struct.
+func (s *Scope) Inject(target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("sdi: %T is not a pointer to struct", target))
+	}
+
+	var errs []error
+	sv := v.Elem()
+	for f := 0; f < sv.NumField(); f++ {
+		fs := sv.Field(f)
+		switch fs.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Func:
+		default:
+			continue
+		}
+		if !fs.CanSet() || !fs.IsNil() {
+			continue
+		}
+		o, err := s.resolve(fs.Type())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("sdi: %T.%s: %w", target, sv.Type().Field(f).Name, err))
+			continue
+		}
+		fs.Set(reflect.ValueOf(o))
+	}
+	return errors.Join(errs...)
+}
+
+type scopeKey struct{}
+
+// WithScope returns copy of ctx carrying the scope s.
+func WithScope(ctx context.Context, s *Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, s)
+}
+
+// ScopeFrom returns the scope carried by ctx.
+func ScopeFrom(ctx context.Context) (*Scope, bool) {
+	s, ok := ctx.Value(scopeKey{}).(*Scope)
+	return s, ok
+}
+
+// ResolveScoped returns object of the scope carried by ctx, or of its
+// container, assignable to type T.
+func ResolveScoped[T any](ctx context.Context) (T, error) {
+	var zero T
+
+	s, ok := ScopeFrom(ctx)
+	if !ok {
+		return zero, errors.New("sdi: context carries no scope")
+	}
+
+	o, err := s.resolve(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return zero, err
+	}
+	return o.(T), nil
+}
+
+// ScopeMiddleware returns HTTP middleware creating a new scope for each
+// request. Function setup, if not nil, adds request-scoped objects into
+// the scope. The scope is carried by the request context, see ScopeFrom
+// and ResolveScoped.
+//
+//	mux.Handle("/orders", c.ScopeMiddleware(func(r *http.Request, s *sdi.Scope) {
+//		s.Add(RequestID(r.Header.Get("X-Request-Id")))
+//	})(ordersHandler))
+func (c *SimpleContainer) ScopeMiddleware(setup func(r *http.Request, s *Scope)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s := c.NewScope()
+			if setup != nil {
+				setup(r, s)
+			}
+			next.ServeHTTP(w, r.WithContext(WithScope(r.Context(), s)))
+		})
+	}
+}
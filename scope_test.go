@@ -0,0 +1,73 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type RequestID string
+
+type Principal interface {
+	User() string
+}
+
+type user string
+
+func (u user) User() string { return string(u) }
+
+type ordersHandler struct {
+	Dict      Lookuper
+	Principal Principal
+}
+
+func TestScopeMiddleware(t *testing.T) {
+	cs := sdi.New()
+	cs.Add(dictionary{"greeting": "hi"})
+	cs.BuildDependencies()
+
+	var (
+		h   ordersHandler
+		id  RequestID
+		err error
+	)
+	handler := cs.ScopeMiddleware(func(r *http.Request, s *sdi.Scope) {
+		s.Add(RequestID(r.Header.Get("X-Request-Id")), user("alice"))
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, _ := sdi.ScopeFrom(r.Context())
+		err = s.Inject(&h)
+		id, _ = sdi.ResolveScoped[RequestID](r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-Id", "42")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Dict == nil || h.Dict.Lookup("greeting") != "hi" || h.Principal.User() != "alice" {
+		t.Errorf("unexpected handler %+v", h)
+	}
+	if id != "42" {
+		t.Errorf("unexpected request ID %q", id)
+	}
+}
+
+func TestScopeInjectErrors(t *testing.T) {
+	cs := sdi.New()
+	cs.BuildDependencies()
+	s := cs.NewScope()
+
+	var h ordersHandler
+	if err := s.Inject(&h); !errors.Is(err, sdi.ErrUnresolvedDependency) {
+		t.Errorf("expected ErrUnresolvedDependency, got %v", err)
+	}
+	if _, err := sdi.ResolveScoped[Principal](sdi.WithScope(context.Background(), s)); !errors.Is(err, sdi.ErrUnresolvedDependency) {
+		t.Errorf("expected ErrUnresolvedDependency, got %v", err)
+	}
+}
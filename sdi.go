@@ -1,13 +1,15 @@
-/* Package sdi provides Simple Dependency Injection functionality.
-
- */
+/*
+Package sdi provides Simple Dependency Injection functionality.
+*/
 package sdi
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
-	"unsafe"
+	"sync"
+	"time"
 )
 
 // Initializer is the interface that wraps the basic Init method.
@@ -34,6 +36,36 @@ type Runner interface {
 	Start(context.Context) error
 }
 
+// BeforeStarter is the interface that wraps the basic BeforeStart method.
+//
+// BeforeStart is invocated inside container's StartRunners() right before
+// Start of the runner implementing it. All objects are initialized at
+// this moment, therefore it's the place for work such as cache warmup.
+// An error returned by BeforeStart is treated as Start failure.
+type BeforeStarter interface {
+	BeforeStart(context.Context) error
+}
+
+// AfterStarter is the interface that wraps the basic AfterStart method.
+//
+// AfterStart is invocated inside container's StartRunners() right after
+// successful Start of the runner implementing it. An error returned by
+// AfterStart is treated as Start failure.
+type AfterStarter interface {
+	AfterStart(context.Context) error
+}
+
+// Stopper is the interface that wraps the basic Stop method.
+//
+// Stop is invocated inside container's Stop() for each containered object
+// implementing Stopper interface, once, sychronously and in reverse order.
+//
+// Stop should release resources acquired by Init and Start and return
+// when the object is stopped or the passed context is done.
+type Stopper interface {
+	Stop(context.Context) error
+}
+
 // ContaineredService is the interface what wraps two interfaces
 // Initializer and Runner.
 //
@@ -66,10 +98,34 @@ type Container interface {
 	StartRunners(context.Context) error
 }
 
+// Privater is the interface that wraps the basic Private method.
+//
+// Private returns pointer to an unexported struct of the object whose
+// exported fields should be injected the same way as the object's fields.
+// Objects added with option InjectUnexported get unexported interface
+// fields injected without it.
 type Privater interface {
 	Private() interface{}
 }
 
+// AfterInjector is the interface that wraps the basic AfterInject method.
+//
+// AfterInject is invocated inside container's BuildDependencies() for each
+// containered object implementing AfterInjector interface after all
+// objects are linked, before any Init. It's the place to validate that
+// required dependencies are injected and to do cheap derived setup.
+// Objects can be resolved from the container, e.g. by Resolve or Lazy.
+type AfterInjector interface {
+	AfterInject() error
+}
+
+// MultiPrivater is the interface that wraps the basic Privates method.
+//
+// Privates is like Private of Privater but returns several pointers.
+type MultiPrivater interface {
+	Privates() []interface{}
+}
+
 // Globalizer is the interface that wraps the basic Global method.
 //
 // Implementing interface Globalizer is a simple way of injecting arbitrary entity
@@ -78,6 +134,16 @@ type Globalizer interface {
 	Global()
 }
 
+// Namer is the interface that wraps the basic ContainerName method.
+//
+// ContainerName returns human-readable name of the object used in errors,
+// logs, events, graphs and reports instead of its type, e.g. to tell
+// apart two instances of the same type. Name given by AddNamed takes
+// precedence. Unlike name given by AddNamed it's not a qualifier.
+type Namer interface {
+	ContainerName() string
+}
+
 // Global implements Globalizer interface.
 type Global struct {
 }
@@ -86,200 +152,1155 @@ func (g *Global) Global() {}
 
 // SimpleContainer holds references to containered objects
 // and implements Container interface.
+//
+// Objects can be added concurrently. BuildDependencies freezes the list
+// of containered objects: adding or replacing objects after it panics.
+// Introspection methods are safe to call concurrently with lifecycle
+// methods.
 type SimpleContainer struct {
+	mux     sync.RWMutex
+	built   bool
+	state   State
+	running string // lifecycle method in progress
+	cache   *typeCache
 	objects []interface{}
+	regs    []registration
+	deps    []dependency
+	missing []dependency // fields left nil, provider is -1
+	opts    options
+	parent  *SimpleContainer
+	states  []objectState
+	wg      sync.WaitGroup
+	errc    chan RunnerError
+	done    chan error
+	order   []int
+	// startOrder is the order of Start calls, see StartPrioritizer.
+	startOrder []int
+	// stopOrder is the order of Stop calls, consumers before providers.
+	stopOrder []int
+
+	// progress tracks the running lifecycle method, see WithProgress.
+	progress *progress
+
+	bindings map[reflect.Type]interface{}
+	loggers  map[reflect.Type]func(string) interface{}
+
+	// gates are start gates by name, see Gate.
+	gates map[string]*Gate
+
+	// copies maps objects of the container cloned by Clone to their
+	// copies in this container.
+	copies map[interface{}]interface{}
+
+	// stopWatchdog stops the watchdog, see WithWatchdog.
+	stopWatchdog context.CancelFunc
+
+	transients []transient
+
+	// providers are constructors added by Provide, cleanups are cleanup
+	// functions returned by them, cleaned is set once they've been called.
+	providers []provider
+	cleanups  []cleanup
+	cleaned   bool
+
+	// ctxFields are fields getting values of the context passed to
+	// InitRequired.
+	ctxFields []ctxField
+
+	decorators map[reflect.Type][]reflect.Value
+	decoMux    sync.Mutex
+	decorated  map[decoration]reflect.Value
+	subs       subscribers
 }
 
-// New returns container for objects.
-func New() *SimpleContainer {
-	return &SimpleContainer{}
+// registration holds registration details of the containered object with
+// the same position in SimpleContainer.objects.
+type registration struct {
+	name   string
+	module string
+
+	// constructed is true for objects created by Provide constructors.
+	constructed bool
+
+	// group is the runner group set by AddGrouped.
+	group string
+
+	// profile is the profile set by AddForProfile.
+	profile string
+
+	// blocking is set by AddBlocking.
+	blocking bool
+
+	// notInjectable is set by NotInjectable.
+	notInjectable bool
+
+	// unexported is set by InjectUnexported.
+	unexported bool
+
+	// gate is set by Gated.
+	gate *Gate
+
+	// preconditions are set by WaitFor.
+	preconditions []Precondition
+}
+
+// dependency describes injection of object provider into field of object
+// consumer. Both are positions in SimpleContainer.objects.
+type dependency struct {
+	consumer int
+	provider int
+	field    string
+
+	// target is the field the provider is assigned to, the slice or map
+	// containing the provider, or the setter method called with it.
+	target reflect.Value
+
+	// explicit is true for dependencies declared by DependsOn.
+	explicit bool
+
+	// policy is the policy applied to the missing dependency, typ is
+	// the type of the field left nil.
+	policy UnresolvedPolicy
+	typ    reflect.Type
+}
+
+// New returns container for objects configured by options.
+func New(opts ...Option) *SimpleContainer {
+	c := &SimpleContainer{}
+	traceFromEnv(&c.opts)
+	for _, opt := range opts {
+		opt(&c.opts)
+	}
+	c.subscribeSlog()
+	return c
 }
 
 var _ Container = &SimpleContainer{}
 
 // AddService add objects implementing interface ContaineredService into container.
 func (c *SimpleContainer) AddService(o ...ContaineredService) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
 	for i := range o {
-		c.objects = append(c.objects, o[i])
+		c.add(o[i], registration{})
 	}
 }
 
 // Add adds an object into container.
+//
+// Usually the object is a pointer to struct, its fields are injected by
+// BuildDependencies. Other objects, e.g. maps, slices or named integers
+// implementing required interfaces, are injected into other objects as is
+// and take part in lifecycle, but have no fields to inject into.
+//
+// Parameters of type AddOption, e.g. NotInjectable, configure all
+// objects of the call.
+//
 // It panics if parameter:
-// - is not a pointer
-// - does not implement Initializer, Runner or Globalizer interface.
+// - is nil or nil pointer
+// - does not implement Initializer, Runner, Stopper or Globalizer interface.
+//
+// The panic value is an error wrapping ErrNotContainerable.
 func (c *SimpleContainer) Add(o ...interface{}) {
+	o, r := withAddOptions(o, registration{})
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
 
 	for i := range o {
-		_, in := o[i].(Initializer)
-		_, ru := o[i].(Runner)
-		_, gl := o[i].(Globalizer)
-		if !in && !ru && !gl {
-			panic(fmt.Sprintf("%T does not implement Runner, Initializer or Globalizer interfaces", o[i]))
+		mustBeContainerable(o[i])
+		c.add(o[i], r)
+	}
+}
+
+// AddFunc adds function values into container. Functions are injected
+// into exported fields of the same function type.
+// It panics if parameter is not a non-nil function.
+func (c *SimpleContainer) AddFunc(fn ...interface{}) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for i := range fn {
+		v := reflect.ValueOf(fn[i])
+		if v.Kind() != reflect.Func || v.IsNil() {
+			panic(fmt.Sprintf("sdi: %T is not a function", fn[i]))
+		}
+		c.add(fn[i], registration{})
+	}
+}
+
+// AddNamed adds an object into container under the name.
+// Named objects are injected into map fields, see BuildDependencies.
+// It panics if the name is empty or already used, and in the same cases
+// as Add.
+func (c *SimpleContainer) AddNamed(name string, o interface{}) {
+	if name == "" {
+		panic("sdi: empty object name")
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for i := range c.regs {
+		if c.regs[i].name == name {
+			panic(fmt.Sprintf("sdi: object name %q already used", name))
 		}
+	}
+	mustBeContainerable(o)
+	c.add(o, registration{name: name})
+}
+
+// add appends the object. The caller must hold write lock.
+func (c *SimpleContainer) add(o interface{}, r registration) {
+	if c.built {
+		panic(fmt.Sprintf("sdi: %T added after BuildDependencies", o))
+	}
+	c.objects = append(c.objects, o)
+	c.regs = append(c.regs, r)
+	c.emit(Event{Type: ObjectAdded, Object: c.nameOf(len(c.objects) - 1)})
+}
 
-		c.objects = append(c.objects, o[i])
+func mustBeContainerable(o interface{}) {
+	if err := containerable(o); err != nil {
+		panic(err)
 	}
 }
 
-// BuildDependencies links containered objects. The method should be called
-// once after adding all necessary objects into container.
+// containerable returns error wrapping ErrNotContainerable if the object
+// o can't be added into container.
+func containerable(o interface{}) error {
+	if v := reflect.ValueOf(o); !v.IsValid() || v.Kind() == reflect.Ptr && v.IsNil() {
+		return fmt.Errorf("%w: nil %T", ErrNotContainerable, o)
+	}
+	_, in := o.(Initializer)
+	_, ru := o.(Runner)
+	_, st := o.(Stopper)
+	_, gl := o.(Globalizer)
+	if !in && !ru && !st && !gl {
+		return fmt.Errorf("%w: %T does not implement Runner, Initializer, Stopper or Globalizer interfaces", ErrNotContainerable, o)
+	}
+	return nil
+}
+
+// Build links containered objects. The method should be called once
+// after adding all necessary objects into container. Objects
+// implementing Enabler and returning false are removed first.
+//
+// Nil exported interface, pointer and function fields get the containered
+// object assignable to the field type, functions are added by AddFunc.
+// If several objects are assignable an error wrapping ErrAmbiguous is
+// returned, use Bind to choose the object explicitly or
+// WithResolutionPolicy to choose it automatically. A field tagged with
+// `sdi:"qualifier=NAME"` gets the object added by AddNamed with name NAME.
+// Fields of type Container or *SimpleContainer get the container itself.
+// Fields of types registered by LoggerFactory get logger created for
+// the object.
+// If no containered object is assignable to a field, it gets a new object
+// created by the factory registered by AddTransient, if there is one.
+// Otherwise the field is left nil according to unresolved policy, see
+// WithUnresolvedPolicy.
+// Fields of embedded structs, exported fields of unnamed struct types and
+// exported struct fields tagged with `sdi:"inject"` are processed the same
+// way.
+//
+// Empty exported fields of type slice of interfaces get all containered
+// objects assignable to the slice element type. Empty exported fields of
+// type map with string keys and interface values get all objects added by
+// AddNamed assignable to the map value type, keyed by name.
+//
+// Exported fields of type string, bool, integer, float or time.Duration
+// tagged with `sdi:"env=NAME,default=VALUE"` get the value of environment
+// variable NAME, or VALUE if the variable is not set and the field is zero.
+// Tag option required (`sdi:"env=NAME,required"`) makes missing variable
+// an error.
+//
+// Build also orders objects declaring dependencies by DependsOn
+// interface and returns an error if the declared dependencies are not
+// containered or form a cycle.
+//
+// Exported methods named SetXxx, e.g. SetLogger but not Setup, with
+// a single non-empty interface parameter and no results are called with
+// the containered object assignable to the parameter type, if there is
+// one.
+//
+// Finally AfterInject of objects implementing AfterInjector is called,
+// errors returned by it are returned by Build.
+func (c *SimpleContainer) Build() error {
+	hooks, names, err := c.build()
+	if err != nil {
+		return err
+	}
+	// AfterInject is called without the lock, so it can resolve objects.
+	if err := afterInject(hooks, names); err != nil {
+		return err
+	}
+	c.setContainerState(StateBuilt)
+	c.emit(Event{Type: Wired})
+	return nil
+}
+
+// BuildDependencies implements Container interface. It's like Build but
+// panics if Build returns error.
 func (c *SimpleContainer) BuildDependencies() {
-	c.buildDependencies()
+	if err := c.Build(); err != nil {
+		panic(err)
+	}
+}
+
+// build injects dependencies and returns objects implementing
+// AfterInjector with their names in the order of Init calls.
+func (c *SimpleContainer) build() ([]AfterInjector, []string, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.built {
+		return nil, nil, fmt.Errorf("%w: BuildDependencies called again", ErrInvalidState)
+	}
+
+	c.built = true
+	defer func() { c.cache = nil }()
+	c.removeDisabled()
+	c.sortObjects()
+	if err := c.checkBindings(); err != nil {
+		return nil, nil, err
+	}
+	if err := c.construct(); err != nil {
+		return nil, nil, err
+	}
+	if err := c.buildDependencies(); err != nil {
+		return nil, nil, err
+	}
+	if err := c.checkMissing(c.missing); err != nil {
+		return nil, nil, err
+	}
+	if err := c.buildOrder(); err != nil {
+		return nil, nil, err
+	}
+	c.slogWiring()
+	if err := c.recordWiring(); err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		hooks []AfterInjector
+		names []string
+	)
+	for _, i := range c.sequence() {
+		if ai, ok := c.objects[i].(AfterInjector); ok {
+			hooks = append(hooks, ai)
+			names = append(names, c.nameOf(i))
+		}
+	}
+	return hooks, names, nil
+}
+
+// afterInject calls AfterInject of hooks, names are names of the objects.
+func afterInject(hooks []AfterInjector, names []string) error {
+	var errs []error
+	for k, ai := range hooks {
+		if err := ai.AfterInject(); err != nil {
+			errs = append(errs, fmt.Errorf("sdi: %s.AfterInject: %w", names[k], err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // InitRequired inits each containered object if it implements
 // Initializer interface.
+//
+// Before any Init, exported fields tagged with `sdi:"ctx=NAME"` get
+// the value ctx carries under key ContextKey(NAME) or the key set by
+// WithContextKey. Fields are left intact if there is no value, tag option
+// required (`sdi:"ctx=NAME,required"`) makes missing value an error.
+//
+// Inits one in the order they've been added into container, objects
+// declared by DependsOn of an object are initialized before it.
+//
+// Duration of each Init is limited by WithInitTimeout option or
+// InitTimeouter interface implemented by the object.
+//
+// If the container created with WithParallelInit option, independent
+// objects are initialized concurrently. See WithParallelInit.
+//
+// If Init of an object fails, already initialized objects implementing
+// Stopper are stopped in reverse order.
+//
+// If the container created with WithContinueOnInitError option, Init
+// errors do not break initialization, see WithContinueOnInitError.
+//
+// InitRequired must be called after BuildDependencies or Stop, otherwise
+// error wrapping ErrInvalidState is returned. With WithContinueOnInitError
+// option the container is initialized even if some Init calls fail, so
+// runners can be started, and InitRequired can be called again before
+// StartRunners to retry failed objects. Objects initialized already are
+// not initialized again.
+//
+// Called after Stop, InitRequired resets lifecycle state of all objects,
+// so the container can be brought up and down repeatedly by InitRequired,
+// StartRunners and Stop, or by Run. Objects are neither added nor
+// constructed again, therefore objects of restartable containers should
+// acquire resources in Init and release them in Stop. Objects constructed
+// by Provide can't be reused after Stop has called their cleanup
+// functions: InitRequired returns error wrapping ErrInvalidState instead.
+// Gates opened before stay open.
 func (c *SimpleContainer) InitRequired(ctx context.Context) error {
-	for i := range c.objects {
+	allowed := []State{StateBuilt, StateStopped}
+	if c.opts.continueOnInitError {
+		allowed = append(allowed, StateInitialized)
+	}
+	if err := c.begin("InitRequired", allowed...); err != nil {
+		return err
+	}
+	if c.State() == StateStopped {
+		c.mux.RLock()
+		cleaned := c.cleaned
+		c.mux.RUnlock()
+		if cleaned {
+			return c.end(StateStopped, fmt.Errorf("%w: InitRequired called after cleanup of objects constructed by Provide", ErrInvalidState))
+		}
+		c.resetStates()
+	}
+	if err := c.setContextValues(ctx, c.ctxFields); err != nil {
+		return c.end(StateInitialized, err)
+	}
+	c.track(func(i int) bool {
+		_, ok := c.objects[i].(Initializer)
+		return ok && !c.stateOf(i).inited
+	})
+	defer c.untrack()
+
+	var err error
+	if c.opts.parallelInit {
+		err = c.initParallel(ctx)
+	} else {
+		err = c.initSequential(ctx)
+	}
+
+	if err != nil && c.opts.continueOnInitError {
+		// failed objects don't prevent the container from being
+		// initialized, their runners are not started.
+		c.end(StateInitialized, nil)
+		return err
+	}
+	if err != nil {
+		err = c.rollback(err, func(s objectState) bool { return s.inited || s.started })
+	}
+	return c.end(StateInitialized, err)
+}
+
+func (c *SimpleContainer) initSequential(ctx context.Context) error {
+	var (
+		errs   []error
+		failed = make([]bool, len(c.objects))
+	)
+	for _, i := range c.sequence() {
+		if c.providerFailed(i, failed) {
+			failed[i] = true
+			continue
+		}
 		s, ok := c.objects[i].(Initializer)
-		if !ok {
+		if !ok || c.stateOf(i).inited {
 			continue
 		}
-		if err := s.Init(ctx); err != nil {
-			return err
+		if err := c.initObject(ctx, i, s); err != nil {
+			if !c.opts.continueOnInitError {
+				return err
+			}
+			failed[i] = true
+			errs = append(errs, err)
 		}
 	}
-	return nil
+	return errors.Join(errs...)
+}
+
+// providerFailed returns true if any object the object at position i
+// depends on is marked failed.
+func (c *SimpleContainer) providerFailed(i int, failed []bool) bool {
+	for _, d := range c.deps {
+		if d.consumer == i && failed[d.provider] {
+			return true
+		}
+	}
+	return false
 }
 
 // StartRunners starts runner of each containered object if it
 // implements Runner interface.
 //
-// Starts one in the order they've been added into container.
+// Starts one in the order they've been added into container, objects
+// declared by DependsOn of a runner are started before it.
+//
+// If Start of a runner fails, already started runners implementing
+// Stopper are stopped in reverse order, unless the container is created
+// with WithContinueOnStartError option.
+//
+// Start of a runner must return once the runner is started, see
+// WithStartWarning to detect runners which block. Start of runners
+// implementing BlockingRunner or added by AddBlocking is called in
+// a separate goroutine.
+//
+// If the container created with WithManagedRunners or WithSupervision
+// option, each Start is called in a separate goroutine and StartRunners
+// returns immediately. See WithManagedRunners.
+//
+// StartRunners must be called once after InitRequired, otherwise error
+// wrapping ErrInvalidState is returned.
 func (c *SimpleContainer) StartRunners(ctx context.Context) error {
-	for i := range c.objects {
+	return c.StartRunnersWith(ctx)
+}
+
+// StartRunnersWith is like StartRunners, options opts select runners to
+// start, see Groups.
+func (c *SimpleContainer) StartRunnersWith(ctx context.Context, opts ...StartOption) error {
+	if err := c.begin("StartRunners", StateInitialized); err != nil {
+		return err
+	}
+	so := newStartOptions(opts)
+	if err := c.checkGroups(so); err != nil {
+		return c.end(StateInitialized, err)
+	}
+	c.track(func(i int) bool {
+		_, ok := c.objects[i].(Runner)
+		return ok && c.selected(i, so)
+	})
+	defer c.untrack()
+
+	if c.opts.managed {
+		c.startWatchdog(ctx)
+		if err := c.startManaged(ctx, so); err != nil {
+			return c.end(StateStarted, err)
+		}
+		return c.runnersStarted(nil)
+	}
+
+	var (
+		errs   []error
+		failed = make([]bool, len(c.objects))
+	)
+	for _, i := range c.startSequence() {
 		s, ok := c.objects[i].(Runner)
-		if !ok {
+		if c.opts.continueOnStartError && c.providerFailed(i, failed) {
+			failed[i] = true
+			if ok && c.selected(i, so) {
+				c.markFailed(i, fmt.Errorf("sdi: %s is not started: its dependency failed to start", c.nameOf(i)))
+				c.advance(i)
+			}
+			continue
+		}
+		if !ok || !c.selected(i, so) {
 			continue
 		}
-		if err := s.Start(ctx); err != nil {
-			return err
+		if c.notInited(i) {
+			failed[i] = true
+			c.advance(i)
+			continue
+		}
+		if c.gated(i) {
+			c.startGated(ctx, i, s)
+			c.advance(i)
+			continue
+		}
+		if c.blocking(i) {
+			c.startBlocking(ctx, i, s)
+			c.advance(i)
+			continue
 		}
+		if err := c.startObject(ctx, i, s); err != nil {
+			err = &StartError{Object: c.nameOf(i), Err: err}
+			if !c.opts.continueOnStartError {
+				return c.end(StateStarted, c.rollback(err, func(s objectState) bool { return s.started }))
+			}
+			failed[i] = true
+			c.markFailed(i, err)
+			errs = append(errs, err)
+		}
+		c.advance(i)
 	}
-	return nil
+	c.startWatchdog(ctx)
+	// failed runners don't prevent the container from being started.
+	return c.runnersStarted(errors.Join(errs...))
 }
 
-func (c *SimpleContainer) buildDependencies() {
-	for i := range c.objects {
-		c.setReferenceTo(i, c.objects[i])
-		if pa, ok := c.objects[i].(Privater); ok {
-			obj := pa.Private()
-			c.setReferenceTo(i, obj)
+// runnersStarted moves the container to StateStarted and emits
+// RunnersStarted event. It returns err of runners failed to start, see
+// WithContinueOnStartError.
+func (c *SimpleContainer) runnersStarted(err error) error {
+	c.end(StateStarted, nil)
+	c.emit(Event{Type: RunnersStarted, Err: err})
+	return err
+}
+
+// Stop stops each containered object if it implements Stopper interface.
+//
+// Before that, Drain of started objects implementing Drainer is called in
+// the order of Stop calls, limited by drain timeout set by
+// WithDrainTimeout. Errors returned by Drain do not break shutdown and are
+// returned joined with Stop errors.
+//
+// Stops one in the reverse order they've been started, moving each object
+// before objects it depends on by injection, Provide or DependsOn:
+// a consumer is always stopped before its dependencies even if they have
+// been added in an inconvenient order. Objects stopped already are
+// skipped. An error returned by Stop does not break stopping of remaining
+// objects, all errors are returned joined by errors.Join.
+// Contexts passed to Start of runners are cancelled after their Stop.
+// Then cleanup functions returned by constructors added by Provide are
+// called in reverse order of construction.
+//
+// If ctx is done, or shutdown timeout set by WithShutdownTimeout expires,
+// objects whose Stop has not returned are abandoned: Stop does not wait
+// for them and reports them in the returned error wrapping ctx.Err().
+// Remaining objects are abandoned without calling their Stop.
+func (c *SimpleContainer) Stop(ctx context.Context) error {
+	if c.State() == StateCreated {
+		return c.cleanup(ctx)
+	}
+	c.emit(Event{Type: ShutdownBegan})
+	c.cancelWatchdog()
+	if d := c.opts.shutdownTimeout; d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+	defer c.setContainerState(StateStopped)
+	derr := c.drain(ctx)
+	err := c.stopWhere(ctx, func(s objectState) bool { return !s.stopped })
+	cerr := c.cleanup(ctx)
+	if derr != nil || cerr != nil {
+		return errors.Join(derr, err, cerr)
+	}
+	return err
+}
+
+// stopWhere stops in the order of stopSequence objects implementing
+// Stopper whose state satisfies cond and cancels contexts passed to their
+// Start.
+func (c *SimpleContainer) stopWhere(ctx context.Context, cond func(objectState) bool) error {
+	var errs []error
+	for _, i := range c.stopSequence() {
+		if !cond(c.stateOf(i)) {
+			continue
+		}
+		if err := c.stopObject(ctx, i); err != nil {
+			errs = append(errs, err)
 		}
 	}
+	return errors.Join(errs...)
 }
 
-func (c *SimpleContainer) setReferenceTo(pos int, ref interface{}) {
+// stopObject calls Stop of the object at position i if it implements
+// Stopper and cancels the context passed to its Start.
+func (c *SimpleContainer) stopObject(ctx context.Context, i int) error {
+	s, ok := c.objects[i].(Stopper)
+	if !ok {
+		c.release(i)
+		return nil
+	}
+	err := callUntilDone(ctx, c.nameOf(i)+".Stop", s.Stop)
+	c.release(i)
+	c.setState(i, func(s *objectState) {
+		s.inited = false
+		s.started = false
+		s.stopped = true
+		s.paused = false
+		s.canceled = false
+		s.liveErr = nil
+		s.stoppedAt = time.Now()
+	})
+	return err
+}
 
-	s := reflect.ValueOf(ref)
-	t := s.Elem().Type()
+// notInited reports whether initialization of the object at position i
+// or of an object it depends on has failed with WithContinueOnInitError
+// option. The runner is marked failed, it must not be started.
+func (c *SimpleContainer) notInited(i int) bool {
+	if !c.opts.continueOnInitError || !c.initFailed(i) {
+		return false
+	}
+	c.markFailed(i, fmt.Errorf("sdi: %s is not started: it or its dependency failed to initialize", c.nameOf(i)))
+	return true
+}
+
+// initFailed reports whether the object at position i, or an object it
+// depends on, implements Initializer and is not initialized.
+func (c *SimpleContainer) initFailed(i int) bool {
+	seen := make([]bool, len(c.objects))
+	var failed func(int) bool
+	failed = func(i int) bool {
+		if seen[i] {
+			return false
+		}
+		seen[i] = true
+		if _, ok := c.objects[i].(Initializer); ok && !c.stateOf(i).inited {
+			return true
+		}
+		for _, d := range c.deps {
+			if d.consumer == i && failed(d.provider) {
+				return true
+			}
+		}
+		return false
+	}
+	return failed(i)
+}
+
+// markFailed marks the runner at position i failed to start with err,
+// see WithContinueOnStartError.
+func (c *SimpleContainer) markFailed(i int, err error) {
+	c.setState(i, func(s *objectState) {
+		s.failErr = err
+	})
+	c.logf("%v", err)
+}
 
-	if t.Kind() != reflect.Struct {
-		c.set(pos, s, t)
-		return
+// rollback stops objects whose state satisfies cond after failure err
+// and returns err joined with stop errors.
+func (c *SimpleContainer) rollback(err error, cond func(objectState) bool) error {
+	if serr := c.stopWhere(context.Background(), func(s objectState) bool {
+		return !s.stopped && cond(s)
+	}); serr != nil {
+		return errors.Join(err, serr)
 	}
+	return err
+}
 
-	// pass through the struct fields.
-	for f := 0; f < t.NumField(); f++ {
+// initObject calls Init of the object at position i notifying observers,
+// tracing the call, limiting its duration by Init timeout and converting
+// panic into PanicError.
+func (c *SimpleContainer) initObject(ctx context.Context, i int, s Initializer) (err error) {
+	ctx = c.objectContext(ctx, i)
+	if c.opts.tracer != nil {
+		var end func(error)
+		ctx, end = c.opts.tracer.StartSpan(ctx, PhaseInit, c.nameOf(i))
+		defer func() { end(err) }()
+	}
 
-		fs := s.Elem().Field(f)
-		ft := fs.Type()
+	for _, o := range c.opts.observers {
+		o.BeforeInit(s)
+	}
+	name := c.nameOf(i)
+	c.emit(Event{Type: InitStarted, Object: name})
 
-		if fs.CanSet() == false {
-			continue
+	if err := c.waitPreconditions(ctx, i, s); err != nil {
+		err = &InitError{Object: name, Err: err}
+		c.setState(i, func(s *objectState) {
+			s.initErr = err
+		})
+		for _, o := range c.opts.observers {
+			o.AfterInit(s, err, 0)
 		}
+		c.emit(Event{Type: InitFinished, Object: name, Err: err})
+		c.advance(i)
+		return err
+	}
 
-		if ft.Kind() != reflect.Interface {
-			continue
+	started := time.Now()
+	timeout := c.initTimeout(s)
+	unwatch := c.watchInit(name)
+	err = c.callWithRetry(ctx, c.initRetryPolicy(s), name+".Init", func(ctx context.Context) error {
+		return callWithTimeout(ctx, timeout, name+".Init", recovered(name, PhaseInit, s.Init))
+	})
+	unwatch()
+	elapsed := time.Since(started)
+	if err != nil {
+		err = &InitError{Object: name, Err: err}
+	}
+
+	c.setState(i, func(s *objectState) {
+		s.initTime = elapsed
+		s.initErr = err
+		if err == nil {
+			s.inited = true
+			s.stopped = false
+			s.halted = false
+			s.initedAt = time.Now()
 		}
+	})
 
-		if fs.IsNil() == false {
-			// if assigned already by user before.
-			continue
+	for _, o := range c.opts.observers {
+		o.AfterInit(s, err, elapsed)
+	}
+	c.emit(Event{Type: InitFinished, Object: name, Duration: elapsed, Err: err})
+	c.advance(i)
+	return err
+}
+
+// startObject calls Start of the object at position i notifying observers,
+// tracing the call and converting panic into PanicError.
+func (c *SimpleContainer) startObject(ctx context.Context, i int, s Runner) (err error) {
+	ctx, cancel := context.WithCancel(c.objectContext(ctx, i))
+	c.setState(i, func(s *objectState) {
+		s.cancel = cancel
+	})
+	if err := c.awaitProviders(ctx, i); err != nil {
+		c.release(i)
+		return err
+	}
+	if c.opts.tracer != nil {
+		var end func(error)
+		ctx, end = c.opts.tracer.StartSpan(ctx, PhaseStart, c.nameOf(i))
+		defer func() { end(err) }()
+	}
+
+	for _, o := range c.opts.observers {
+		o.BeforeStart(s)
+	}
+	c.emit(Event{Type: RunnerStarted, Object: c.nameOf(i)})
+
+	start := recovered(c.nameOf(i), PhaseStart, func(ctx context.Context) error {
+		return c.callStart(ctx, i, s)
+	})
+	var timeout time.Duration
+	unwatch := func() {}
+	if !c.opts.managed && !c.blocking(i) {
+		timeout = c.startTimeout(s)
+		unwatch = c.watchStart(c.nameOf(i))
+	}
+
+	lctx, unlabel := c.labeled(ctx, i)
+	started := time.Now()
+	err = callWithin(lctx, timeout, c.nameOf(i)+".Start", start)
+	unlabel()
+	unwatch()
+	elapsed := time.Since(started)
+
+	c.setState(i, func(s *objectState) {
+		s.startTime = elapsed
+		if err == nil {
+			s.started = true
+			s.stopped = false
+			s.startedAt = started
+			s.failErr = nil
 		}
-		c.set(pos, fs, ft)
+	})
+	if err != nil {
+		c.release(i)
 	}
 
+	for _, o := range c.opts.observers {
+		o.AfterStart(s, err, elapsed)
+	}
+	if err != nil || c.opts.managed || c.blocking(i) {
+		c.emit(Event{Type: RunnerExited, Object: c.nameOf(i), Duration: elapsed, Err: err})
+	}
+	return err
 }
 
-func (c *SimpleContainer) set(pos int, fs reflect.Value, ft reflect.Type) {
-	for i := range c.objects {
-		if pos == i {
-			// pass reference to itself.
-			continue
+// callStart calls Start of the runner at position i surrounded by its
+// BeforeStart and AfterStart hooks.
+func (c *SimpleContainer) callStart(ctx context.Context, i int, s Runner) error {
+	if bs, ok := s.(BeforeStarter); ok {
+		if err := bs.BeforeStart(ctx); err != nil {
+			return fmt.Errorf("sdi: %s.BeforeStart: %w", c.nameOf(i), err)
+		}
+	}
+
+	if err := s.Start(ctx); err != nil {
+		return err
+	}
+
+	if as, ok := s.(AfterStarter); ok {
+		if err := as.AfterStart(ctx); err != nil {
+			return fmt.Errorf("sdi: %s.AfterStart: %w", c.nameOf(i), err)
 		}
+	}
+	return nil
+}
 
-		md := reflect.TypeOf(c.objects[i])
+// nameOf returns name of the object at position i used in reports:
+// registration name, name returned by Namer or type of the object.
+func (c *SimpleContainer) nameOf(i int) string {
+	if c.regs[i].name != "" {
+		return c.regs[i].name
+	}
+	if n, ok := c.objects[i].(Namer); ok {
+		if name := n.ContainerName(); name != "" {
+			return name
+		}
+	}
+	return fmt.Sprintf("%T", c.objects[i])
+}
 
-		if !md.AssignableTo(ft) {
-			// pass not complaint
+func (c *SimpleContainer) buildDependencies() error {
+	var errs []error
+	for i := range c.objects {
+		if c.regs[i].constructed {
 			continue
 		}
-		v := reflect.NewAt(reflect.TypeOf(c.objects[i]).Elem(), unsafe.Pointer(reflect.ValueOf(c.objects[i]).Pointer()))
-		fs.Set(v)
+		errs = append(errs, c.inject(i))
 	}
+	return errors.Join(errs...)
 }
 
-/*
-func (c *SimpleContainer) setReferenceBackup(pos int, ref interface{}) {
+// inject injects dependencies into the object at position i.
+func (c *SimpleContainer) inject(i int) error {
+	errs := []error{c.setReferenceTo(i, c.objects[i])}
+	if pa, ok := c.objects[i].(Privater); ok {
+		errs = append(errs, c.setPrivate(i, pa.Private()))
+	}
+	if mp, ok := c.objects[i].(MultiPrivater); ok {
+		for _, obj := range mp.Privates() {
+			errs = append(errs, c.setPrivate(i, obj))
+		}
+	}
+	errs = append(errs, c.callSetters(i))
+	return errors.Join(errs...)
+}
 
+// setReferenceTo injects dependencies into fields of the struct pointed to
+// by ref. Other objects, e.g. functions, maps or pointers to integers,
+// have no fields to inject into and are left as is.
+func (c *SimpleContainer) setReferenceTo(pos int, ref interface{}) error {
 	s := reflect.ValueOf(ref)
-	t := s.Elem().Type()
+	if s.Kind() != reflect.Ptr || s.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	return errors.Join(c.setFields(pos, s.Elem(), "")...)
+}
 
-	// однозначно верим в том, что c.service[i] является структурой
-	// TODO: поставить проверку
-	if t.Kind() != reflect.Struct {
-		//panic(fmt.Sprintf("%T, %#v", ref, s))
+// setPrivate injects dependencies into fields of the struct ref returned
+// by Private or Privates of the object at position pos.
+func (c *SimpleContainer) setPrivate(pos int, ref interface{}) error {
+	s := reflect.ValueOf(ref)
+	if s.Kind() != reflect.Ptr || s.IsNil() || s.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("sdi: %s: private %T is not a pointer to struct", c.nameOf(pos), ref)
 	}
+	return c.setReferenceTo(pos, ref)
+}
 
-	//fmt.Printf("service[%d] s=%s, t=%s\n", i, s.String(), t.String())
+// setFields injects dependencies into fields of the struct sv and
+// structs embedded into it. Prefix is prepended to field names
+// in reports.
+func (c *SimpleContainer) setFields(pos int, sv reflect.Value, prefix string) []error {
+	var errs []error
+	t := sv.Type()
 
-	// для каждого атрибута структуры, перебираем атрибуты.
+	// pass through the struct fields.
 	for f := 0; f < t.NumField(); f++ {
 
-		fs := s.Elem().Field(f)
+		sf := t.Field(f)
+		fs := sv.Field(f)
 		ft := fs.Type()
+		name := prefix + sf.Name
+
+		if sf.Anonymous {
+			// traverse embedded structs, exported fields of an
+			// embedded struct are settable even if its type is not.
+			if ft.Kind() == reflect.Ptr && ft.Elem().Kind() == reflect.Struct && !fs.IsNil() {
+				errs = append(errs, c.setFields(pos, fs.Elem(), name+".")...)
+				continue
+			}
+			if ft.Kind() == reflect.Struct {
+				errs = append(errs, c.setFields(pos, fs, name+".")...)
+				continue
+			}
+		}
 
 		if fs.CanSet() == false {
+			var ok bool
+			if fs, ok = c.unexportedField(pos, sf, fs); !ok {
+				continue
+			}
+		}
+
+		opts := parseTag(sf.Tag.Get(tagName))
+		if env, ok := opts["env"]; ok {
+			c.tracef("%s.%s: set from environment variable %s", c.nameOf(pos), name, env)
+			if err := setFromEnv(fs, env, opts); err != nil {
+				errs = append(errs, fmt.Errorf("sdi: %s.%s: %w", c.nameOf(pos), name, err))
+			}
+			continue
+		}
+		if key, ok := opts["ctx"]; ok {
+			c.tracef("%s.%s: set from context value %s by InitRequired", c.nameOf(pos), name, key)
+			c.addContextField(pos, fs, name, key, opts)
 			continue
 		}
 
-		if ft.Kind() != reflect.Interface {
+		if li, ok := fs.Addr().Interface().(lazyInjectable); ok {
+			c.tracef("%s.%s: lazy, resolved on first use", c.nameOf(pos), name)
+			li.setResolver(c)
 			continue
 		}
 
-		if fs.IsNil() == false {
-			// если присвоено, например конструктором сервиса.
+		if _, inject := opts["inject"]; ft.Kind() == reflect.Struct && (ft.Name() == "" || inject) {
+			// nested struct of unnamed type or explicitly marked.
+			errs = append(errs, c.setFields(pos, fs, name+".")...)
 			continue
 		}
 
-		for k := range c.objects {
-			if pos == k {
-				continue
+		if ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.Interface {
+			if fs.Len() == 0 {
+				c.setSlice(pos, fs, ft, name)
+			}
+			continue
+		}
+
+		if ft.Kind() == reflect.Map && ft.Key().Kind() == reflect.String && ft.Elem().Kind() == reflect.Interface {
+			if fs.Len() == 0 {
+				c.setMap(pos, fs, ft, name)
 			}
-			//fmt.Printf("сервис %d, %T %T %#v\n", k, c.service[k], fs.Elem(), reflect.ValueOf(c.service[k]).Pointer())
+			continue
+		}
 
-			md := reflect.TypeOf(c.objects[k])
+		if ft.Kind() != reflect.Interface && ft.Kind() != reflect.Ptr && ft.Kind() != reflect.Func {
+			continue
+		}
 
-			//if md.Kind() != reflect.Ptr {
-			if !md.AssignableTo(ft) {
-				fmt.Printf("field=[%s] service=[%s], name=%s\n", ft.String(), md.String(), ft.Name())
-				println("not assignable to")
-				continue
+		if fs.IsNil() == false {
+			// if assigned already by user before.
+			c.tracef("%s.%s: already set, skipped", c.nameOf(pos), name)
+			continue
+		}
+		if q, ok := opts["qualifier"]; ok {
+			if err := c.setQualified(pos, fs, name, q); err != nil {
+				errs = append(errs, err)
 			}
-			println("assignable to==========================================")
+			continue
+		}
+		policy, err := c.unresolvedPolicy(opts)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("sdi: %s.%s: %w", c.nameOf(pos), name, err))
+			continue
+		}
+		if err := c.set(pos, fs, ft, name, policy); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+func (c *SimpleContainer) set(pos int, fs reflect.Value, ft reflect.Type, field string, policy UnresolvedPolicy) error {
+	v, ok, err := c.value(pos, ft, field, fs, policy)
+	if ok {
+		fs.Set(v)
+	}
+	return err
+}
+
+var containerType = reflect.TypeOf((*Container)(nil)).Elem()
+
+// value returns containered object, except the object at position pos,
+// assignable to type ft and records its injection into the field. Objects
+// of the parent container are used if no own object is assignable.
+// Fields of type Container or *SimpleContainer get the container itself,
+// fields of logger types registered by LoggerFactory get a new logger.
+// If there is no such object, the field is recorded as missing unless
+// policy is IgnoreUnresolved.
+func (c *SimpleContainer) value(pos int, ft reflect.Type, field string, target reflect.Value, policy UnresolvedPolicy) (reflect.Value, bool, error) {
+	if ft == containerType || ft == reflect.TypeOf(c) {
+		c.tracef("%s.%s: injected the container", c.nameOf(pos), field)
+		return reflect.ValueOf(c), true, nil
+	}
+	if v, ok, err := c.logger(pos, ft); ok || err != nil {
+		if ok {
+			c.tracef("%s.%s: injected logger of factory", c.nameOf(pos), field)
+		}
+		if err != nil {
+			err = fmt.Errorf("%w: %s.%s", err, c.nameOf(pos), field)
+		}
+		return v, ok, err
+	}
 
-			// КАК понять что поле b.A является ServiceA и присвоить полю b.A ссылку на cs[k] (a)?
+	c.traceCandidates(pos, ft, field)
+	i, err := c.candidate(pos, ft)
+	if err != nil {
+		c.tracef("%s.%s: %v", c.nameOf(pos), field, err)
+		return reflect.Value{}, false, fmt.Errorf("%w: %s.%s", err, c.nameOf(pos), field)
+	}
+	if i >= 0 {
+		c.tracef("%s.%s: injected %s", c.nameOf(pos), field, c.nameOf(i))
+		c.deps = append(c.deps, dependency{consumer: pos, provider: i, field: field, target: target})
+		return c.provide(ft, i), true, nil
+	}
+	if o, ok := c.newTransient(ft, nil); ok {
+		c.tracef("%s.%s: injected new transient %T", c.nameOf(pos), field, o)
+		return reflect.ValueOf(o), true, nil
+	}
 
-			//				if ft.Implements(reflect.TypeOf(&cs[k]).) == false {
-			//					println("not implements")
-			//				}
-			//fs.SetPointer(unsafe.Pointer(reflect.ValueOf(cs[0]).Elem().Pointer()))
-			//println("here")
-			v := reflect.NewAt(reflect.TypeOf(c.objects[k]).Elem(), unsafe.Pointer(reflect.ValueOf(c.objects[k]).Pointer()))
-			fs.Set(v)
+	if c.parent != nil {
+		o, err := c.parent.resolve(ft)
+		if err == nil {
+			c.tracef("%s.%s: injected %T of parent container", c.nameOf(pos), field, o)
+			return reflect.ValueOf(o), true, nil
 		}
+		if !errors.Is(err, ErrUnresolvedDependency) {
+			return reflect.Value{}, false, fmt.Errorf("%w: %s.%s", err, c.nameOf(pos), field)
+		}
+	}
+	if b, ok := c.buildInfoFor(ft); ok {
+		c.tracef("%s.%s: injected build info", c.nameOf(pos), field)
+		return reflect.ValueOf(b), true, nil
+	}
+	if o, ok, err := c.fallback(ft); ok || err != nil {
+		if err != nil {
+			return reflect.Value{}, false, fmt.Errorf("%w: %s.%s", err, c.nameOf(pos), field)
+		}
+		c.tracef("%s.%s: injected %T of fallback resolver", c.nameOf(pos), field, o)
+		return reflect.ValueOf(o), true, nil
+	}
+	c.tracef("%s.%s: left nil, no candidate", c.nameOf(pos), field)
+	if policy != IgnoreUnresolved {
+		c.missing = append(c.missing, dependency{consumer: pos, provider: -1, field: field, policy: policy, typ: ft})
+	}
+	return reflect.Value{}, false, nil
+}
+
+// candidate returns position of the object, except the object at position
+// pos, assignable to type ft or -1 if there is no such object. The object
+// bound to ft by Bind is preferred. If several objects are assignable the
+// choice is made according to the resolution policy.
+func (c *SimpleContainer) candidate(pos int, ft reflect.Type) (int, error) {
+	if i, ok := c.bound(ft); ok && i != pos {
+		c.tracef("%s bound to %s", ft, c.nameOf(i))
+		return i, nil
+	}
+
+	var found []int
+	for _, i := range c.assignableTo(ft) {
+		if pos == i {
+			// pass reference to itself.
+			continue
+		}
+		found = append(found, i)
+	}
+	return c.choose(ft, found)
+}
+
+// setSlice assigns slice of all containered objects, except the object
+// at position pos, assignable to the slice element type.
+func (c *SimpleContainer) setSlice(pos int, fs reflect.Value, ft reflect.Type, field string) {
+	et := ft.Elem()
+	sv := reflect.MakeSlice(ft, 0, 0)
+	for _, i := range c.assignableTo(et) {
+		if pos == i {
+			continue
+		}
+		sv = reflect.Append(sv, c.provide(et, i))
+		c.deps = append(c.deps, dependency{consumer: pos, provider: i, field: field, target: fs})
+	}
+
+	if sv.Len() > 0 {
+		fs.Set(sv)
+	}
+}
+
+// setMap assigns map of all named containered objects, except the object
+// at position pos, assignable to the map element type, keyed by name.
+func (c *SimpleContainer) setMap(pos int, fs reflect.Value, ft reflect.Type, field string) {
+	et := ft.Elem()
+	mv := reflect.MakeMap(ft)
+	for _, i := range c.assignableTo(et) {
+		if pos == i || c.regs[i].name == "" {
+			continue
+		}
+		mv.SetMapIndex(reflect.ValueOf(c.regs[i].name).Convert(ft.Key()), c.provide(et, i))
+		c.deps = append(c.deps, dependency{consumer: pos, provider: i, field: field, target: fs})
+	}
+
+	if mv.Len() > 0 {
+		fs.Set(mv)
 	}
 }
-*/
@@ -1,40 +1,82 @@
 package sdi_test
 
 import (
+	"context"
+	"fmt"
 	"sync"
-	"testing"
+
+	"github.com/axkit/sdi"
 )
 
-func ExampleMain() {
+type URLLister interface {
+	URLs() []string
+}
 
-	type URLStorage struct {
-		mux sync.RWMutex
-		url []string
-	}
+type URLStorage struct {
+	mux sync.RWMutex
+	url []string
+}
 
+func (us *URLStorage) Add(u string) {
+	us.mux.Lock()
+	defer us.mux.Unlock()
+	us.url = append(us.url, u)
+}
 
-	func (us *URLStorage) Add(u string) {
-		us.mux.Lock()
-		defer us.mux.Unlock()
-		us.url = append(us.url, u)
-	}
+func (us *URLStorage) URLs() []string {
+	us.mux.RLock()
+	defer us.mux.RUnlock()
+	return append([]string(nil), us.url...)
+}
 
-	func (us *URLStorage)Init(ctx context.Context ) error {
-		
-		return nil
-	}
+func (us *URLStorage) Init(ctx context.Context) error {
+	us.Add("https://example.com/health")
+	return nil
+}
 
+type Messenger interface {
+	Notify(string)
+}
 
-	type HealthChecker struct {
-		
-	}
-	
-	type Notifier struct {
-		
-	}
-	
+type Notifier struct{}
 
+func (n *Notifier) Global() {}
 
-	_ = sdi.New()
+func (n *Notifier) Notify(msg string) {
+	fmt.Println("notify:", msg)
+}
+
+type HealthChecker struct {
+	Storage  URLLister
+	Notifier Messenger
+}
 
+func (hc *HealthChecker) Init(ctx context.Context) error {
+	return nil
+}
+
+func (hc *HealthChecker) Start(ctx context.Context) error {
+	for _, u := range hc.Storage.URLs() {
+		hc.Notifier.Notify("checking " + u)
+	}
+	return nil
+}
+
+func Example() {
+	c := sdi.New()
+	c.Add(&URLStorage{}, &Notifier{})
+	c.AddService(&HealthChecker{})
+	c.BuildDependencies()
+
+	ctx := context.Background()
+	if err := c.InitRequired(ctx); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := c.StartRunners(ctx); err != nil {
+		fmt.Println(err)
+		return
+	}
+	// Output:
+	// notify: checking https://example.com/health
 }
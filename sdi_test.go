@@ -2,17 +2,15 @@ package sdi_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/axkit/sdi"
 )
 
-func ExampleMain() {
-	fmt.Println("hello!")
-
-}
-
 type AI interface {
 	Age() int
 }
@@ -160,3 +158,435 @@ func TestOverall(t *testing.T) {
 
 	fmt.Println(b.Name(), "g=", g)
 }
+
+type Handler interface {
+	Handle() string
+}
+
+type handlerA struct{}
+
+func (h *handlerA) Global()        {}
+func (h *handlerA) Handle() string { return "a" }
+
+type handlerB struct{}
+
+func (h *handlerB) Global()        {}
+func (h *handlerB) Handle() string { return "b" }
+
+type router struct {
+	Handlers []Handler
+	Preset   []Handler
+}
+
+func (r *router) Global() {}
+
+func TestSliceInjection(t *testing.T) {
+	cs := sdi.New()
+	r := router{Preset: []Handler{&handlerA{}}}
+	cs.Add(&handlerA{}, &r, &handlerB{})
+	cs.BuildDependencies()
+
+	if len(r.Handlers) != 2 || r.Handlers[0].Handle() != "a" || r.Handlers[1].Handle() != "b" {
+		t.Errorf("unexpected handlers %v", r.Handlers)
+	}
+	if len(r.Preset) != 1 {
+		t.Errorf("preset slice must not be overwritten, got %v", r.Preset)
+	}
+}
+
+type dispatcher struct {
+	Providers map[string]Handler
+}
+
+func (d *dispatcher) Global() {}
+
+func TestMapInjection(t *testing.T) {
+	cs := sdi.New()
+	d := dispatcher{}
+	cs.AddNamed("paypal", &handlerA{})
+	cs.Add(&d, &handlerB{})
+	cs.AddNamed("stripe", &handlerB{})
+	cs.BuildDependencies()
+
+	if len(d.Providers) != 2 {
+		t.Fatalf("expected 2 providers, got %v", d.Providers)
+	}
+	if d.Providers["paypal"].Handle() != "a" || d.Providers["stripe"].Handle() != "b" {
+		t.Errorf("unexpected providers %v", d.Providers)
+	}
+}
+
+func TestAddNamedDuplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	cs := sdi.New()
+	cs.AddNamed("a", &handlerA{})
+	cs.AddNamed("a", &handlerB{})
+}
+
+func TestWiringAssignsSameObjects(t *testing.T) {
+	cs := sdi.New()
+	a := A{}
+	b := B{}
+	c := C{}
+	e := E{}
+	cs.Add(&a, &b, &c, &e)
+	cs.BuildDependencies()
+
+	if b.AService != &a {
+		t.Error("expected AService to point to a")
+	}
+	if b.CService != &c {
+		t.Error("expected CService to point to c")
+	}
+	if b.private.ES != &e {
+		t.Error("expected private ES to point to e")
+	}
+
+	// changes made through the injected references are visible
+	// in the original objects and vice versa.
+	b.CService.Set("F")
+	if c.gender != "F" {
+		t.Errorf("expected gender F, got %q", c.gender)
+	}
+	a.age = 33
+	if b.AService.Age() != 33 {
+		t.Errorf("expected age 33, got %d", b.AService.Age())
+	}
+}
+
+func TestConcurrentAdd(t *testing.T) {
+	cs := sdi.New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			cs.Add(&C{})
+		}()
+		go func() {
+			defer wg.Done()
+			_ = cs.Objects()
+		}()
+	}
+	wg.Wait()
+	cs.BuildDependencies()
+
+	if n := len(cs.Objects()); n != 10 {
+		t.Errorf("expected 10 objects, got %d", n)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic adding after BuildDependencies")
+		}
+	}()
+	cs.Add(&C{})
+}
+
+type BaseService struct {
+	Messenger CI
+}
+
+type baseLogger struct {
+	Ager AI
+}
+
+type embeddingService struct {
+	BaseService
+	*baseLogger
+	name string
+}
+
+func (es *embeddingService) Global() {}
+
+func TestEmbeddedInjection(t *testing.T) {
+	cs := sdi.New()
+	a := A{}
+	c := C{}
+	es := embeddingService{baseLogger: &baseLogger{}}
+	cs.Add(&a, &c, &es)
+	cs.BuildDependencies()
+
+	if es.Messenger != &c {
+		t.Error("expected embedded struct field to be injected")
+	}
+	if es.Ager != &a {
+		t.Error("expected embedded pointer struct field to be injected")
+	}
+
+	in := cs.Objects()[2].Dependencies
+	if len(in) != 2 || in[0].Field != "BaseService.Messenger" || in[1].Field != "baseLogger.Ager" {
+		t.Errorf("unexpected injections %v", in)
+	}
+}
+
+type Collaborators struct {
+	C CI
+}
+
+type nestingService struct {
+	Deps struct {
+		A AI
+		C CI
+	}
+	Marked   Collaborators `sdi:"inject"`
+	Unmarked Collaborators
+}
+
+func (ns *nestingService) Global() {}
+
+func TestNestedInjection(t *testing.T) {
+	cs := sdi.New()
+	a := A{}
+	c := C{}
+	ns := nestingService{}
+	cs.Add(&a, &c, &ns)
+	cs.BuildDependencies()
+
+	if ns.Deps.A != &a || ns.Deps.C != &c {
+		t.Error("expected unnamed struct fields to be injected")
+	}
+	if ns.Marked.C != &c {
+		t.Error("expected tagged struct fields to be injected")
+	}
+	if ns.Unmarked.C != nil {
+		t.Error("expected untagged named struct fields to be left untouched")
+	}
+}
+
+type pool struct {
+	size int
+}
+
+func (p *pool) Init(ctx context.Context) error {
+	p.size = 10
+	return nil
+}
+
+type repository struct {
+	Pool   *pool
+	Preset *pool
+}
+
+func (r *repository) Global() {}
+
+func TestPointerInjection(t *testing.T) {
+	cs := sdi.New()
+	p := pool{}
+	preset := pool{size: 1}
+	r := repository{Preset: &preset}
+	cs.Add(&r, &p)
+	cs.BuildDependencies()
+
+	if r.Pool != &p {
+		t.Error("expected pointer field to be injected")
+	}
+	if r.Preset != &preset {
+		t.Error("expected assigned pointer field to be left untouched")
+	}
+}
+
+type multiPrivate struct {
+	storage struct {
+		A AI
+	}
+	transport struct {
+		C CI
+	}
+}
+
+func (mp *multiPrivate) Global() {}
+
+func (mp *multiPrivate) Privates() []interface{} {
+	return []interface{}{&mp.storage, &mp.transport}
+}
+
+func TestMultiPrivater(t *testing.T) {
+	cs := sdi.New()
+	a := A{}
+	c := C{}
+	mp := multiPrivate{}
+	cs.Add(&a, &c, &mp)
+	cs.BuildDependencies()
+
+	if mp.storage.A != &a || mp.transport.C != &c {
+		t.Error("expected all private targets to be injected")
+	}
+}
+
+type validated struct {
+	A       AI
+	derived int
+}
+
+func (v *validated) Global() {}
+
+func (v *validated) AfterInject() error {
+	if v.A == nil {
+		return errors.New("A is required")
+	}
+	v.derived = 1
+	return nil
+}
+
+func TestAfterInject(t *testing.T) {
+	cs := sdi.New()
+	v := validated{}
+	cs.Add(&A{}, &v)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if v.derived != 1 {
+		t.Error("expected AfterInject to be called")
+	}
+
+	cs = sdi.New()
+	cs.Add(&validated{})
+	err := cs.Build()
+	if err == nil || err.Error() != "sdi: *sdi_test.validated.AfterInject: A is required" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+// resolving resolves dependencies in AfterInject.
+type resolving struct {
+	C    sdi.Lazy[CI]
+	c    *sdi.SimpleContainer
+	a, b CI
+}
+
+func (r *resolving) Global() {}
+
+func (r *resolving) AfterInject() (err error) {
+	r.a = r.C.Get()
+	r.b, err = sdi.Resolve[CI](r.c)
+	return err
+}
+
+func TestAfterInjectResolve(t *testing.T) {
+	cs := sdi.New()
+	c := C{}
+	r := resolving{c: cs}
+	cs.Add(&c, &r)
+
+	done := make(chan error, 1)
+	go func() { done <- cs.Build() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("BuildDependencies deadlocked resolving in AfterInject")
+	}
+	if r.a != &c || r.b != &c {
+		t.Errorf("expected C to be resolved in AfterInject, got %v and %v", r.a, r.b)
+	}
+}
+
+type EventHandler func(ctx context.Context, event string) error
+
+type eventBus struct {
+	Handle EventHandler
+}
+
+func (eb *eventBus) Global() {}
+
+func TestAddFunc(t *testing.T) {
+	var handled string
+	cs := sdi.New()
+	eb := eventBus{}
+	cs.Add(&eb)
+	cs.AddFunc(func(ctx context.Context, event string) error {
+		handled = event
+		return nil
+	})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if eb.Handle == nil {
+		t.Fatal("expected function to be injected")
+	}
+	eb.Handle(context.Background(), "created")
+	if handled != "created" {
+		t.Error("expected injected function to be called")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	cs.AddFunc(&eb)
+}
+
+type Lookuper interface {
+	Lookup(string) string
+}
+
+type dictionary map[string]string
+
+func (d dictionary) Init(ctx context.Context) error {
+	d["greeting"] = "hello"
+	return nil
+}
+
+func (d dictionary) Lookup(k string) string {
+	return d[k]
+}
+
+type translator struct {
+	Dict Lookuper
+}
+
+func (tr *translator) Global() {}
+
+func TestValueObjects(t *testing.T) {
+	cs := sdi.New()
+	tr := translator{}
+	cs.Add(dictionary{}, &tr)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Dict == nil || tr.Dict.Lookup("greeting") != "hello" {
+		t.Error("expected initialized map to be injected")
+	}
+
+	for _, o := range []interface{}{nil, (*translator)(nil)} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic adding %#v", o)
+				}
+			}()
+			sdi.New().Add(o)
+		}()
+	}
+}
+
+type pluginLoader struct {
+	Container sdi.Container
+	Simple    *sdi.SimpleContainer
+}
+
+func (pl *pluginLoader) Global() {}
+
+func TestContainerInjection(t *testing.T) {
+	cs := sdi.New()
+	pl := pluginLoader{}
+	cs.Add(&pl)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if pl.Container != cs || pl.Simple != cs {
+		t.Error("expected container to be injected")
+	}
+}
@@ -0,0 +1,237 @@
+// Package sdiadapter provides ready-made containered services of package
+// sdi wrapping common servers and periodic jobs:
+//
+//	c.AddService(
+//		sdiadapter.NewHTTPServer(&http.Server{Addr: ":8080", Handler: mux}),
+//		sdiadapter.NewTicker(time.Minute, refreshRates),
+//	)
+//
+// Init validates configuration, Start starts serving in a goroutine and
+// returns, Stop shuts down gracefully within the context passed to it.
+// Errors occurring after Start are reported by Health. The gRPC server
+// adapter is in package sdigrpc.
+package sdiadapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+// HTTPServer runs http.Server as containered service.
+type HTTPServer struct {
+	srv *http.Server
+
+	mux  sync.Mutex
+	ln   net.Listener
+	err  error
+	done chan struct{}
+}
+
+var (
+	_ sdi.ContaineredService = (*HTTPServer)(nil)
+	_ sdi.Stopper            = (*HTTPServer)(nil)
+	_ sdi.Healther           = (*HTTPServer)(nil)
+)
+
+// NewHTTPServer returns service serving HTTP requests by srv. srv.Addr
+// defaults to ":http", addresses with port 0 choose a free port, see Addr.
+func NewHTTPServer(srv *http.Server) *HTTPServer {
+	return &HTTPServer{srv: srv}
+}
+
+// Init implements sdi.Initializer interface. It validates the address
+// and TLS configuration of the server.
+func (s *HTTPServer) Init(ctx context.Context) error {
+	if s.srv == nil {
+		return errors.New("sdiadapter: nil http.Server")
+	}
+	if s.srv.Addr != "" {
+		if _, _, err := net.SplitHostPort(s.srv.Addr); err != nil {
+			return fmt.Errorf("sdiadapter: http.Server.Addr: %w", err)
+		}
+	}
+	if s.srv.TLSConfig != nil && len(s.srv.TLSConfig.Certificates) == 0 && s.srv.TLSConfig.GetCertificate == nil {
+		return errors.New("sdiadapter: http.Server.TLSConfig has no certificates")
+	}
+	return nil
+}
+
+// Start implements sdi.Runner interface. It listens on the address of
+// the server and serves requests in a goroutine.
+func (s *HTTPServer) Start(ctx context.Context) error {
+	addr := s.srv.Addr
+	if addr == "" {
+		addr = ":http"
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("sdiadapter: %w", err)
+	}
+
+	s.mux.Lock()
+	s.ln, s.err, s.done = ln, nil, make(chan struct{})
+	done := s.done
+	s.mux.Unlock()
+
+	go func() {
+		defer close(done)
+		var err error
+		if s.srv.TLSConfig != nil {
+			err = s.srv.ServeTLS(ln, "", "")
+		} else {
+			err = s.srv.Serve(ln)
+		}
+		if !errors.Is(err, http.ErrServerClosed) {
+			s.mux.Lock()
+			s.err = err
+			s.mux.Unlock()
+		}
+	}()
+	return nil
+}
+
+// Stop implements sdi.Stopper interface. It shuts down the server
+// gracefully waiting for active requests until ctx is done, then closes
+// remaining connections.
+func (s *HTTPServer) Stop(ctx context.Context) error {
+	s.mux.Lock()
+	done := s.done
+	s.mux.Unlock()
+	if done == nil {
+		return nil
+	}
+
+	if err := s.srv.Shutdown(ctx); err != nil {
+		s.srv.Close()
+		return fmt.Errorf("sdiadapter: %w", err)
+	}
+	<-done
+	return nil
+}
+
+// Health implements sdi.Healther interface. It returns the error Serve
+// has failed with.
+func (s *HTTPServer) Health(ctx context.Context) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.err != nil {
+		return fmt.Errorf("sdiadapter: %w", s.err)
+	}
+	return nil
+}
+
+// Addr returns the address the server listens on or nil if it's not
+// started.
+func (s *HTTPServer) Addr() net.Addr {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.ln == nil {
+		return nil
+	}
+	return s.ln.Addr()
+}
+
+// Ticker runs a job periodically as containered service.
+type Ticker struct {
+	interval time.Duration
+	job      func(context.Context) error
+
+	mux    sync.Mutex
+	err    error
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var (
+	_ sdi.ContaineredService = (*Ticker)(nil)
+	_ sdi.Stopper            = (*Ticker)(nil)
+	_ sdi.Healther           = (*Ticker)(nil)
+)
+
+// NewTicker returns service calling job every interval after Start.
+// Calls don't overlap: if the job runs longer than interval, ticks are
+// dropped.
+func NewTicker(interval time.Duration, job func(context.Context) error) *Ticker {
+	return &Ticker{interval: interval, job: job}
+}
+
+// Init implements sdi.Initializer interface. It validates the interval
+// and the job.
+func (t *Ticker) Init(ctx context.Context) error {
+	if t.interval <= 0 {
+		return fmt.Errorf("sdiadapter: non-positive ticker interval %s", t.interval)
+	}
+	if t.job == nil {
+		return errors.New("sdiadapter: nil ticker job")
+	}
+	return nil
+}
+
+// Start implements sdi.Runner interface. It calls the job every interval
+// in a goroutine until Stop.
+func (t *Ticker) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	done := make(chan struct{})
+
+	t.mux.Lock()
+	t.cancel, t.done = cancel, done
+	t.mux.Unlock()
+
+	go func() {
+		defer close(done)
+		tk := time.NewTicker(t.interval)
+		defer tk.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tk.C:
+			}
+			err := t.job(ctx)
+			t.mux.Lock()
+			t.err = err
+			t.mux.Unlock()
+		}
+	}()
+	return nil
+}
+
+// Stop implements sdi.Stopper interface. It cancels the context of
+// the running job and waits for it to return until ctx is done.
+func (t *Ticker) Stop(ctx context.Context) error {
+	t.mux.Lock()
+	cancel, done := t.cancel, t.done
+	t.mux.Unlock()
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("sdiadapter: ticker job has not returned: %w", ctx.Err())
+	}
+}
+
+// Health implements sdi.Healther interface. It returns the error of
+// the last job run.
+func (t *Ticker) Health(ctx context.Context) error {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.err != nil {
+		return fmt.Errorf("sdiadapter: ticker job: %w", t.err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package sdiadapter_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+	"github.com/axkit/sdi/sdiadapter"
+)
+
+func TestHTTPServer(t *testing.T) {
+	ctx := context.Background()
+	srv := sdiadapter.NewHTTPServer(&http.Server{
+		Addr: "127.0.0.1:0",
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, "pong")
+		}),
+	})
+	c := sdi.New()
+	c.AddService(srv)
+	if err := c.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Get("http://" + srv.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(b) != "pong" {
+		t.Errorf("expected pong, got %q", b)
+	}
+
+	if err := c.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Health(ctx); err != nil {
+		t.Errorf("expected graceful shutdown not to be reported, got %v", err)
+	}
+	if _, err := http.Get("http://" + srv.Addr().String()); err == nil {
+		t.Error("expected server to be shut down")
+	}
+}
+
+func TestHTTPServerInit(t *testing.T) {
+	for _, s := range []*http.Server{nil, {Addr: "8080"}} {
+		if err := sdiadapter.NewHTTPServer(s).Init(context.Background()); err == nil {
+			t.Errorf("expected error for %+v", s)
+		}
+	}
+}
+
+func TestTicker(t *testing.T) {
+	ctx := context.Background()
+	errJob := errors.New("job failed")
+	var calls atomic.Int32
+	tk := sdiadapter.NewTicker(time.Millisecond, func(ctx context.Context) error {
+		if calls.Add(1) > 2 {
+			return errJob
+		}
+		return nil
+	})
+	if err := tk.Init(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := tk.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for tk.Health(ctx) == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("expected job error to be reported")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := tk.Health(ctx); !errors.Is(err, errJob) {
+		t.Errorf("expected %v, got %v", errJob, err)
+	}
+	if err := tk.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	n := calls.Load()
+	time.Sleep(5 * time.Millisecond)
+	if calls.Load() != n {
+		t.Error("expected job not to be called after Stop")
+	}
+
+	if err := sdiadapter.NewTicker(0, nil).Init(ctx); err == nil {
+		t.Error("expected error for zero interval")
+	}
+}
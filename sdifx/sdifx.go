@@ -0,0 +1,149 @@
+// Package sdifx bridges sdi containers and go.uber.org/fx applications,
+// so services built on both frameworks can be migrated one part at a time.
+//
+// Objects constructed by fx are consumed by sdi through App:
+//
+//	app := sdifx.New(fx.Provide(newDB), fx.NopLogger)
+//	c.Add(app)
+//	sdifx.Export[*sql.DB](c, app)
+//
+// Objects of sdi container are consumed by fx through Provide, Lifecycle
+// runs the container as part of fx application:
+//
+//	fx.New(
+//		sdifx.Lifecycle(c),
+//		sdifx.Provide[Store](c),
+//		fx.Invoke(register),
+//	)
+package sdifx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/axkit/sdi"
+	"go.uber.org/fx"
+)
+
+// App is fx application run as containered object. The application is
+// built by BuildDependencies of the container if objects are exported
+// by Export, otherwise by Init. Start and Stop run fx lifecycle hooks.
+type App struct {
+	opts    []fx.Option
+	targets []interface{}
+
+	once    sync.Once
+	app     *fx.App
+	err     error
+	started bool
+}
+
+var (
+	_ sdi.Initializer = (*App)(nil)
+	_ sdi.Runner      = (*App)(nil)
+	_ sdi.Stopper     = (*App)(nil)
+)
+
+// New returns App built from fx options opts.
+func New(opts ...fx.Option) *App {
+	return &App{opts: opts}
+}
+
+// Export registers constructor of containered object of type T populated
+// by fx application a, see fx.Populate. Objects exported by a depend on
+// a, therefore a is initialized and started before them. a must be added
+// into c.
+func Export[T any](c *sdi.SimpleContainer, a *App) {
+	p := new(T)
+	a.targets = append(a.targets, p)
+	c.Provide(func(*App) (T, error) {
+		if err := a.build(); err != nil {
+			return *p, err
+		}
+		return *p, nil
+	})
+}
+
+// build creates fx application once.
+func (a *App) build() error {
+	a.once.Do(func() {
+		opts := append(a.opts[:len(a.opts):len(a.opts)], fx.Populate(a.targets...))
+		a.app = fx.New(opts...)
+		if err := a.app.Err(); err != nil {
+			a.err = fmt.Errorf("sdifx: %w", err)
+		}
+	})
+	return a.err
+}
+
+// Init implements sdi.Initializer interface. It builds fx application.
+func (a *App) Init(ctx context.Context) error {
+	return a.build()
+}
+
+// Start implements sdi.Runner interface. It runs OnStart hooks of fx
+// application.
+func (a *App) Start(ctx context.Context) error {
+	if err := a.build(); err != nil {
+		return err
+	}
+	if err := a.app.Start(ctx); err != nil {
+		return fmt.Errorf("sdifx: %w", err)
+	}
+	a.started = true
+	return nil
+}
+
+// Stop implements sdi.Stopper interface. It runs OnStop hooks of fx
+// application.
+func (a *App) Stop(ctx context.Context) error {
+	if !a.started {
+		return nil
+	}
+	a.started = false
+	if err := a.app.Stop(ctx); err != nil {
+		return fmt.Errorf("sdifx: %w", err)
+	}
+	return nil
+}
+
+// Provide returns fx option providing containered object of type T
+// resolved by sdi.Resolve.
+func Provide[T any](c sdi.Container) fx.Option {
+	return fx.Provide(func() (T, error) {
+		return sdi.Resolve[T](c)
+	})
+}
+
+// Lifecycle returns fx option building dependencies of container c when
+// fx application is built and appending hook initializing and starting
+// the container on start and stopping it on stop. It must precede
+// invocations using objects provided by Provide.
+//
+// The context fx passes to OnStart expires after the start timeout, so
+// runners are started with a context detached from it, which is
+// cancelled after the container is stopped.
+func Lifecycle(c *sdi.SimpleContainer) fx.Option {
+	return fx.Invoke(func(lc fx.Lifecycle) error {
+		if err := c.Build(); err != nil {
+			return err
+		}
+		cancel := func() {}
+		lc.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				if err := c.InitRequired(ctx); err != nil {
+					return err
+				}
+				var rctx context.Context
+				rctx, cancel = context.WithCancel(context.WithoutCancel(ctx))
+				return c.StartRunners(rctx)
+			},
+			OnStop: func(ctx context.Context) error {
+				defer cancel()
+				return c.Stop(ctx)
+			},
+		})
+		return nil
+	})
+}
@@ -0,0 +1,140 @@
+package sdifx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+	"github.com/axkit/sdi/sdifx"
+	"go.uber.org/fx"
+)
+
+type Store interface {
+	Get(key string) string
+}
+
+type store struct {
+	started, stopped bool
+}
+
+func (s *store) Get(key string) string { return "fx:" + key }
+
+type handler struct {
+	Store Store
+}
+
+func (h *handler) Init(ctx context.Context) error { return nil }
+
+func TestExport(t *testing.T) {
+	st := &store{}
+	app := sdifx.New(fx.NopLogger, fx.Provide(func(lc fx.Lifecycle) Store {
+		lc.Append(fx.Hook{
+			OnStart: func(context.Context) error { st.started = true; return nil },
+			OnStop:  func(context.Context) error { st.stopped = true; return nil },
+		})
+		return st
+	}))
+
+	h := handler{}
+	c := sdi.New()
+	c.Add(app, &h)
+	sdifx.Export[Store](c, app)
+
+	ctx := context.Background()
+	if err := c.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if h.Store != st {
+		t.Fatalf("expected object constructed by fx to be injected, got %v", h.Store)
+	}
+	if err := c.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !st.started {
+		t.Error("expected fx OnStart hook to be called")
+	}
+	if err := c.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !st.stopped {
+		t.Error("expected fx OnStop hook to be called")
+	}
+}
+
+type sdiStore struct {
+	inited, stopped bool
+}
+
+func (s *sdiStore) Init(ctx context.Context) error { s.inited = true; return nil }
+func (s *sdiStore) Stop(ctx context.Context) error { s.stopped = true; return nil }
+func (s *sdiStore) Get(key string) string          { return "sdi:" + key }
+
+func TestProvide(t *testing.T) {
+	st := &sdiStore{}
+	c := sdi.New()
+	c.Add(st)
+
+	var got Store
+	app := fx.New(
+		fx.NopLogger,
+		sdifx.Lifecycle(c),
+		sdifx.Provide[Store](c),
+		fx.Populate(&got),
+	)
+	if err := app.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got != st {
+		t.Fatalf("expected containered object to be provided, got %v", got)
+	}
+
+	ctx := context.Background()
+	if err := app.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !st.inited {
+		t.Error("expected container to be initialized on fx start")
+	}
+	if err := app.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !st.stopped {
+		t.Error("expected container to be stopped on fx stop")
+	}
+}
+
+type ctxRunner struct {
+	ctx context.Context
+}
+
+func (r *ctxRunner) Start(ctx context.Context) error { r.ctx = ctx; return nil }
+
+func TestLifecycleStartTimeout(t *testing.T) {
+	r := &ctxRunner{}
+	c := sdi.New()
+	c.Add(r)
+
+	app := fx.New(fx.NopLogger, sdifx.Lifecycle(c))
+	if err := app.Err(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	if err := app.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// fx cancels the start context once its start timeout expires.
+	cancel()
+	if err := r.ctx.Err(); err != nil {
+		t.Fatalf("expected runner context to outlive fx start, got %v", err)
+	}
+	if err := app.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if r.ctx.Err() == nil {
+		t.Error("expected runner context to be cancelled on fx stop")
+	}
+}
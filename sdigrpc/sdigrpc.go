@@ -0,0 +1,119 @@
+// Package sdigrpc maps state of sdi container onto the standard gRPC
+// health service grpc.health.v1.Health.
+//
+//	c := sdi.New()
+//	...
+//	grpc_health_v1.RegisterHealthServer(srv, sdigrpc.NewServer(c))
+//
+// The overall status, requested with empty service name, is SERVING when
+// the container is started and all containered objects implementing
+// sdi.Healther are healthy. A service name is the name of a containered
+// object implementing sdi.Healther, see sdi.SimpleContainer.Health.
+//
+// Service runs grpc.Server itself as containered service.
+package sdigrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/axkit/sdi"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultWatchInterval is the default interval of status checks of Watch.
+const DefaultWatchInterval = 5 * time.Second
+
+// Server implements grpc_health_v1.HealthServer reporting state of
+// the container.
+type Server struct {
+	healthpb.UnimplementedHealthServer
+
+	c *sdi.SimpleContainer
+
+	// WatchInterval is the interval of status checks of Watch streams.
+	WatchInterval time.Duration
+}
+
+var _ healthpb.HealthServer = (*Server)(nil)
+
+// NewServer returns health server of the container c.
+func NewServer(c *sdi.SimpleContainer) *Server {
+	return &Server{c: c, WatchInterval: DefaultWatchInterval}
+}
+
+// Check returns status of the service. Unknown service names are reported
+// with codes.NotFound as required by the health checking protocol.
+func (s *Server) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	st, ok := s.status(ctx, req.GetService())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.GetService())
+	}
+	return &healthpb.HealthCheckResponse{Status: st}, nil
+}
+
+// Watch sends status of the service every time it changes. Status is
+// checked every WatchInterval. Unknown service names are reported
+// as SERVICE_UNKNOWN.
+func (s *Server) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx := stream.Context()
+
+	interval := s.WatchInterval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	last := healthpb.HealthCheckResponse_ServingStatus(-1)
+	for {
+		st, ok := s.status(ctx, req.GetService())
+		if !ok {
+			st = healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+		}
+		if st != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: st}); err != nil {
+				return err
+			}
+			last = st
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-t.C:
+		}
+	}
+}
+
+// status returns status of the service. It returns false if service is
+// not empty and there is no containered Healther with such name.
+func (s *Server) status(ctx context.Context, service string) (healthpb.HealthCheckResponse_ServingStatus, bool) {
+	health := s.c.Health(ctx)
+	if service != "" {
+		err, ok := health[service]
+		if !ok {
+			return healthpb.HealthCheckResponse_SERVICE_UNKNOWN, false
+		}
+		return servingStatus(err == nil), true
+	}
+
+	if s.c.State() != sdi.StateStarted {
+		return servingStatus(false), true
+	}
+	for _, err := range health {
+		if err != nil {
+			return servingStatus(false), true
+		}
+	}
+	return servingStatus(true), true
+}
+
+func servingStatus(serving bool) healthpb.HealthCheckResponse_ServingStatus {
+	if serving {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
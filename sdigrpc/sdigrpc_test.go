@@ -0,0 +1,106 @@
+package sdigrpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+	"github.com/axkit/sdi/sdigrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+type database struct {
+	err error
+}
+
+func (db *database) Init(ctx context.Context) error   { return nil }
+func (db *database) Health(ctx context.Context) error { return db.err }
+
+type api struct{}
+
+func (a *api) Start(ctx context.Context) error { return nil }
+
+func check(t *testing.T, s *sdigrpc.Server, service string, expected healthpb.HealthCheckResponse_ServingStatus) {
+	t.Helper()
+	resp, err := s.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != expected {
+		t.Errorf("service %q: expected %s, got %s", service, expected, resp.Status)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	db := database{}
+	c := sdi.New()
+	c.AddNamed("db", &db)
+	c.Add(&api{})
+	c.BuildDependencies()
+	s := sdigrpc.NewServer(c)
+
+	ctx := context.Background()
+	c.InitRequired(ctx)
+	check(t, s, "", healthpb.HealthCheckResponse_NOT_SERVING)
+	check(t, s, "db", healthpb.HealthCheckResponse_SERVING)
+
+	c.StartRunners(ctx)
+	check(t, s, "", healthpb.HealthCheckResponse_SERVING)
+
+	db.err = errors.New("connection lost")
+	check(t, s, "", healthpb.HealthCheckResponse_NOT_SERVING)
+	check(t, s, "db", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	if _, err := s.Check(ctx, &healthpb.HealthCheckRequest{Service: "cache"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+}
+
+type watchStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan healthpb.HealthCheckResponse_ServingStatus
+}
+
+func (ws *watchStream) Context() context.Context {
+	return ws.ctx
+}
+
+func (ws *watchStream) Send(resp *healthpb.HealthCheckResponse) error {
+	ws.sent <- resp.Status
+	return nil
+}
+
+func TestWatch(t *testing.T) {
+	c := sdi.New()
+	c.Add(&api{})
+	c.BuildDependencies()
+	c.InitRequired(context.Background())
+	s := sdigrpc.NewServer(c)
+	s.WatchInterval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ws := watchStream{ctx: ctx, sent: make(chan healthpb.HealthCheckResponse_ServingStatus, 10)}
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Watch(&healthpb.HealthCheckRequest{}, &ws)
+	}()
+
+	if st := <-ws.sent; st != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected NOT_SERVING, got %s", st)
+	}
+	c.StartRunners(context.Background())
+	if st := <-ws.sent; st != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("expected SERVING, got %s", st)
+	}
+
+	cancel()
+	if err := <-done; status.Code(err) != codes.Canceled {
+		t.Errorf("expected Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package sdigrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/axkit/sdi"
+	"google.golang.org/grpc"
+)
+
+// Service runs grpc.Server as containered service:
+//
+//	srv := grpc.NewServer()
+//	pb.RegisterGreeterServer(srv, greeter)
+//	c.AddService(sdigrpc.NewService(srv, ":9090"))
+//
+// Init validates the address, Start listens on it and serves in
+// a goroutine, Stop stops the server gracefully until the context passed
+// to it is done, then closes remaining connections. The error Serve has
+// failed with is reported by Health.
+type Service struct {
+	srv  *grpc.Server
+	addr string
+
+	mux  sync.Mutex
+	ln   net.Listener
+	err  error
+	done chan struct{}
+}
+
+var (
+	_ sdi.ContaineredService = (*Service)(nil)
+	_ sdi.Stopper            = (*Service)(nil)
+	_ sdi.Healther           = (*Service)(nil)
+)
+
+// NewService returns service serving gRPC requests by srv on the address
+// addr. Addresses with port 0 choose a free port, see Addr.
+func NewService(srv *grpc.Server, addr string) *Service {
+	return &Service{srv: srv, addr: addr}
+}
+
+// Init implements sdi.Initializer interface.
+func (s *Service) Init(ctx context.Context) error {
+	if s.srv == nil {
+		return errors.New("sdigrpc: nil grpc.Server")
+	}
+	if _, _, err := net.SplitHostPort(s.addr); err != nil {
+		return fmt.Errorf("sdigrpc: %w", err)
+	}
+	return nil
+}
+
+// Start implements sdi.Runner interface.
+func (s *Service) Start(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("sdigrpc: %w", err)
+	}
+
+	s.mux.Lock()
+	s.ln, s.err, s.done = ln, nil, make(chan struct{})
+	done := s.done
+	s.mux.Unlock()
+
+	go func() {
+		defer close(done)
+		if err := s.srv.Serve(ln); err != nil {
+			s.mux.Lock()
+			s.err = err
+			s.mux.Unlock()
+		}
+	}()
+	return nil
+}
+
+// Stop implements sdi.Stopper interface.
+func (s *Service) Stop(ctx context.Context) error {
+	s.mux.Lock()
+	done := s.done
+	s.mux.Unlock()
+	if done == nil {
+		return nil
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.srv.Stop()
+		<-done
+		return fmt.Errorf("sdigrpc: graceful stop: %w", ctx.Err())
+	}
+	<-done
+	return nil
+}
+
+// Health implements sdi.Healther interface.
+func (s *Service) Health(ctx context.Context) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.err != nil {
+		return fmt.Errorf("sdigrpc: %w", s.err)
+	}
+	return nil
+}
+
+// Addr returns the address the server listens on or nil if it's not
+// started.
+func (s *Service) Addr() net.Addr {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.ln == nil {
+		return nil
+	}
+	return s.ln.Addr()
+}
@@ -0,0 +1,54 @@
+package sdigrpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/axkit/sdi"
+	"github.com/axkit/sdi/sdigrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	srv := grpc.NewServer()
+	c := sdi.New()
+	svc := sdigrpc.NewService(srv, "127.0.0.1:0")
+	c.AddService(svc)
+	healthpb.RegisterHealthServer(srv, sdigrpc.NewServer(c))
+	c.BuildDependencies()
+	if err := c.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := grpc.NewClient(svc.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("expected SERVING, got %s", resp.Status)
+	}
+
+	if err := c.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Health(ctx); err != nil {
+		t.Errorf("expected graceful stop not to be reported, got %v", err)
+	}
+}
+
+func TestServiceInit(t *testing.T) {
+	if err := sdigrpc.NewService(grpc.NewServer(), "9090").Init(context.Background()); err == nil {
+		t.Error("expected error for address without port")
+	}
+}
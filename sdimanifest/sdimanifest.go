@@ -0,0 +1,198 @@
+// Package sdimanifest assembles container of package sdi from a JSON
+// manifest listing components to enable, their names and configuration.
+//
+// Components are created by factories registered in Registry under kind
+// names, so a single binary can run in several topologies selected by
+// the manifest instead of conditional Add calls:
+//
+//	{
+//		"components": [
+//			{"kind": "postgres", "name": "primary", "config": {"dsn": "postgres://db/app"}},
+//			{"kind": "http", "config": {"addr": ":8080"}},
+//			{"kind": "grpc", "enabled": false}
+//		]
+//	}
+//
+// Named components are added by AddNamed, therefore the name is the
+// qualifier of fields tagged with `sdi:"qualifier=NAME"`.
+//
+// The package depends on the standard library only, YAML manifests can be
+// converted to JSON before loading.
+package sdimanifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/axkit/sdi"
+)
+
+// Manifest describes components of the container.
+type Manifest struct {
+	Components []Component `json:"components"`
+}
+
+// Component describes a component created by the factory of kind Kind.
+type Component struct {
+	// Kind is the name the factory is registered with.
+	Kind string `json:"kind"`
+
+	// Name is the name the component is added with, see sdi.AddNamed.
+	// Components without name are added by Add.
+	Name string `json:"name,omitempty"`
+
+	// Enabled false skips the component. Components are enabled by default.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Config is passed to the factory.
+	Config Config `json:"config,omitempty"`
+}
+
+// enabled returns false if the component is disabled explicitly.
+func (c *Component) enabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// Config is the raw JSON configuration of a component.
+type Config []byte
+
+// Decode unmarshals configuration into v rejecting unknown fields. Empty
+// configuration leaves v untouched.
+func (cfg Config) Decode(v interface{}) error {
+	if len(cfg) == 0 {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(cfg))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// MarshalJSON returns cfg itself or null if it's empty.
+func (cfg Config) MarshalJSON() ([]byte, error) {
+	if len(cfg) == 0 {
+		return []byte("null"), nil
+	}
+	return cfg, nil
+}
+
+// UnmarshalJSON stores copy of data into cfg.
+func (cfg *Config) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*cfg = nil
+		return nil
+	}
+	*cfg = append((*cfg)[0:0], data...)
+	return nil
+}
+
+// Load reads manifest from r. Unknown fields are errors.
+func Load(r io.Reader) (*Manifest, error) {
+	var m Manifest
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("sdimanifest: %w", err)
+	}
+	return &m, nil
+}
+
+// Factory creates a component from its configuration. The returned
+// object must be acceptable by sdi.Add.
+type Factory func(cfg Config) (interface{}, error)
+
+// Registry holds factories of components by kind.
+type Registry struct {
+	mux       sync.RWMutex
+	factories map[string]Factory
+}
+
+// NewRegistry returns empty registry.
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]Factory)}
+}
+
+// Register registers factory f of components of kind. It panics if
+// the kind is registered already.
+func (r *Registry) Register(kind string, f Factory) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if _, ok := r.factories[kind]; ok {
+		panic(fmt.Sprintf("sdimanifest: kind %q registered twice", kind))
+	}
+	r.factories[kind] = f
+}
+
+// Kinds returns sorted names of registered kinds.
+func (r *Registry) Kinds() []string {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	res := make([]string, 0, len(r.factories))
+	for k := range r.factories {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Assemble creates enabled components of the manifest m in the order they
+// are listed and adds them into container c. Unknown kinds, factory errors
+// and objects rejected by the container are returned as errors, components
+// created before the error remain added.
+func (r *Registry) Assemble(c *sdi.SimpleContainer, m *Manifest) error {
+	for i := range m.Components {
+		comp := &m.Components[i]
+		if !comp.enabled() {
+			continue
+		}
+
+		r.mux.RLock()
+		f, ok := r.factories[comp.Kind]
+		r.mux.RUnlock()
+		if !ok {
+			return fmt.Errorf("sdimanifest: unknown kind %q", comp.Kind)
+		}
+
+		o, err := f(comp.Config)
+		if err != nil {
+			return fmt.Errorf("sdimanifest: %s: %w", comp.label(), err)
+		}
+		if err := add(c, comp.Name, o); err != nil {
+			return fmt.Errorf("sdimanifest: %s: %w", comp.label(), err)
+		}
+	}
+	return nil
+}
+
+// label returns name of the component used in errors.
+func (c *Component) label() string {
+	if c.Name != "" {
+		return fmt.Sprintf("%s %q", c.Kind, c.Name)
+	}
+	return c.Kind
+}
+
+// add adds object o into container converting panic into error.
+func add(c *sdi.SimpleContainer, name string, o interface{}) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			if e, ok := v.(error); ok {
+				err = e
+				return
+			}
+			err = fmt.Errorf("%v", v)
+		}
+	}()
+
+	if name != "" {
+		c.AddNamed(name, o)
+		return nil
+	}
+	c.Add(o)
+	return nil
+}
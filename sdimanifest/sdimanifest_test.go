@@ -0,0 +1,98 @@
+package sdimanifest_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/axkit/sdi"
+	"github.com/axkit/sdi/sdimanifest"
+)
+
+type Store interface {
+	DSN() string
+}
+
+type postgres struct {
+	Config struct {
+		DSN string `json:"dsn"`
+	}
+}
+
+func (p *postgres) Init(ctx context.Context) error { return nil }
+func (p *postgres) DSN() string                    { return p.Config.DSN }
+
+type server struct {
+	Addr    string `json:"addr"`
+	Primary Store  `sdi:"qualifier=primary"`
+}
+
+func (s *server) Global() {}
+
+func registry() *sdimanifest.Registry {
+	r := sdimanifest.NewRegistry()
+	r.Register("postgres", func(cfg sdimanifest.Config) (interface{}, error) {
+		p := &postgres{}
+		return p, cfg.Decode(&p.Config)
+	})
+	r.Register("http", func(cfg sdimanifest.Config) (interface{}, error) {
+		s := &server{Addr: ":80"}
+		return s, cfg.Decode(s)
+	})
+	r.Register("broken", func(cfg sdimanifest.Config) (interface{}, error) {
+		return nil, errors.New("not configured")
+	})
+	return r
+}
+
+func TestAssemble(t *testing.T) {
+	m, err := sdimanifest.Load(strings.NewReader(`{"components": [
+		{"kind": "postgres", "name": "replica", "config": {"dsn": "postgres://replica"}},
+		{"kind": "postgres", "name": "primary", "config": {"dsn": "postgres://primary"}},
+		{"kind": "http", "config": {"addr": ":8080"}},
+		{"kind": "broken", "enabled": false}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cs := sdi.New()
+	if err := registry().Assemble(cs, m); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	objs := cs.Objects()
+	if len(objs) != 3 {
+		t.Fatalf("expected 3 objects, got %d", len(objs))
+	}
+	s := objs[2].Object.(*server)
+	if s.Addr != ":8080" || s.Primary == nil || s.Primary.DSN() != "postgres://primary" {
+		t.Errorf("unexpected server %+v", s)
+	}
+}
+
+func TestAssembleErrors(t *testing.T) {
+	for manifest, expected := range map[string]string{
+		`{"components": [{"kind": "redis"}]}`:                                            `sdimanifest: unknown kind "redis"`,
+		`{"components": [{"kind": "broken"}]}`:                                           "sdimanifest: broken: not configured",
+		`{"components": [{"kind": "http", "config": {"port": 80}}]}`:                     `sdimanifest: http: json: unknown field "port"`,
+		`{"components": [{"kind": "http", "name": "a"}, {"kind": "http", "name": "a"}]}`: `sdimanifest: http "a": sdi: object name "a" already used`,
+	} {
+		m, err := sdimanifest.Load(strings.NewReader(manifest))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = registry().Assemble(sdi.New(), m)
+		if err == nil || err.Error() != expected {
+			t.Errorf("expected %q, got %v", expected, err)
+		}
+	}
+
+	if _, err := sdimanifest.Load(strings.NewReader(`{"objects": []}`)); err == nil {
+		t.Error("expected error for unknown manifest field")
+	}
+}
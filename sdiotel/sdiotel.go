@@ -0,0 +1,49 @@
+// Package sdiotel provides OpenTelemetry tracing of sdi container lifecycle.
+//
+//	c := sdi.New(sdi.WithTracer(sdiotel.NewTracer(otel.Tracer("app"))))
+//
+// Every Init and Start call of containered objects gets a span named
+// "sdi.Init <object>" or "sdi.Start <object>". An error returned by
+// the call is recorded and sets the span status to Error.
+package sdiotel
+
+import (
+	"context"
+
+	"github.com/axkit/sdi"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute keys set on spans.
+const (
+	PhaseKey  = attribute.Key("sdi.phase")
+	ObjectKey = attribute.Key("sdi.object")
+)
+
+// Tracer implements sdi.Tracer using OpenTelemetry tracer.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+var _ sdi.Tracer = (*Tracer)(nil)
+
+// NewTracer returns sdi.Tracer creating spans with t.
+func NewTracer(t trace.Tracer) *Tracer {
+	return &Tracer{tracer: t}
+}
+
+// StartSpan implements sdi.Tracer interface.
+func (t *Tracer) StartSpan(ctx context.Context, phase, object string) (context.Context, func(error)) {
+	ctx, span := t.tracer.Start(ctx, "sdi."+phase+" "+object,
+		trace.WithAttributes(PhaseKey.String(phase), ObjectKey.String(object)))
+
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,60 @@
+package sdiotel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/axkit/sdi"
+	"github.com/axkit/sdi/sdiotel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type service struct {
+	initErr error
+}
+
+func (s *service) Init(ctx context.Context) error  { return s.initErr }
+func (s *service) Start(ctx context.Context) error { return nil }
+
+func TestTracer(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	c := sdi.New(sdi.WithTracer(sdiotel.NewTracer(tp.Tracer("test"))))
+	c.AddService(&service{})
+	c.BuildDependencies()
+	if err := c.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.StartRunners(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name() != "sdi.Init *sdiotel_test.service" || spans[1].Name() != "sdi.Start *sdiotel_test.service" {
+		t.Errorf("unexpected span names %q, %q", spans[0].Name(), spans[1].Name())
+	}
+}
+
+func TestTracerError(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	c := sdi.New(sdi.WithTracer(sdiotel.NewTracer(tp.Tracer("test"))))
+	c.AddService(&service{initErr: errors.New("no database")})
+	c.BuildDependencies()
+	if err := c.InitRequired(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 1 || spans[0].Status().Code != codes.Error {
+		t.Errorf("expected single span with error status")
+	}
+}
@@ -0,0 +1,90 @@
+// Package sdiplugin lets Go plugins contribute objects into container of
+// package sdi.
+//
+// A plugin is a main package built with -buildmode=plugin exporting
+// function Register of type func(sdi.Container) or
+// func(sdi.Container) error adding its objects into the container:
+//
+//	func Register(c sdi.Container) {
+//		c.Add(&reportExporter{})
+//	}
+//
+// Plugins must be opened before BuildDependencies. Plugins are supported
+// by the Go toolchain on a limited set of platforms and must be built with
+// the same versions of Go and of shared packages as the application,
+// see package plugin.
+package sdiplugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+	"sort"
+
+	"github.com/axkit/sdi"
+)
+
+// RegisterSymbol is the name of the function looked up in plugins.
+const RegisterSymbol = "Register"
+
+// Open opens plugin at path and calls its Register function with
+// the container c.
+func Open(c sdi.Container, path string) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("sdiplugin: %w", err)
+	}
+	sym, err := p.Lookup(RegisterSymbol)
+	if err != nil {
+		return fmt.Errorf("sdiplugin: %s: %w", path, err)
+	}
+	if err := Register(c, sym); err != nil {
+		return fmt.Errorf("sdiplugin: %s: %w", path, err)
+	}
+	return nil
+}
+
+// OpenDir opens plugins matching *.so in directory dir in lexical order.
+// It stops at the first failed plugin.
+func OpenDir(c sdi.Container, dir string) error {
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("sdiplugin: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.so"))
+	if err != nil {
+		return fmt.Errorf("sdiplugin: %w", err)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if err := Open(c, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Register calls register function fn, usually a symbol looked up in
+// a plugin, with the container c. Panics of fn, e.g. caused by objects
+// rejected by the container, are returned as errors.
+func Register(c sdi.Container, fn interface{}) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			if e, ok := v.(error); ok {
+				err = e
+				return
+			}
+			err = fmt.Errorf("%v", v)
+		}
+	}()
+
+	switch f := fn.(type) {
+	case func(sdi.Container):
+		f(c)
+		return nil
+	case func(sdi.Container) error:
+		return f(c)
+	}
+	return fmt.Errorf("%s has type %T, expected func(sdi.Container) or func(sdi.Container) error", RegisterSymbol, fn)
+}
@@ -0,0 +1,57 @@
+package sdiplugin_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/axkit/sdi"
+	"github.com/axkit/sdi/sdiplugin"
+)
+
+type exporter struct{}
+
+func (e *exporter) Global() {}
+
+func TestRegister(t *testing.T) {
+	c := sdi.New()
+	err := sdiplugin.Register(c, func(c sdi.Container) {
+		c.Add(&exporter{})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(c.Objects()); n != 1 {
+		t.Errorf("expected 1 object, got %d", n)
+	}
+
+	errFailed := errors.New("license expired")
+	if err := sdiplugin.Register(c, func(c sdi.Container) error { return errFailed }); !errors.Is(err, errFailed) {
+		t.Errorf("expected register error, got %v", err)
+	}
+	if err := sdiplugin.Register(c, func(c sdi.Container) { c.Add(struct{}{}) }); !errors.Is(err, sdi.ErrNotContainerable) {
+		t.Errorf("expected ErrNotContainerable, got %v", err)
+	}
+	if err := sdiplugin.Register(c, func() {}); err == nil {
+		t.Error("expected error for wrong signature")
+	}
+}
+
+func TestOpenDir(t *testing.T) {
+	c := sdi.New()
+	dir := t.TempDir()
+	if err := sdiplugin.OpenDir(c, dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := sdiplugin.OpenDir(c, filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected error for missing directory")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.so"), []byte("not a plugin"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := sdiplugin.OpenDir(c, dir); err == nil {
+		t.Error("expected error for broken plugin")
+	}
+}
@@ -0,0 +1,157 @@
+// Package sdiprom provides Prometheus metrics of sdi container lifecycle.
+//
+//	m := sdiprom.NewCollector()
+//	prometheus.MustRegister(m)
+//	c := sdi.New(sdi.WithObserver(m))
+//
+// Exposed metrics:
+//
+//	sdi_init_duration_seconds{object}  histogram of Init durations
+//	sdi_start_duration_seconds{object} histogram of Start durations
+//	sdi_restarts_total{object}         number of repeated Start calls
+//	sdi_object_state{object,state}     1 for the current lifecycle state
+//
+// Label object is the name returned by ContainerName of objects
+// implementing sdi.Namer, the type of the object otherwise.
+package sdiprom
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/axkit/sdi"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Lifecycle states reported by sdi_object_state metric.
+const (
+	StateInitializing = "initializing"
+	StateInitialized  = "initialized"
+	StateStarting     = "starting"
+	StateStarted      = "started"
+	StateFailed       = "failed"
+)
+
+var states = []string{StateInitializing, StateInitialized, StateStarting, StateStarted, StateFailed}
+
+// Collector implements sdi.Observer recording lifecycle metrics and
+// prometheus.Collector exposing them.
+type Collector struct {
+	initDuration  *prometheus.HistogramVec
+	startDuration *prometheus.HistogramVec
+	restarts      *prometheus.CounterVec
+	state         *prometheus.GaugeVec
+
+	mux     sync.Mutex
+	started map[string]bool
+}
+
+var (
+	_ sdi.Observer         = (*Collector)(nil)
+	_ prometheus.Collector = (*Collector)(nil)
+)
+
+// NewCollector returns Collector with default histogram buckets.
+func NewCollector() *Collector {
+	return &Collector{
+		initDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "sdi",
+			Name:      "init_duration_seconds",
+			Help:      "Duration of Init calls of containered objects.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"object"}),
+		startDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "sdi",
+			Name:      "start_duration_seconds",
+			Help:      "Duration of Start calls of containered objects.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"object"}),
+		restarts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "sdi",
+			Name:      "restarts_total",
+			Help:      "Number of Start calls of containered objects after the first one.",
+		}, []string{"object"}),
+		state: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "sdi",
+			Name:      "object_state",
+			Help:      "Lifecycle state of containered objects, 1 for the current state.",
+		}, []string{"object", "state"}),
+		started: make(map[string]bool),
+	}
+}
+
+// Describe implements prometheus.Collector interface.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.initDuration.Describe(ch)
+	c.startDuration.Describe(ch)
+	c.restarts.Describe(ch)
+	c.state.Describe(ch)
+}
+
+// Collect implements prometheus.Collector interface.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.initDuration.Collect(ch)
+	c.startDuration.Collect(ch)
+	c.restarts.Collect(ch)
+	c.state.Collect(ch)
+}
+
+// BeforeInit implements sdi.Observer interface.
+func (c *Collector) BeforeInit(obj interface{}) {
+	c.setState(objectName(obj), StateInitializing)
+}
+
+// AfterInit implements sdi.Observer interface.
+func (c *Collector) AfterInit(obj interface{}, err error, d time.Duration) {
+	name := objectName(obj)
+	c.initDuration.WithLabelValues(name).Observe(d.Seconds())
+	if err != nil {
+		c.setState(name, StateFailed)
+		return
+	}
+	c.setState(name, StateInitialized)
+}
+
+// BeforeStart implements sdi.Observer interface.
+func (c *Collector) BeforeStart(obj interface{}) {
+	name := objectName(obj)
+
+	c.mux.Lock()
+	if c.started[name] {
+		c.restarts.WithLabelValues(name).Inc()
+	}
+	c.started[name] = true
+	c.mux.Unlock()
+
+	c.setState(name, StateStarting)
+}
+
+// AfterStart implements sdi.Observer interface.
+func (c *Collector) AfterStart(obj interface{}, err error, d time.Duration) {
+	name := objectName(obj)
+	c.startDuration.WithLabelValues(name).Observe(d.Seconds())
+	if err != nil {
+		c.setState(name, StateFailed)
+		return
+	}
+	c.setState(name, StateStarted)
+}
+
+func (c *Collector) setState(name, state string) {
+	for _, s := range states {
+		v := 0.0
+		if s == state {
+			v = 1
+		}
+		c.state.WithLabelValues(name, s).Set(v)
+	}
+}
+
+// objectName returns name of the object returned by sdi.Namer or its type.
+func objectName(obj interface{}) string {
+	if n, ok := obj.(sdi.Namer); ok && n.ContainerName() != "" {
+		return n.ContainerName()
+	}
+	return fmt.Sprintf("%T", obj)
+}
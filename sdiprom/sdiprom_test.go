@@ -0,0 +1,58 @@
+package sdiprom_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/axkit/sdi"
+	"github.com/axkit/sdi/sdiprom"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type service struct{}
+
+func (s *service) Init(ctx context.Context) error  { return nil }
+func (s *service) Start(ctx context.Context) error { return nil }
+
+func TestCollector(t *testing.T) {
+	m := sdiprom.NewCollector()
+	c := sdi.New(sdi.WithObserver(m))
+	c.AddService(&service{})
+	c.BuildDependencies()
+	for i := 0; i < 2; i++ {
+		c.Stop(context.Background())
+		if err := c.InitRequired(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.StartRunners(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := `
+# HELP sdi_restarts_total Number of Start calls of containered objects after the first one.
+# TYPE sdi_restarts_total counter
+sdi_restarts_total{object="*sdiprom_test.service"} 1
+`
+	if err := testutil.CollectAndCompare(m, strings.NewReader(expected), "sdi_restarts_total"); err != nil {
+		t.Error(err)
+	}
+
+	expected = `
+# HELP sdi_object_state Lifecycle state of containered objects, 1 for the current state.
+# TYPE sdi_object_state gauge
+sdi_object_state{object="*sdiprom_test.service",state="failed"} 0
+sdi_object_state{object="*sdiprom_test.service",state="initialized"} 0
+sdi_object_state{object="*sdiprom_test.service",state="initializing"} 0
+sdi_object_state{object="*sdiprom_test.service",state="started"} 1
+sdi_object_state{object="*sdiprom_test.service",state="starting"} 0
+`
+	if err := testutil.CollectAndCompare(m, strings.NewReader(expected), "sdi_object_state"); err != nil {
+		t.Error(err)
+	}
+
+	if n := testutil.CollectAndCount(m, "sdi_init_duration_seconds"); n != 1 {
+		t.Errorf("expected 1 init duration series, got %d", n)
+	}
+}
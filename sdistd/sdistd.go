@@ -0,0 +1,124 @@
+// Package sdistd provides standard objects of package sdi abstracting
+// time, randomness and environment, so services depending on them are
+// testable through the container instead of package level variables:
+//
+//	type scheduler struct {
+//		Clock sdistd.Clock
+//	}
+//
+//	c.AddModule(sdistd.Module())
+//	c.Add(&scheduler{})
+//
+// Tests add deterministic fakes from package sditest instead of Module.
+package sdistd
+
+import (
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+// ModuleName is the name of the module returned by Module.
+const ModuleName = "sdistd"
+
+// Module returns module holding SystemClock, SystemRand and SystemEnv.
+func Module() *sdi.Module {
+	return sdi.NewModule(ModuleName, &SystemClock{}, NewSystemRand(), &SystemEnv{})
+}
+
+// Clock is the source of current time and timers.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	After(d time.Duration) <-chan time.Time
+}
+
+// Rand is the source of pseudo-random numbers.
+type Rand interface {
+	Int63() int64
+	Intn(n int) int
+	Float64() float64
+}
+
+// Env reads environment variables.
+type Env interface {
+	Lookup(key string) (string, bool)
+	Get(key string) string
+}
+
+// SystemClock implements Clock by package time.
+type SystemClock struct{}
+
+// Global implements sdi.Globalizer interface.
+func (*SystemClock) Global() {}
+
+// Now returns time.Now().
+func (*SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Since returns time.Since(t).
+func (*SystemClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+// After returns time.After(d).
+func (*SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// SystemRand implements Rand by package math/rand. It's safe for
+// concurrent use.
+type SystemRand struct {
+	mux sync.Mutex
+	r   *rand.Rand
+}
+
+// Global implements sdi.Globalizer interface.
+func (*SystemRand) Global() {}
+
+// NewSystemRand returns SystemRand seeded by current time.
+func NewSystemRand() *SystemRand {
+	return &SystemRand{r: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// Int63 returns a non-negative pseudo-random 63-bit integer.
+func (r *SystemRand) Int63() int64 {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.r.Int63()
+}
+
+// Intn returns a non-negative pseudo-random number in [0,n). It panics
+// if n <= 0.
+func (r *SystemRand) Intn(n int) int {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.r.Intn(n)
+}
+
+// Float64 returns a pseudo-random number in [0.0,1.0).
+func (r *SystemRand) Float64() float64 {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.r.Float64()
+}
+
+// SystemEnv implements Env by package os.
+type SystemEnv struct{}
+
+// Global implements sdi.Globalizer interface.
+func (*SystemEnv) Global() {}
+
+// Lookup returns os.LookupEnv(key).
+func (*SystemEnv) Lookup(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+// Get returns os.Getenv(key).
+func (*SystemEnv) Get(key string) string {
+	return os.Getenv(key)
+}
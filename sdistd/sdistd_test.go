@@ -0,0 +1,46 @@
+package sdistd_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+	"github.com/axkit/sdi/sdistd"
+)
+
+type scheduler struct {
+	Clock sdistd.Clock
+	Rand  sdistd.Rand
+	Env   sdistd.Env
+}
+
+func (*scheduler) Global() {}
+
+func TestModule(t *testing.T) {
+	t.Setenv("SDISTD_TEST", "on")
+
+	s := scheduler{}
+	c := sdi.New()
+	c.AddModule(sdistd.Module())
+	c.Add(&s)
+	if err := c.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Clock == nil || s.Rand == nil || s.Env == nil {
+		t.Fatalf("expected standard objects to be injected, got %+v", s)
+	}
+	if d := s.Clock.Since(s.Clock.Now()); d < 0 || d > time.Second {
+		t.Errorf("unexpected elapsed time %s", d)
+	}
+	if n := s.Rand.Intn(10); n < 0 || n >= 10 {
+		t.Errorf("expected number in [0,10), got %d", n)
+	}
+	if v, ok := s.Env.Lookup("SDISTD_TEST"); !ok || v != "on" {
+		t.Errorf("expected variable to be read, got %q", v)
+	}
+}
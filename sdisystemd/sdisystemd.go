@@ -0,0 +1,122 @@
+// Package sdisystemd integrates sdi containers with systemd service
+// notifications (sd_notify) of units with Type=notify:
+//
+//	c := sdi.New(sdi.WithWatchdog(sdisystemd.WatchdogPolicy()))
+//	sdisystemd.Notify(c)
+//	c.Add(...)
+//	err := c.RunUntilSignal()
+//
+// Messages are sent on container events:
+//
+//	READY=1      runners are started and objects implementing sdi.Readier are ready
+//	RELOADING=1  Reconfigure is called, READY=1 is sent when it finishes
+//	STOPPING=1   Stop is called
+//	WATCHDOG=1   a round of liveness checks passed, see sdi.WithWatchdog
+//
+// Nothing is sent if the process is not run by systemd, i.e. NOTIFY_SOCKET
+// environment variable is not set.
+package sdisystemd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+// Notifier sends notifications to the service manager listening on
+// a datagram unix socket. It's safe for concurrent use.
+type Notifier struct {
+	socket string
+}
+
+// NewNotifier returns Notifier sending to socket, e.g. the value of
+// NOTIFY_SOCKET environment variable. Names of abstract sockets start with
+// '@'. Notifier of empty socket sends nothing.
+func NewNotifier(socket string) *Notifier {
+	return &Notifier{socket: socket}
+}
+
+// Notify sends state, e.g. "READY=1", to the service manager.
+func (n *Notifier) Notify(state string) error {
+	if n.socket == "" {
+		return nil
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: n.socket, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("sdisystemd: %w", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return fmt.Errorf("sdisystemd: %w", err)
+	}
+	return nil
+}
+
+// Notify attaches Notifier of the socket set by NOTIFY_SOCKET environment
+// variable to container c, see Attach.
+func Notify(c *sdi.SimpleContainer) (detach func()) {
+	return Attach(c, NewNotifier(os.Getenv("NOTIFY_SOCKET")))
+}
+
+// Attach subscribes n to events of container c, see package description,
+// and returns function cancelling the subscription. Errors of sending
+// notifications are ignored, systemd treats missing notifications
+// according to the unit configuration.
+func Attach(c *sdi.SimpleContainer, n *Notifier) (detach func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	unsubscribe := c.Subscribe(func(e sdi.Event) {
+		switch e.Type {
+		case sdi.RunnersStarted:
+			// WaitReady must not be called by the subscriber itself.
+			go func() {
+				if err := c.WaitReady(ctx); err == nil {
+					_ = n.Notify(readyState(e.Err))
+				}
+			}()
+		case sdi.Reconfiguring:
+			_ = n.Notify("RELOADING=1")
+		case sdi.Reconfigured:
+			_ = n.Notify(readyState(e.Err))
+		case sdi.ShutdownBegan:
+			cancel()
+			_ = n.Notify("STOPPING=1")
+		case sdi.LivenessPassed:
+			_ = n.Notify("WATCHDOG=1")
+		}
+	})
+	return func() {
+		unsubscribe()
+		cancel()
+	}
+}
+
+// readyState returns READY=1 with status describing err, if any.
+func readyState(err error) string {
+	if err == nil {
+		return "READY=1"
+	}
+	status := strings.ReplaceAll(err.Error(), "\n", "; ")
+	return "READY=1\nSTATUS=" + status
+}
+
+// WatchdogPolicy returns watchdog policy checking liveness twice per
+// watchdog timeout of the service set by WATCHDOG_USEC environment
+// variable, so WATCHDOG=1 is sent in time while runners are alive.
+// The returned policy is disabled, i.e. has zero Interval, if systemd
+// watchdog is not enabled for the process.
+func WatchdogPolicy() sdi.WatchdogPolicy {
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return sdi.WatchdogPolicy{}
+	}
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return sdi.WatchdogPolicy{}
+	}
+	return sdi.WatchdogPolicy{Interval: time.Duration(usec) * time.Microsecond / 2}
+}
@@ -0,0 +1,127 @@
+package sdisystemd_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+	"github.com/axkit/sdi/sdisystemd"
+)
+
+type server struct {
+	ready chan struct{}
+}
+
+func (s *server) Start(ctx context.Context) error {
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		close(s.ready)
+	}()
+	return nil
+}
+
+func (s *server) Ready() <-chan struct{} { return s.ready }
+
+func (s *server) Liveness(ctx context.Context) error { return nil }
+
+func (s *server) Reconfigure(ctx context.Context) error { return nil }
+
+// listen returns unix datagram socket of the fake service manager and
+// channel receiving messages sent to it.
+func listen(t *testing.T) (string, <-chan string) {
+	dir, err := os.MkdirTemp("", "sd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	socket := filepath.Join(dir, "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	msgs := make(chan string, 100)
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				return
+			}
+			msgs <- string(buf[:n])
+		}
+	}()
+	return socket, msgs
+}
+
+func expect(t *testing.T, msgs <-chan string, want string) {
+	t.Helper()
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case m := <-msgs:
+			if m == want {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("expected %q to be sent", want)
+		}
+	}
+}
+
+func TestAttach(t *testing.T) {
+	socket, msgs := listen(t)
+	c := sdi.New(sdi.WithWatchdog(sdi.WatchdogPolicy{Interval: time.Millisecond}))
+	defer sdisystemd.Attach(c, sdisystemd.NewNotifier(socket))()
+	c.Add(&server{ready: make(chan struct{})})
+
+	ctx := context.Background()
+	if err := c.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, msgs, "READY=1")
+	expect(t, msgs, "WATCHDOG=1")
+
+	if err := c.Reconfigure(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, msgs, "RELOADING=1")
+	expect(t, msgs, "READY=1")
+
+	if err := c.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, msgs, "STOPPING=1")
+}
+
+func TestNotifyWithoutSocket(t *testing.T) {
+	if err := sdisystemd.NewNotifier("").Notify("READY=1"); err != nil {
+		t.Errorf("expected nothing to be sent, got %v", err)
+	}
+}
+
+func TestWatchdogPolicy(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "2000000")
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()))
+	if p := sdisystemd.WatchdogPolicy(); p.Interval != time.Second {
+		t.Errorf("expected interval of 1s, got %s", p.Interval)
+	}
+
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1))
+	if p := sdisystemd.WatchdogPolicy(); p.Interval != 0 {
+		t.Errorf("expected watchdog of another process to be ignored, got %s", p.Interval)
+	}
+}
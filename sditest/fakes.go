@@ -0,0 +1,162 @@
+package sditest
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/axkit/sdi/sdistd"
+)
+
+var (
+	_ sdistd.Clock = (*FakeClock)(nil)
+	_ sdistd.Rand  = (*FakeRand)(nil)
+	_ sdistd.Env   = (*FakeEnv)(nil)
+)
+
+// FakeClock implements sdistd.Clock. Time is moved only by Advance and
+// Set, channels returned by After receive when the time reaches their
+// deadline. It's safe for concurrent use.
+type FakeClock struct {
+	mux     sync.Mutex
+	now     time.Time
+	waiters []waiter
+}
+
+// Global implements sdi.Globalizer interface.
+func (*FakeClock) Global() {}
+
+type waiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewFakeClock returns FakeClock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns current fake time.
+func (c *FakeClock) Now() time.Time {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.now
+}
+
+// Since returns fake time elapsed since t.
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// After returns channel receiving fake time once it's advanced by d.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	w := waiter{at: c.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		w.ch <- c.now
+		return w.ch
+	}
+	c.waiters = append(c.waiters, w)
+	return w.ch
+}
+
+// Advance moves fake time forward by d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set sets fake time to t and fires channels returned by After whose
+// deadline has been reached.
+func (c *FakeClock) Set(t time.Time) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.now = t
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(t) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- t
+	}
+	c.waiters = pending
+}
+
+// FakeRand implements sdistd.Rand returning the same sequence of numbers
+// for the same seed. It's safe for concurrent use.
+type FakeRand struct {
+	mux sync.Mutex
+	r   *rand.Rand
+}
+
+// Global implements sdi.Globalizer interface.
+func (*FakeRand) Global() {}
+
+// NewFakeRand returns FakeRand seeded by seed.
+func NewFakeRand(seed int64) *FakeRand {
+	return &FakeRand{r: rand.New(rand.NewSource(seed))}
+}
+
+// Int63 returns the next 63-bit integer of the sequence.
+func (r *FakeRand) Int63() int64 {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.r.Int63()
+}
+
+// Intn returns the next number of the sequence in [0,n).
+func (r *FakeRand) Intn(n int) int {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.r.Intn(n)
+}
+
+// Float64 returns the next number of the sequence in [0.0,1.0).
+func (r *FakeRand) Float64() float64 {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.r.Float64()
+}
+
+// FakeEnv implements sdistd.Env reading variables from a map instead of
+// the process environment. It's safe for concurrent use.
+type FakeEnv struct {
+	mux  sync.RWMutex
+	vars map[string]string
+}
+
+// Global implements sdi.Globalizer interface.
+func (*FakeEnv) Global() {}
+
+// NewFakeEnv returns FakeEnv holding copy of vars.
+func NewFakeEnv(vars map[string]string) *FakeEnv {
+	e := &FakeEnv{vars: make(map[string]string, len(vars))}
+	for k, v := range vars {
+		e.vars[k] = v
+	}
+	return e
+}
+
+// Lookup returns the variable key and whether it's set.
+func (e *FakeEnv) Lookup(key string) (string, bool) {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+	v, ok := e.vars[key]
+	return v, ok
+}
+
+// Get returns the variable key or empty string if it's not set.
+func (e *FakeEnv) Get(key string) string {
+	v, _ := e.Lookup(key)
+	return v
+}
+
+// Set sets the variable key to value.
+func (e *FakeEnv) Set(key, value string) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.vars[key] = value
+}
@@ -0,0 +1,56 @@
+package sditest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi/sdistd"
+	"github.com/axkit/sdi/sditest"
+)
+
+type ticketer struct {
+	Clock sdistd.Clock
+	Rand  sdistd.Rand
+	Env   sdistd.Env
+}
+
+func (*ticketer) Global() {}
+
+func TestFakes(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := sditest.NewFakeClock(start)
+	env := sditest.NewFakeEnv(map[string]string{"REGION": "eu"})
+	tk := ticketer{}
+	sditest.Start(t, clock, sditest.NewFakeRand(1), env, &tk)
+
+	if tk.Clock != clock || tk.Env != env || tk.Rand == nil {
+		t.Fatalf("expected fakes to be injected, got %+v", tk)
+	}
+
+	after := tk.Clock.After(time.Minute)
+	clock.Advance(30 * time.Second)
+	select {
+	case <-after:
+		t.Fatal("expected timer not to fire before deadline")
+	default:
+	}
+	clock.Advance(30 * time.Second)
+	if got := <-after; !got.Equal(start.Add(time.Minute)) {
+		t.Errorf("expected %s, got %s", start.Add(time.Minute), got)
+	}
+	if d := tk.Clock.Since(start); d != time.Minute {
+		t.Errorf("expected 1m elapsed, got %s", d)
+	}
+
+	if a, b := sditest.NewFakeRand(7).Int63(), sditest.NewFakeRand(7).Int63(); a != b {
+		t.Errorf("expected the same sequence for the same seed, got %d and %d", a, b)
+	}
+
+	env.Set("ZONE", "a")
+	if tk.Env.Get("REGION") != "eu" || tk.Env.Get("ZONE") != "a" {
+		t.Error("expected variables of fake environment")
+	}
+	if _, ok := tk.Env.Lookup("HOME"); ok {
+		t.Error("expected process environment not to be read")
+	}
+}
@@ -0,0 +1,53 @@
+// Package sditest provides helpers for testing objects wired by
+// package sdi.
+package sditest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+// Start adds objects into a new container, builds dependencies, inits
+// and starts the objects. The container is stopped when the test and all
+// its subtests complete. Any error fails the test immediately.
+func Start(t testing.TB, objects ...interface{}) *sdi.SimpleContainer {
+	t.Helper()
+
+	c := sdi.New()
+	c.Add(objects...)
+	if err := c.Build(); err != nil {
+		t.Fatalf("sditest: build dependencies: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := c.InitRequired(ctx); err != nil {
+		t.Fatalf("sditest: init: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := c.Stop(context.Background()); err != nil {
+			t.Errorf("sditest: stop: %v", err)
+		}
+	})
+	if err := c.StartRunners(ctx); err != nil {
+		t.Fatalf("sditest: start: %v", err)
+	}
+	return c
+}
+
+// AssertWired fails the test if the field pointed to by field is nil.
+//
+//	sditest.AssertWired(t, &b.AService)
+func AssertWired(t testing.TB, field interface{}) {
+	t.Helper()
+
+	v := reflect.ValueOf(field)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		t.Fatalf("sditest: AssertWired expects pointer to field, got %T", field)
+	}
+	if v.Elem().IsZero() {
+		t.Errorf("sditest: %s is not wired", v.Elem().Type())
+	}
+}
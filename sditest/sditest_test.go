@@ -0,0 +1,59 @@
+package sditest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/axkit/sdi/sditest"
+)
+
+type Store interface {
+	Get(string) string
+}
+
+type store struct {
+	stopped bool
+}
+
+func (s *store) Init(ctx context.Context) error { return nil }
+func (s *store) Stop(ctx context.Context) error { s.stopped = true; return nil }
+func (s *store) Get(k string) string            { return k }
+
+type service struct {
+	Store Store
+}
+
+func (s *service) Start(ctx context.Context) error { return nil }
+
+func TestStart(t *testing.T) {
+	st := store{}
+	t.Run("started", func(t *testing.T) {
+		svc := service{}
+		sditest.Start(t, &st, &svc)
+		sditest.AssertWired(t, &svc.Store)
+	})
+	if !st.stopped {
+		t.Error("expected container to be stopped on cleanup")
+	}
+}
+
+// recorder records failures instead of failing the test.
+type recorder struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failed = true
+}
+
+func TestAssertWired(t *testing.T) {
+	r := recorder{TB: t}
+	svc := service{}
+	sditest.AssertWired(&r, &svc.Store)
+	if !r.failed {
+		t.Error("expected failure for nil field")
+	}
+}
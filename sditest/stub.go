@@ -0,0 +1,110 @@
+package sditest
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/axkit/sdi"
+)
+
+var (
+	stubsMux sync.RWMutex
+	stubs    = make(map[reflect.Type]interface{})
+)
+
+// Stubs returns option of sdi.New filling dependencies no containered
+// object satisfies with stubs made by Stub, so a unit test can wire
+// a single service without constructing its whole dependency tree:
+//
+//	c := sdi.New(sditest.Stubs())
+//	c.Add(&svc, fakeRepo)
+//
+// Stubs are injected instead of leaving fields nil, therefore strict
+// unresolved policy does not report them. Dependencies the tested code
+// actually calls should be added as fakes.
+func Stubs() sdi.Option {
+	return sdi.WithFallbackResolver(Stub)
+}
+
+// Stub returns stub of type t or false if t can't be stubbed.
+//
+// Stub of a function type is a function returning zero values.
+//
+// Stub of an interface type is the stub registered by RegisterStub,
+// usually generated by sdigen -stubs, whose methods panic with message
+// "sditest: stub of pkg.Iface.Method called".
+//
+// Otherwise it's a value of a struct type generated by reflect.StructOf
+// embedding the interface. Go reflection can't define methods, so methods
+// of such stub panic with message "reflect: StructOf does not support
+// methods of embedded interfaces" naming neither the interface nor
+// the method. Interfaces without methods, unnamed or unexported
+// interfaces and interfaces with unexported methods are not stubbed this
+// way.
+func Stub(t reflect.Type) (interface{}, bool) {
+	stubsMux.RLock()
+	s, ok := stubs[t]
+	stubsMux.RUnlock()
+	if ok {
+		return s, true
+	}
+
+	switch t.Kind() {
+	case reflect.Func:
+		return reflect.MakeFunc(t, func([]reflect.Value) []reflect.Value {
+			out := make([]reflect.Value, t.NumOut())
+			for k := range out {
+				out[k] = reflect.Zero(t.Out(k))
+			}
+			return out
+		}).Interface(), true
+	case reflect.Interface:
+		return interfaceStub(t)
+	}
+	return nil, false
+}
+
+// RegisterStub registers stub of interface type T returned by Stub.
+// sdigen -stubs generates stubs of interfaces containered objects depend
+// on, registered by init functions of a test file:
+//
+//	//go:generate sdigen -stubs
+//
+// It panics if T is not an interface type.
+func RegisterStub[T any](stub T) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("sditest: stub of %s, not an interface", t))
+	}
+
+	stubsMux.Lock()
+	defer stubsMux.Unlock()
+	stubs[t] = stub
+}
+
+// interfaceStub returns value of a struct type embedding interface t.
+func interfaceStub(t reflect.Type) (o interface{}, ok bool) {
+	if t.NumMethod() == 0 || !isExported(t.Name()) {
+		return nil, false
+	}
+	for k := 0; k < t.NumMethod(); k++ {
+		if !t.Method(k).IsExported() {
+			return nil, false
+		}
+	}
+	defer func() {
+		// StructOf panics on types it doesn't support.
+		if recover() != nil {
+			o, ok = nil, false
+		}
+	}()
+
+	st := reflect.StructOf([]reflect.StructField{{Name: t.Name(), Type: t, Anonymous: true}})
+	v := reflect.New(st).Elem().Interface()
+	return v, reflect.TypeOf(v).Implements(t)
+}
+
+func isExported(name string) bool {
+	return name != "" && name[0] >= 'A' && name[0] <= 'Z'
+}
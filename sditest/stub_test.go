@@ -0,0 +1,110 @@
+package sditest_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/axkit/sdi"
+	"github.com/axkit/sdi/sditest"
+)
+
+type Mailer interface {
+	Send(to, body string) error
+}
+
+type signup struct {
+	Store  Store
+	Mailer Mailer
+	Now    func() (int64, error)
+}
+
+func (s *signup) Init(ctx context.Context) error { return nil }
+
+func (s *signup) Register(name string) string {
+	return s.Store.Get(name)
+}
+
+func TestStubs(t *testing.T) {
+	st := store{}
+	s := signup{}
+	c := sdi.New(sditest.Stubs(), sdi.WithStrictMode())
+	c.Add(&st, &s)
+	if err := c.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Store != &st {
+		t.Errorf("expected containered object to be injected, got %T", s.Store)
+	}
+	if got := s.Register("bob"); got != "bob" {
+		t.Errorf("expected bob, got %q", got)
+	}
+	if n, err := s.Now(); n != 0 || err != nil {
+		t.Errorf("expected function stub to return zero values, got %d, %v", n, err)
+	}
+	if s.Mailer == nil {
+		t.Fatal("expected Mailer to be stubbed")
+	}
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "embedded interfaces") {
+			t.Errorf("expected method of interface stub to panic, got %v", r)
+		}
+	}()
+	s.Mailer.Send("bob", "welcome")
+}
+
+type hidden interface {
+	Get(string) string
+}
+
+func TestStubUnsupported(t *testing.T) {
+	for _, typ := range []reflect.Type{
+		reflect.TypeOf((*interface{})(nil)).Elem(),
+		reflect.TypeOf((*hidden)(nil)).Elem(),
+		reflect.TypeOf((*store)(nil)),
+	} {
+		if o, ok := sditest.Stub(typ); ok {
+			t.Errorf("expected %s not to be stubbed, got %T", typ, o)
+		}
+	}
+}
+
+type Notifier interface {
+	Notify(to string, args ...interface{}) error
+}
+
+// stubNotifier is the stub sdigen -stubs generates for Notifier.
+type stubNotifier struct{}
+
+func (stubNotifier) Notify(string, ...interface{}) error {
+	panic("sditest: stub of sditest_test.Notifier.Notify called")
+}
+
+func TestRegisterStub(t *testing.T) {
+	sditest.RegisterStub[Notifier](stubNotifier{})
+
+	o, ok := sditest.Stub(reflect.TypeOf((*Notifier)(nil)).Elem())
+	if !ok {
+		t.Fatal("expected registered stub")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != "sditest: stub of sditest_test.Notifier.Notify called" {
+				t.Errorf("expected stub to panic naming the method, got %v", r)
+			}
+		}()
+		o.(Notifier).Notify("bob")
+	}()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected RegisterStub of not interface type to panic")
+		}
+	}()
+	sditest.RegisterStub(store{})
+}
@@ -0,0 +1,14 @@
+// Command sdivet reports wiring errors of sdi containers, see package
+// sdivet. It's run standalone or by go vet:
+//
+//	go vet -vettool=$(which sdivet) ./...
+package main
+
+import (
+	"github.com/axkit/sdi/sdivet"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(sdivet.Analyzer)
+}
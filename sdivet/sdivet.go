@@ -0,0 +1,400 @@
+// Package sdivet defines an analyzer reporting wiring errors of sdi
+// containers at build time.
+//
+// The analyzer inspects objects added into containers by Add, AddService,
+// AddBlocking, AddNamed, AddGrouped, AddForProfile, AddIf, AddAs and
+// NewModule and reports
+//
+//   - objects implementing none of sdi.Runner, sdi.Initializer,
+//     sdi.Stopper and sdi.Globalizer, which Add rejects at runtime;
+//   - exported interface fields of added objects no object added in
+//     the package can be injected into.
+//
+// Fields are checked only if all objects of the package are known: calls
+// passing objects with ellipsis, Merge, NewChild and Clone turn the check
+// off. Fields tagged with qualifier, env, ctx or unresolved=ignore, fields
+// set to a value other than nil in the composite literal, fields of types
+// registered by LoggerFactory and fields of interfaces having method
+// BuildInfo are not reported. Objects added with option NotInjectable are
+// checked, but never satisfy fields.
+//
+// The analyzer is run by go vet with command sdivet:
+//
+//	go install github.com/axkit/sdi/sdivet/cmd/sdivet@latest
+//	go vet -vettool=$(which sdivet) ./...
+package sdivet
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const sdiPath = "github.com/axkit/sdi"
+
+// Analyzer reports wiring errors of sdi containers.
+var Analyzer = &analysis.Analyzer{
+	Name:     "sdivet",
+	Doc:      "report objects sdi container rejects and interface fields it can not inject",
+	URL:      "https://pkg.go.dev/github.com/axkit/sdi/sdivet",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// added is an object added into container.
+type added struct {
+	expr ast.Expr
+	typ  types.Type
+	set  map[string]bool // fields set in the composite literal
+	all  bool            // all fields set by positional composite literal
+}
+
+type checker struct {
+	pass       *analysis.Pass
+	lifecycle  []*types.Interface
+	objects    []added
+	registered []types.Type // types of objects, functions and loggers
+	incomplete bool
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	ch := checker{pass: pass}
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	ins.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		ch.call(n.(*ast.CallExpr))
+	})
+
+	for _, o := range ch.objects {
+		ch.checkContainerable(o)
+	}
+	if !ch.incomplete {
+		for _, o := range ch.objects {
+			ch.checkFields(o)
+		}
+	}
+	return nil, nil
+}
+
+// call records objects added into container by the call.
+func (ch *checker) call(call *ast.CallExpr) {
+	fn, ok := typeutil.Callee(ch.pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != sdiPath {
+		return
+	}
+	ch.lookupLifecycle(fn.Pkg())
+
+	args := call.Args
+	if call.Ellipsis.IsValid() {
+		switch fn.Name() {
+		case "Add", "AddService", "AddBlocking", "AddGrouped", "AddForProfile", "AddIf", "AddFunc", "NewModule", "Provide", "AddTransient":
+			ch.incomplete = true
+			return
+		}
+	}
+
+	switch fn.Name() {
+	case "Add", "AddService", "AddBlocking":
+		ch.add(args)
+	case "AddNamed", "AddGrouped", "AddForProfile", "AddIf", "AddAs", "NewModule":
+		if len(args) > 0 {
+			ch.add(args[1:])
+		}
+	case "AddFunc":
+		for _, a := range args {
+			ch.registered = append(ch.registered, ch.pass.TypesInfo.TypeOf(a))
+		}
+	case "Provide", "AddTransient":
+		for _, a := range args {
+			sig, ok := ch.pass.TypesInfo.TypeOf(a).Underlying().(*types.Signature)
+			if !ok {
+				continue
+			}
+			// constructors of Provide may return several objects followed
+			// by cleanup function and error, which are never injected.
+			for k := 0; k < sig.Results().Len(); k++ {
+				if t := sig.Results().At(k).Type(); !isCleanupOrError(t) {
+					ch.registered = append(ch.registered, t)
+				}
+			}
+		}
+	case "LoggerFactory":
+		ch.loggerFactory(call, fn)
+	case "Merge", "NewChild", "Clone":
+		ch.incomplete = true
+	}
+}
+
+// isCleanupOrError reports whether t is error or cleanup function type
+// of constructors, func() or func(context.Context) error.
+func isCleanupOrError(t types.Type) bool {
+	errType := types.Universe.Lookup("error").Type()
+	if types.Identical(t, errType) {
+		return true
+	}
+	sig, ok := t.(*types.Signature)
+	if !ok {
+		return false
+	}
+	if sig.Params().Len() == 0 && sig.Results().Len() == 0 {
+		return true
+	}
+	if sig.Params().Len() != 1 || sig.Results().Len() != 1 || !types.Identical(sig.Results().At(0).Type(), errType) {
+		return false
+	}
+	n, ok := sig.Params().At(0).Type().(*types.Named)
+	return ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == "context" && n.Obj().Name() == "Context"
+}
+
+// loggerFactory records type of loggers created by the factory.
+func (ch *checker) loggerFactory(call *ast.CallExpr, fn *types.Func) {
+	if fn.Type().(*types.Signature).Recv() == nil {
+		// generic function, the logger type is the type argument.
+		if inst, ok := ch.pass.TypesInfo.Instances[calleeIdent(call.Fun)]; ok && inst.TypeArgs.Len() == 1 {
+			ch.registered = append(ch.registered, inst.TypeArgs.At(0))
+		}
+		return
+	}
+	if len(call.Args) == 0 {
+		return
+	}
+	if p, ok := ch.pass.TypesInfo.TypeOf(call.Args[0]).(*types.Pointer); ok {
+		ch.registered = append(ch.registered, p.Elem())
+	}
+}
+
+// calleeIdent returns identifier of the called function.
+func calleeIdent(e ast.Expr) *ast.Ident {
+	switch e := e.(type) {
+	case *ast.Ident:
+		return e
+	case *ast.SelectorExpr:
+		return e.Sel
+	case *ast.IndexExpr:
+		return calleeIdent(e.X)
+	case *ast.IndexListExpr:
+		return calleeIdent(e.X)
+	}
+	return nil
+}
+
+// lookupLifecycle finds lifecycle interfaces of package sdi.
+func (ch *checker) lookupLifecycle(pkg *types.Package) {
+	if ch.lifecycle != nil {
+		return
+	}
+	for _, name := range []string{"Runner", "Initializer", "Stopper", "Globalizer"} {
+		if tn, ok := pkg.Scope().Lookup(name).(*types.TypeName); ok {
+			if it, ok := tn.Type().Underlying().(*types.Interface); ok {
+				ch.lifecycle = append(ch.lifecycle, it)
+			}
+		}
+	}
+}
+
+// isNil reports whether e is the predeclared nil, which leaves a field
+// unset.
+func (ch *checker) isNil(e ast.Expr) bool {
+	id, ok := ast.Unparen(e).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	_, ok = ch.pass.TypesInfo.Uses[id].(*types.Nil)
+	return ok
+}
+
+// add records objects added by expressions args. Objects added with
+// option NotInjectable are checked, but not registered as candidates.
+func (ch *checker) add(args []ast.Expr) {
+	var objects []ast.Expr
+	injectable := true
+	for _, a := range args {
+		if !ch.isAddOption(a) {
+			objects = append(objects, a)
+			continue
+		}
+		if call, ok := a.(*ast.CallExpr); ok {
+			if fn, ok := typeutil.Callee(ch.pass.TypesInfo, call).(*types.Func); ok && fn.Name() == "NotInjectable" {
+				injectable = false
+			}
+		}
+	}
+
+	for _, a := range objects {
+		o := added{expr: a, typ: ch.pass.TypesInfo.TypeOf(a), set: make(map[string]bool)}
+		if ue, ok := a.(*ast.UnaryExpr); ok && ue.Op == token.AND {
+			if cl, ok := ue.X.(*ast.CompositeLit); ok {
+				for _, elt := range cl.Elts {
+					kv, ok := elt.(*ast.KeyValueExpr)
+					if !ok {
+						o.all = true
+						break
+					}
+					if id, ok := kv.Key.(*ast.Ident); ok && !ch.isNil(kv.Value) {
+						o.set[id.Name] = true
+					}
+				}
+			}
+		}
+		ch.objects = append(ch.objects, o)
+		if injectable {
+			ch.registered = append(ch.registered, o.typ)
+		}
+	}
+}
+
+// isAddOption reports whether expression a is of type sdi.AddOption.
+func (ch *checker) isAddOption(a ast.Expr) bool {
+	n, ok := ch.pass.TypesInfo.TypeOf(a).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := n.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == sdiPath && obj.Name() == "AddOption"
+}
+
+// checkContainerable reports object o if it implements no lifecycle
+// interface. Objects of interface types are checked at runtime.
+func (ch *checker) checkContainerable(o added) {
+	if o.typ == nil || types.IsInterface(o.typ) || len(ch.lifecycle) == 0 {
+		return
+	}
+	for _, it := range ch.lifecycle {
+		if types.Implements(o.typ, it) {
+			return
+		}
+	}
+	ch.pass.Reportf(o.expr.Pos(), "%s does not implement Runner, Initializer, Stopper or Globalizer",
+		types.TypeString(o.typ, types.RelativeTo(ch.pass.Pkg)))
+}
+
+// checkFields reports exported interface fields of object o no registered
+// object is assignable to.
+func (ch *checker) checkFields(o added) {
+	if o.all {
+		return
+	}
+	p, ok := o.typ.(*types.Pointer)
+	if !ok {
+		return
+	}
+	st, ok := p.Elem().Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+	ch.checkStruct(o, st, "", true)
+}
+
+func (ch *checker) checkStruct(o added, st *types.Struct, prefix string, top bool) {
+	for k := 0; k < st.NumFields(); k++ {
+		f := st.Field(k)
+		name := prefix + f.Name()
+		if f.Embedded() {
+			if es, ok := f.Type().Underlying().(*types.Struct); ok {
+				ch.checkStruct(o, es, name+".", false)
+			}
+			continue
+		}
+		if !f.Exported() || top && o.set[f.Name()] {
+			continue
+		}
+
+		opts := parseTag(reflect.StructTag(st.Tag(k)).Get("sdi"))
+		if _, ok := opts["qualifier"]; ok {
+			continue
+		}
+		if _, ok := opts["env"]; ok {
+			continue
+		}
+		if _, ok := opts["ctx"]; ok {
+			continue
+		}
+		if opts["unresolved"] == "ignore" {
+			continue
+		}
+
+		if ns, ok := f.Type().(*types.Struct); ok {
+			ch.checkStruct(o, ns, name+".", false)
+			continue
+		}
+		if !types.IsInterface(f.Type()) || isSDI(f.Type()) || isBuildInfo(f.Type()) {
+			continue
+		}
+		if !ch.satisfied(o, f.Type()) {
+			ch.pass.Reportf(o.expr.Pos(), "%s.%s: no object added into container implements %s",
+				types.TypeString(o.typ, types.RelativeTo(ch.pass.Pkg)), name,
+				types.TypeString(f.Type(), types.RelativeTo(ch.pass.Pkg)))
+		}
+	}
+}
+
+// satisfied returns true if a registered type other than type of object o
+// is assignable to type t.
+func (ch *checker) satisfied(o added, t types.Type) bool {
+	for _, r := range ch.registered {
+		if r == nil {
+			continue
+		}
+		if types.Identical(r, o.typ) && !ch.addedTwice(r) {
+			continue
+		}
+		if types.AssignableTo(r, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// addedTwice returns true if objects of type t are added more than once.
+func (ch *checker) addedTwice(t types.Type) bool {
+	n := 0
+	for _, r := range ch.registered {
+		if r != nil && types.Identical(r, t) {
+			n++
+		}
+	}
+	return n > 1
+}
+
+// isSDI returns true if t is a type declared in package sdi, e.g.
+// sdi.Container injected by the container itself.
+func isSDI(t types.Type) bool {
+	n, ok := t.(*types.Named)
+	return ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == sdiPath
+}
+
+// isBuildInfo returns true if t is an interface having method BuildInfo,
+// such fields get sdi.BuildInfo injected by the container.
+func isBuildInfo(t types.Type) bool {
+	it, ok := t.Underlying().(*types.Interface)
+	if !ok {
+		return false
+	}
+	for k := 0; k < it.NumMethods(); k++ {
+		if it.Method(k).Name() == "BuildInfo" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTag parses comma separated list of key=value pairs the same way
+// package sdi does.
+func parseTag(tag string) map[string]string {
+	res := make(map[string]string)
+	for _, kv := range strings.Split(tag, ",") {
+		kv = strings.TrimSpace(kv)
+		if kv == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(kv, "=")
+		res[k] = v
+	}
+	return res
+}
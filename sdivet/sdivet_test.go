@@ -0,0 +1,12 @@
+package sdivet_test
+
+import (
+	"testing"
+
+	"github.com/axkit/sdi/sdivet"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), sdivet.Analyzer, "app", "provided", "bundle")
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+
+	"github.com/axkit/sdi"
+)
+
+type Storage interface{ Get(key string) string }
+
+type Mailer interface{ Send(to string) error }
+
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+type Clock interface{ Now() int64 }
+
+type storage struct{}
+
+func (s *storage) Init(ctx context.Context) error { return nil }
+func (s *storage) Get(key string) string          { return key }
+
+type mailer struct{}
+
+func (m *mailer) Init(ctx context.Context) error { return nil }
+func (m *mailer) Send(to string) error           { return nil }
+
+type service struct {
+	Storage   Storage
+	Mailer    Mailer
+	Logger    Logger
+	Clock     Clock
+	Replica   Storage `sdi:"qualifier=replica"`
+	Optional  Mailer  `sdi:"unresolved=ignore"`
+	Container sdi.Container
+}
+
+func (s *service) Start(ctx context.Context) error { return nil }
+
+type systemClock struct{}
+
+func (systemClock) Now() int64 { return 0 }
+
+type config struct {
+	DSN string
+}
+
+func register(c *sdi.SimpleContainer) {
+	c.Add(&storage{}, &service{Clock: nil}) // want `\*service.Mailer: no object added into container implements Mailer` `\*service.Clock: no object added into container implements Clock`
+	c.Add(&service{Clock: systemClock{}})   // want `\*service.Mailer: no object added into container implements Mailer`
+	c.AddNamed("replica", &storage{})
+	c.Add(&mailer{}, sdi.NotInjectable())
+	c.Add(&config{}) // want `\*config does not implement Runner, Initializer, Stopper or Globalizer`
+	sdi.LoggerFactory(c, func(string) Logger { return nil })
+}
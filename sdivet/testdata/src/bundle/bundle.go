@@ -0,0 +1,35 @@
+package bundle
+
+import (
+	"context"
+
+	"github.com/axkit/sdi"
+)
+
+type Storage interface{ Get(key string) string }
+
+type Mailer interface{ Send(to string) error }
+
+type Cleaner interface{ Clean() }
+
+type storage struct{}
+
+func (s *storage) Get(key string) string { return key }
+
+type mailer struct{}
+
+func (m *mailer) Send(to string) error { return nil }
+
+type service struct {
+	Storage Storage
+	Mailer  Mailer
+	Cleaner Cleaner
+}
+
+func (s *service) Start(ctx context.Context) error { return nil }
+
+// register provides Storage and Mailer by a single constructor.
+func register(c *sdi.SimpleContainer) {
+	c.Provide(func() (*storage, *mailer, func(), error) { return &storage{}, &mailer{}, nil, nil })
+	c.Add(&service{}) // want `\*service.Cleaner: no object added into container implements Cleaner`
+}
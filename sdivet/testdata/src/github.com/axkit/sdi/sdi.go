@@ -0,0 +1,30 @@
+// Package sdi is a stub of github.com/axkit/sdi for analyzer tests.
+package sdi
+
+import "context"
+
+type Runner interface{ Start(context.Context) error }
+
+type Initializer interface{ Init(context.Context) error }
+
+type Stopper interface{ Stop(context.Context) error }
+
+type Globalizer interface{ Global() }
+
+type Container interface{ Add(...interface{}) }
+
+type AddOption func()
+
+func NotInjectable() AddOption { return nil }
+
+type SimpleContainer struct{}
+
+func New() *SimpleContainer { return &SimpleContainer{} }
+
+func (c *SimpleContainer) Add(o ...interface{})                 {}
+func (c *SimpleContainer) AddNamed(name string, o interface{})  {}
+func (c *SimpleContainer) AddFunc(fn ...interface{})            {}
+func (c *SimpleContainer) Provide(constructor ...interface{})   {}
+func (c *SimpleContainer) NewChild() *SimpleContainer           { return c }
+func AddAs[I any](c *SimpleContainer, o I)                      {}
+func LoggerFactory[L any](c *SimpleContainer, f func(string) L) {}
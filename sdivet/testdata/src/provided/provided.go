@@ -0,0 +1,29 @@
+package provided
+
+import (
+	"context"
+
+	"github.com/axkit/sdi"
+)
+
+type Storage interface{ Get(key string) string }
+
+type Mailer interface{ Send(to string) error }
+
+type storage struct{}
+
+func (s *storage) Get(key string) string { return key }
+
+type service struct {
+	Storage Storage
+	Mailer  Mailer
+}
+
+func (s *service) Start(ctx context.Context) error { return nil }
+
+// register provides Storage by constructor and Mailer by parent container.
+func register(parent *sdi.SimpleContainer) {
+	c := parent.NewChild()
+	c.Provide(func() *storage { return &storage{} })
+	c.Add(&service{})
+}
@@ -0,0 +1,51 @@
+package sdi
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// callSetters calls setter methods of the object at position i. Setter is
+// an exported method named SetXxx, where Xxx starts with an upper-case
+// letter, with a single parameter of a non-empty interface type and no
+// results. Methods like Setup or Settle are not setters.
+func (c *SimpleContainer) callSetters(i int) error {
+	var errs []error
+	v := reflect.ValueOf(c.objects[i])
+	t := v.Type()
+	for m := 0; m < t.NumMethod(); m++ {
+		mt := t.Method(m)
+		if !isSetter(mt.Name) {
+			continue
+		}
+		// the first parameter is the receiver.
+		if mt.Type.NumIn() != 2 || mt.Type.NumOut() != 0 {
+			continue
+		}
+		pt := mt.Type.In(1)
+		if pt.Kind() != reflect.Interface || pt.NumMethod() == 0 {
+			continue
+		}
+		pv, ok, err := c.value(i, pt, mt.Name+"()", v.Method(m), c.opts.unresolvedPolicy)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if ok {
+			v.Method(m).Call([]reflect.Value{pv})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isSetter reports whether method name is SetXxx.
+func isSetter(name string) bool {
+	if !strings.HasPrefix(name, "Set") {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(name[len("Set"):])
+	return unicode.IsUpper(r)
+}
@@ -0,0 +1,78 @@
+package sdi_test
+
+import (
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type encapsulated struct {
+	ager   AI
+	gender CI
+	calls  int
+}
+
+func (e *encapsulated) Global() {}
+
+func (e *encapsulated) SetAger(a AI) {
+	e.ager = a
+	e.calls++
+}
+
+func (e *encapsulated) SetGender(c CI) {
+	e.gender = c
+	e.calls++
+}
+
+// SetName is not a setter: parameter is not an interface.
+func (e *encapsulated) SetName(string) {
+	e.calls++
+}
+
+func TestSetterInjection(t *testing.T) {
+	cs := sdi.New()
+	a := A{}
+	e := encapsulated{}
+	cs.Add(&a, &e)
+	cs.BuildDependencies()
+
+	if e.ager != &a {
+		t.Error("expected SetAger to be called with a")
+	}
+	if e.gender != nil || e.calls != 1 {
+		t.Errorf("unexpected setter calls: %d", e.calls)
+	}
+
+	deps := cs.Objects()[1].Dependencies
+	if len(deps) != 1 || deps[0].Field != "SetAger()" {
+		t.Errorf("unexpected dependencies %v", deps)
+	}
+}
+
+type settling struct {
+	calls int
+}
+
+func (s *settling) Global() {}
+
+// Setup is not a setter: Set is not followed by an upper-case letter.
+func (s *settling) Setup(a AI) {
+	s.calls++
+}
+
+// SetAny is not a setter: parameter is the empty interface.
+func (s *settling) SetAny(v interface{}) {
+	s.calls++
+}
+
+func TestSetterLookalikes(t *testing.T) {
+	cs := sdi.New()
+	s := settling{}
+	cs.Add(&A{}, &C{}, &s)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if s.calls != 0 {
+		t.Errorf("expected Setup and SetAny not to be called, got %d calls", s.calls)
+	}
+}
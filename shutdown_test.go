@@ -0,0 +1,96 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+// stuckStopper ignores cancellation in Stop.
+type stuckStopper struct {
+	release chan struct{}
+}
+
+func (h *stuckStopper) Init(ctx context.Context) error { return nil }
+
+func (h *stuckStopper) Stop(ctx context.Context) error {
+	<-h.release
+	return nil
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	var jn journal
+	h := stuckStopper{release: make(chan struct{})}
+	defer close(h.release)
+
+	cs := sdi.New(sdi.WithShutdownTimeout(20 * time.Millisecond))
+	cs.AddNamed("first", &journaled{name: "a", journal: &jn})
+	cs.AddNamed("hanging", &h)
+	cs.AddNamed("last", &journaled{name: "b", journal: &jn})
+	cs.BuildDependencies()
+	if err := cs.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	started := time.Now()
+	err := cs.Stop(context.Background())
+	if time.Since(started) > time.Second {
+		t.Error("expected Stop to return after shutdown timeout")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	for _, s := range []string{"hanging.Stop abandoned", "first.Stop abandoned"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("expected error to contain %q, got %v", s, err)
+		}
+	}
+	if strings.Contains(err.Error(), "last") {
+		t.Errorf("expected last object to be stopped, got %v", err)
+	}
+}
+
+type journaledPool struct {
+	journaled
+}
+
+func (p *journaledPool) Ping() bool { return true }
+
+type journaledAPI struct {
+	journaled
+	Pool Pinger
+}
+
+func TestStopDependencyOrder(t *testing.T) {
+	var jn journal
+	api := &journaledAPI{journaled: journaled{name: "api", journal: &jn}}
+	pool := &journaledPool{journaled{name: "pool", journal: &jn}}
+	cache := &journaled{name: "cache", journal: &jn}
+
+	ctx := context.Background()
+	cs := sdi.New()
+	cs.Add(api, cache, pool)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+	jn.calls = nil
+	if err := cs.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"stop api", "stop pool", "stop cache"}
+	if !reflect.DeepEqual(jn.calls, expected) {
+		t.Errorf("expected %v, got %v", expected, jn.calls)
+	}
+}
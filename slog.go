@@ -0,0 +1,90 @@
+package sdi
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithSlog makes the container write structured logs of its operations
+// to l: objects added and fields wired at debug level, Init and Start
+// of objects at info level, failures at error level.
+func WithSlog(l *slog.Logger) Option {
+	return func(o *options) {
+		o.slog = l
+	}
+}
+
+// subscribeSlog subscribes structured logger set by WithSlog to
+// container events.
+func (c *SimpleContainer) subscribeSlog() {
+	l := c.opts.slog
+	if l == nil {
+		return
+	}
+	c.Subscribe(func(e Event) {
+		ctx := context.Background()
+		switch e.Type {
+		case ObjectAdded:
+			l.DebugContext(ctx, "sdi: object added", "object", e.Object)
+		case Wired:
+			l.DebugContext(ctx, "sdi: dependencies built")
+		case InitStarted:
+			l.DebugContext(ctx, "sdi: init started", "object", e.Object)
+		case InitFinished:
+			if e.Err != nil {
+				l.ErrorContext(ctx, "sdi: init failed", "object", e.Object, "duration", e.Duration, "error", e.Err)
+				return
+			}
+			l.InfoContext(ctx, "sdi: initialized", "object", e.Object, "duration", e.Duration)
+		case RunnerStarted:
+			l.InfoContext(ctx, "sdi: starting", "object", e.Object)
+		case RunnerExited:
+			if e.Err != nil {
+				l.ErrorContext(ctx, "sdi: start failed", "object", e.Object, "duration", e.Duration, "error", e.Err)
+				return
+			}
+			l.InfoContext(ctx, "sdi: runner exited", "object", e.Object, "duration", e.Duration)
+		case ShutdownBegan:
+			l.InfoContext(ctx, "sdi: shutdown began")
+		case InitSlow:
+			if e.Stack != nil {
+				l.WarnContext(ctx, "sdi: init is slow", "object", e.Object, "threshold", e.Duration, "goroutines", string(e.Stack))
+				return
+			}
+			l.WarnContext(ctx, "sdi: init is slow", "object", e.Object, "threshold", e.Duration)
+		case StartBlocking:
+			l.WarnContext(ctx, "sdi: start appears to block", "object", e.Object, "threshold", e.Duration)
+		case CircuitOpened:
+			l.ErrorContext(ctx, "sdi: runner is not restarted", "object", e.Object, "error", e.Err)
+		case LivenessFailed:
+			l.ErrorContext(ctx, "sdi: liveness check failed", "object", e.Object, "error", e.Err)
+		case RunnersStarted:
+			l.InfoContext(ctx, "sdi: runners started")
+		case Reconfigured:
+			if e.Err != nil {
+				l.ErrorContext(ctx, "sdi: reconfigure failed", "error", e.Err)
+				return
+			}
+			l.InfoContext(ctx, "sdi: reconfigured")
+		}
+	})
+}
+
+// slogWiring logs injections made by BuildDependencies at debug level.
+// The caller must hold write lock.
+func (c *SimpleContainer) slogWiring() {
+	l := c.opts.slog
+	if l == nil || !l.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+	for _, d := range c.deps {
+		if d.explicit {
+			l.Debug("sdi: dependency declared", "consumer", c.nameOf(d.consumer), "provider", c.nameOf(d.provider))
+			continue
+		}
+		l.Debug("sdi: field wired", "consumer", c.nameOf(d.consumer), "field", d.field, "provider", c.nameOf(d.provider))
+	}
+	for _, m := range c.missing {
+		l.Debug("sdi: field not wired", "consumer", c.nameOf(m.consumer), "field", m.field)
+	}
+}
@@ -0,0 +1,47 @@
+package sdi_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestSlog(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "duration" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	cs := sdi.New(sdi.WithSlog(l))
+	cs.AddNamed("dict", dictionary{})
+	cs.AddNamed("tr", &translator{})
+	cs.AddNamed("db", &failingRunner{})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	cs.StartRunners(context.Background())
+
+	for _, line := range []string{
+		`level=DEBUG msg="sdi: object added" object=dict`,
+		`level=DEBUG msg="sdi: field wired" consumer=tr field=Dict provider=dict`,
+		`level=INFO msg="sdi: initialized" object=dict`,
+		`level=ERROR msg="sdi: start failed" object=db error="port in use"`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("expected %s in log:\n%s", line, &buf)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package sdi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Snapshot is the state of the container after InitRequired recorded by
+// Snapshot method, see Restore.
+type Snapshot struct {
+	c       *SimpleContainer
+	objects []interface{}
+	states  []objectState
+}
+
+// Snapshot records containered objects and their lifecycle state. It must
+// be called after InitRequired, before StartRunners, otherwise error
+// wrapping ErrInvalidState is returned.
+//
+// Snapshot and Restore let integration tests share initialized objects,
+// e.g. database pools, starting and stopping only runners per test case:
+//
+//	snap, err := c.Snapshot()
+//	...
+//	t.Cleanup(func() { c.Restore(context.Background(), snap) })
+//	c.StartRunners(ctx)
+func (c *SimpleContainer) Snapshot() (*Snapshot, error) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	if c.state != StateInitialized || c.running != "" {
+		return nil, fmt.Errorf("%w: Snapshot called in state %s", ErrInvalidState, c.state)
+	}
+	s := &Snapshot{
+		c:       c,
+		objects: append([]interface{}(nil), c.objects...),
+		states:  make([]objectState, len(c.objects)),
+	}
+	copy(s.states, c.states)
+	return s, nil
+}
+
+// Restore returns the container to the snapshot s. Objects replaced by Swap
+// since the snapshot are swapped back. Started objects are stopped in
+// reverse order, those of them initialized at the snapshot are initialized
+// again. Other objects are neither stopped nor initialized again. After
+// Restore the container is in StateInitialized and runners can be started
+// again by StartRunners.
+//
+// Restore returns error if s is the snapshot of another container.
+func (c *SimpleContainer) Restore(ctx context.Context, s *Snapshot) error {
+	if s.c != c {
+		return errors.New("sdi: snapshot of another container")
+	}
+
+	var errs []error
+	for i, o := range s.objects {
+		c.mux.RLock()
+		cur := c.objects[i]
+		c.mux.RUnlock()
+		if reflect.TypeOf(cur).Comparable() && reflect.TypeOf(o).Comparable() && cur != o {
+			errs = append(errs, c.Swap(ctx, cur, o))
+		}
+	}
+
+	if err := c.begin("Restore", StateInitialized, StateStarted, StatePaused, StateStopped); err != nil {
+		return err
+	}
+	c.cancelWatchdog()
+	errs = append(errs, c.stopWhere(ctx, func(st objectState) bool { return st.started && !st.stopped }))
+	if c.opts.managed {
+		errs = append(errs, c.waitRunners(ctx))
+	}
+
+	for _, i := range c.sequence() {
+		if !s.states[i].inited {
+			continue
+		}
+		if in, ok := c.objects[i].(Initializer); ok && !c.stateOf(i).inited {
+			errs = append(errs, c.initObject(ctx, i, in))
+		}
+	}
+	for i := range s.states {
+		c.setState(i, func(st *objectState) {
+			snap := s.states[i]
+			snap.inited, snap.initTime, snap.initErr = st.inited, st.initTime, st.initErr
+			snap.cancel = nil
+			*st = snap
+		})
+	}
+	return c.end(StateInitialized, errors.Join(errs...))
+}
@@ -0,0 +1,96 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type sharedPool struct {
+	inits, stops int
+}
+
+func (p *sharedPool) Init(ctx context.Context) error {
+	p.inits++
+	return nil
+}
+
+func (p *sharedPool) Stop(ctx context.Context) error {
+	p.stops++
+	return nil
+}
+
+type apiServer struct {
+	Client       Client
+	inits        int
+	starts, stop int
+}
+
+func (s *apiServer) Init(ctx context.Context) error {
+	s.inits++
+	return nil
+}
+
+func (s *apiServer) Start(ctx context.Context) error {
+	s.starts++
+	return nil
+}
+
+func (s *apiServer) Stop(ctx context.Context) error {
+	s.stop++
+	return nil
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	ctx := context.Background()
+	pool, api, real := &sharedPool{}, &apiServer{}, &client{name: "real"}
+	cs := sdi.New()
+	cs.Add(pool, real, api)
+	cs.BuildDependencies()
+	cs.InitRequired(ctx)
+
+	snap, err := cs.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n := 0; n < 3; n++ {
+		if err := cs.Swap(ctx, real, &client{name: "stub"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := cs.StartRunners(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := cs.Restore(ctx, snap); err != nil {
+			t.Fatal(err)
+		}
+		if s := cs.State(); s != sdi.StateInitialized {
+			t.Fatalf("expected state %s, got %s", sdi.StateInitialized, s)
+		}
+	}
+
+	if pool.inits != 1 || pool.stops != 0 {
+		t.Errorf("expected pool to be initialized once and never stopped, got %+v", pool)
+	}
+	if api.starts != 3 || api.stop != 3 || api.inits != 4 {
+		t.Errorf("expected api server to be restarted each time, got %+v", api)
+	}
+	if api.Client != real {
+		t.Error("expected swapped object to be restored")
+	}
+}
+
+func TestSnapshotState(t *testing.T) {
+	cs := sdi.New()
+	cs.Add(&sharedPool{})
+	if _, err := cs.Snapshot(); !errors.Is(err, sdi.ErrInvalidState) {
+		t.Errorf("expected %v, got %v", sdi.ErrInvalidState, err)
+	}
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+	snap, _ := cs.Snapshot()
+	if err := sdi.New().Restore(context.Background(), snap); err == nil {
+		t.Error("expected error on snapshot of another container")
+	}
+}
@@ -0,0 +1,121 @@
+package sdi
+
+import (
+	"context"
+	"fmt"
+)
+
+// StartOne starts the containered runner o after StartRunners, e.g.
+// the runner stopped by StopOne or failed to start, see
+// WithContinueOnStartError. The runner is given by reference or by the
+// name it's reported with, see AddNamed and Namer. The runner stopped
+// before is initialized again if it implements Initializer. Other
+// objects, including dependencies of the runner, are neither initialized
+// nor started. StartOne returns nil if the runner is running already.
+//
+// Start of managed and blocking runners is called in a separate
+// goroutine, their context is derived from ctx.
+//
+// StartOne must be called after StartRunners, otherwise error wrapping
+// ErrInvalidState is returned.
+func (c *SimpleContainer) StartOne(ctx context.Context, o interface{}) error {
+	if err := c.begin("StartOne", StateStarted); err != nil {
+		return err
+	}
+	i, err := c.runnerOf(o)
+	if err != nil {
+		return c.end(StateStarted, err)
+	}
+	r := c.objects[i].(Runner)
+	st := c.stateOf(i)
+	if st.started && !st.stopped {
+		return c.end(StateStarted, nil)
+	}
+
+	if in, ok := c.objects[i].(Initializer); ok && !st.inited {
+		if err := c.initObject(ctx, i, in); err != nil {
+			c.markFailed(i, err)
+			return c.end(StateStarted, err)
+		}
+	}
+	c.setState(i, func(s *objectState) {
+		s.failErr = nil
+		s.halted = false
+	})
+
+	switch {
+	case c.gated(i):
+		c.startGated(ctx, i, r)
+	case c.opts.managed || c.blocking(i):
+		c.startBlocking(ctx, i, r)
+	default:
+		if err := c.startObject(ctx, i, r); err != nil {
+			err = &StartError{Object: c.nameOf(i), Err: err}
+			c.markFailed(i, err)
+			return c.end(StateStarted, err)
+		}
+	}
+	return c.end(StateStarted, nil)
+}
+
+// StopOne stops the containered runner o given by reference or by name,
+// see StartOne, without stopping other objects: Stop is called if the
+// runner implements Stopper and the context passed to its Start is
+// cancelled. Objects depending on the runner are left running.
+// The runner is reported stopped by Status and it's not checked by Health
+// until it's started again by StartOne. StopOne returns nil if the runner
+// is not running.
+//
+// StopOne must be called after StartRunners, otherwise error wrapping
+// ErrInvalidState is returned.
+func (c *SimpleContainer) StopOne(ctx context.Context, o interface{}) error {
+	if err := c.begin("StopOne", StateStarted); err != nil {
+		return err
+	}
+	i, err := c.runnerOf(o)
+	if err != nil {
+		return c.end(StateStarted, err)
+	}
+	if st := c.stateOf(i); st.stopped || !(st.started || st.inited) {
+		return c.end(StateStarted, nil)
+	}
+
+	// halted runners exited after cancellation are not restarted by
+	// supervision.
+	c.setState(i, func(s *objectState) {
+		s.halted = true
+		s.failErr = nil
+	})
+	err = c.stopObject(ctx, i)
+	c.setState(i, func(s *objectState) {
+		s.started = false
+		s.stopped = true
+	})
+	return c.end(StateStarted, err)
+}
+
+// runnerOf returns position of the runner o given by reference or by name.
+func (c *SimpleContainer) runnerOf(o interface{}) (int, error) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	i := c.indexOf(o)
+	if name, ok := o.(string); ok && i < 0 {
+		for k := range c.objects {
+			if c.nameOf(k) == name {
+				i = k
+				break
+			}
+		}
+		if i < 0 {
+			return -1, fmt.Errorf("sdi: no object named %q", name)
+		}
+	}
+	if i < 0 {
+		return -1, fmt.Errorf("sdi: %T is not containered", o)
+	}
+	if _, ok := c.objects[i].(Runner); !ok {
+		return -1, fmt.Errorf("sdi: %s is not a runner", c.nameOf(i))
+	}
+	return i, nil
+}
@@ -0,0 +1,146 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+// consumer runs until its context is cancelled and reports unhealthy
+// while it's not running.
+type consumer struct {
+	starts  int32
+	running int32
+}
+
+func (c *consumer) Start(ctx context.Context) error {
+	atomic.AddInt32(&c.starts, 1)
+	atomic.StoreInt32(&c.running, 1)
+	defer atomic.StoreInt32(&c.running, 0)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (c *consumer) Health(ctx context.Context) error {
+	if atomic.LoadInt32(&c.running) == 0 {
+		return errors.New("not running")
+	}
+	return nil
+}
+
+func TestStartOneStopOne(t *testing.T) {
+	var jn journal
+	cs := sdi.New()
+	cs.AddNamed("kafka", &journaled{name: "kafka", journal: &jn})
+	cs.AddNamed("api", &journaled{name: "api", journal: &jn})
+
+	ctx := context.Background()
+	if err := cs.StartOne(ctx, "kafka"); !errors.Is(err, sdi.ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState before StartRunners, got %v", err)
+	}
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+	jn.calls = nil
+
+	if err := cs.StopOne(ctx, "kafka"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.StopOne(ctx, "kafka"); err != nil {
+		t.Fatal(err)
+	}
+	if st := cs.Statuses()[0]; !st.Stopped || st.Started {
+		t.Errorf("expected kafka to be reported stopped, got %+v", st)
+	}
+	if err := cs.StartOne(ctx, "kafka"); err != nil {
+		t.Fatal(err)
+	}
+	if st := cs.Statuses()[0]; st.Stopped || !st.Started {
+		t.Errorf("expected kafka to be reported started, got %+v", st)
+	}
+	if want := []string{"stop kafka", "init kafka", "start kafka"}; !reflect.DeepEqual(jn.calls, want) {
+		t.Errorf("expected %v, got %v", want, jn.calls)
+	}
+
+	if err := cs.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStopOneSupervised(t *testing.T) {
+	kafka := &consumer{}
+	cs := sdi.New(sdi.WithSupervision(sdi.RestartPolicy{
+		MaxRestarts:    -1,
+		InitialBackoff: time.Millisecond,
+	}))
+	cs.AddNamed("kafka", kafka)
+	cs.Add(&C{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return cs.Status(kafka).Running })
+
+	if err := cs.StopOne(ctx, kafka); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return !cs.Status(kafka).Running })
+	time.Sleep(10 * time.Millisecond)
+	if n := atomic.LoadInt32(&kafka.starts); n != 1 {
+		t.Errorf("expected runner stopped by StopOne not to be restarted, got %d starts", n)
+	}
+	if res := cs.Health(ctx); len(res) != 0 {
+		t.Errorf("expected runner stopped by StopOne to be skipped by Health, got %v", res)
+	}
+
+	if err := cs.StartOne(ctx, kafka); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool {
+		err, ok := cs.Health(ctx)["kafka"]
+		return ok && err == nil
+	})
+	if err := cs.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStartOneUnknown(t *testing.T) {
+	cs := sdi.New()
+	cs.Add(&journaled{name: "a", journal: &journal{}}, &healthy{})
+	ctx := context.Background()
+	cs.BuildDependencies()
+	cs.InitRequired(ctx)
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cs.StartOne(ctx, "missing"); err == nil {
+		t.Error("expected error for unknown name")
+	}
+	if err := cs.StopOne(ctx, &journaled{}); err == nil {
+		t.Error("expected error for object not containered")
+	}
+	if err := cs.StopOne(ctx, "*sdi_test.healthy"); err == nil {
+		t.Error("expected error for object not being a runner")
+	}
+}
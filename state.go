@@ -0,0 +1,153 @@
+package sdi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// objectState holds lifecycle state of the containered object with
+// the same position in SimpleContainer.objects.
+type objectState struct {
+	inited  bool
+	started bool
+	stopped bool
+	paused  bool
+
+	// Supervision state, see WithSupervision.
+	running  bool
+	restarts int
+	lastErr  error
+
+	// cancel cancels the context passed to Start, canceled reports
+	// it's cancelled by Cancel, restart reports it's cancelled by Restart.
+	cancel   context.CancelFunc
+	canceled bool
+	restart  bool
+
+	// liveErr is the error of the last liveness check, see WithWatchdog.
+	liveErr error
+
+	// halted reports the runner is stopped by StopOne.
+	halted bool
+
+	// failErr is set when circuit breaker stops restarting the runner,
+	// see RestartPolicy.
+	failErr error
+
+	// Durations and errors of the last Init and Start calls.
+	initTime  time.Duration
+	startTime time.Duration
+	initErr   error
+
+	// exited reports Start of the managed runner returned, see Status.
+	exited bool
+
+	// Times of the last lifecycle transitions, see Status.
+	initedAt  time.Time
+	startedAt time.Time
+	exitedAt  time.Time
+	stoppedAt time.Time
+}
+
+// setState calls f with state of the object at position i under write lock.
+func (c *SimpleContainer) setState(i int, f func(*objectState)) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if n := len(c.objects) - len(c.states); n > 0 {
+		c.states = append(c.states, make([]objectState, n)...)
+	}
+	f(&c.states[i])
+}
+
+// stateOf returns state of the object at position i.
+func (c *SimpleContainer) stateOf(i int) objectState {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	if i < len(c.states) {
+		return c.states[i]
+	}
+	return objectState{}
+}
+
+// State is the lifecycle state of the container.
+type State int
+
+const (
+	// StateCreated is the state of a new container, objects can be added.
+	StateCreated State = iota
+
+	// StateBuilt is the state after successful BuildDependencies.
+	StateBuilt
+
+	// StateInitialized is the state after successful InitRequired.
+	StateInitialized
+
+	// StateStarted is the state after successful StartRunners.
+	StateStarted
+
+	// StateStopped is the state after Stop.
+	StateStopped
+
+	// StatePaused is the state after Pause.
+	StatePaused
+)
+
+var stateNames = [...]string{"created", "built", "initialized", "started", "stopped", "paused"}
+
+func (s State) String() string {
+	if s >= 0 && int(s) < len(stateNames) {
+		return stateNames[s]
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// State returns the lifecycle state of the container.
+func (c *SimpleContainer) State() State {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	return c.state
+}
+
+// begin marks lifecycle method as running. It returns error wrapping
+// ErrInvalidState if the container state is not one of allowed or another
+// lifecycle method is running.
+func (c *SimpleContainer) begin(method string, allowed ...State) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.running != "" {
+		return fmt.Errorf("%w: %s called while %s is running", ErrInvalidState, method, c.running)
+	}
+	for _, a := range allowed {
+		if c.state == a {
+			c.running = method
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s called in state %s", ErrInvalidState, method, c.state)
+}
+
+// end marks lifecycle method started by begin as finished and moves
+// the container to state next if err is nil. It returns err.
+func (c *SimpleContainer) end(next State, err error) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.running = ""
+	if err == nil {
+		c.state = next
+	}
+	return err
+}
+
+// setContainerState sets the lifecycle state of the container.
+func (c *SimpleContainer) setContainerState(s State) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.state = s
+}
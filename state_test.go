@@ -0,0 +1,91 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestContainerState(t *testing.T) {
+	ctx := context.Background()
+	cs := sdi.New()
+	var jn journal
+	cs.Add(&journaled{name: "a", journal: &jn})
+	if s := cs.State(); s != sdi.StateCreated {
+		t.Errorf("expected %s, got %s", sdi.StateCreated, s)
+	}
+	if err := cs.InitRequired(ctx); !errors.Is(err, sdi.ErrInvalidState) {
+		t.Errorf("expected ErrInvalidState, got %v", err)
+	}
+
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.StartRunners(ctx); !errors.Is(err, sdi.ErrInvalidState) {
+		t.Errorf("expected ErrInvalidState, got %v", err)
+	}
+
+	for _, step := range []struct {
+		call func() error
+		want sdi.State
+	}{
+		{func() error { return cs.InitRequired(ctx) }, sdi.StateInitialized},
+		{func() error { return cs.StartRunners(ctx) }, sdi.StateStarted},
+		{func() error { return cs.Stop(ctx) }, sdi.StateStopped},
+		{func() error { return cs.InitRequired(ctx) }, sdi.StateInitialized},
+	} {
+		if err := step.call(); err != nil {
+			t.Fatal(err)
+		}
+		if s := cs.State(); s != step.want {
+			t.Errorf("expected %s, got %s", step.want, s)
+		}
+	}
+}
+
+func TestRepeatedLifecycleCalls(t *testing.T) {
+	ctx := context.Background()
+	cs := sdi.New()
+	ic := initCounter{}
+	cs.Add(&ic)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.Build(); !errors.Is(err, sdi.ErrInvalidState) {
+		t.Errorf("expected ErrInvalidState, got %v", err)
+	}
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(ctx); !errors.Is(err, sdi.ErrInvalidState) {
+		t.Errorf("expected ErrInvalidState, got %v", err)
+	}
+	if ic.inits != 1 {
+		t.Errorf("expected single Init, got %d", ic.inits)
+	}
+}
+
+func TestInitRetry(t *testing.T) {
+	ctx := context.Background()
+	cs := sdi.New(sdi.WithContinueOnInitError())
+	f := failingInit{err: errors.New("no database")}
+	dependent := initCounter{}
+	independent := initCounter{Dep: &barrierNode{}}
+	cs.Add(&f, &dependent, &independent)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cs.InitRequired(ctx); err == nil {
+		t.Fatal("expected error")
+	}
+	f.err = nil
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if dependent.inits != 1 || independent.inits != 1 {
+		t.Errorf("expected single Init of each object, got %d and %d", dependent.inits, independent.inits)
+	}
+}
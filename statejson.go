@@ -0,0 +1,146 @@
+package sdi
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// StateSchemaVersion is the version of the StateDocument schema. It's
+// incremented when fields are removed or change meaning, new fields may
+// be added without changing it.
+const StateSchemaVersion = 1
+
+// StateDocument is the JSON document returned by StateJSON:
+//
+//	{
+//	  "version": 1,
+//	  "state": "started",
+//	  "objects": [
+//	    {
+//	      "id": 0, "name": "db", "type": "*sql.DB",
+//	      "initializer": true, "runner": false, "stopper": true, "globalizer": false,
+//	      "inited": true, "inited_at": "2024-05-01T10:00:00Z", "init_seconds": 0.012,
+//	      "started": false, "running": false, "restarts": 0,
+//	      "failed": false, "stopped": false,
+//	      "health": "ok"
+//	    }
+//	  ],
+//	  "edges": [{"from": 1, "to": 0, "field": "DB"}]
+//	}
+type StateDocument struct {
+	// Version is StateSchemaVersion.
+	Version int `json:"version"`
+
+	// State is the lifecycle state of the container.
+	State string `json:"state"`
+
+	// Objects are listed in the order they've been added into container,
+	// ID of each object is its position.
+	Objects []StateObject `json:"objects"`
+
+	// Edges are the wiring of objects, see Graph.
+	Edges []GraphEdge `json:"edges"`
+}
+
+// StateObject describes a containered object in StateDocument: the node
+// of the wiring graph and its lifecycle state, see ObjectStatus.
+// Times are omitted if the transition has not happened, errors are
+// omitted if there are none.
+type StateObject struct {
+	GraphNode
+
+	Inited      bool       `json:"inited"`
+	InitedAt    *time.Time `json:"inited_at,omitempty"`
+	InitSeconds float64    `json:"init_seconds"`
+	InitError   string     `json:"init_error,omitempty"`
+
+	Started      bool       `json:"started"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	StartSeconds float64    `json:"start_seconds"`
+
+	// Supervision state of the runner, see WithSupervision.
+	Running   bool       `json:"running"`
+	Restarts  int        `json:"restarts"`
+	ExitedAt  *time.Time `json:"exited_at,omitempty"`
+	ExitError string     `json:"exit_error,omitempty"`
+
+	Failed    bool   `json:"failed"`
+	FailError string `json:"fail_error,omitempty"`
+
+	Stopped   bool       `json:"stopped"`
+	StoppedAt *time.Time `json:"stopped_at,omitempty"`
+
+	// Health is HealthStatusOK or the error reported by Health, omitted
+	// for objects not checked by Health.
+	Health string `json:"health,omitempty"`
+}
+
+// StateJSON returns state of the container encoded as StateDocument for
+// external tooling, e.g. service catalogs scraping processes. Health of
+// objects is checked by Health with background context.
+func (c *SimpleContainer) StateJSON() ([]byte, error) {
+	return json.Marshal(c.stateDocument(context.Background()))
+}
+
+// stateDocument collects state of the container.
+func (c *SimpleContainer) stateDocument(ctx context.Context) StateDocument {
+	health := c.Health(ctx)
+
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	g := c.graph()
+	doc := StateDocument{
+		Version: StateSchemaVersion,
+		State:   c.state.String(),
+		Objects: make([]StateObject, len(g.Nodes)),
+		Edges:   g.Edges,
+	}
+	for i, n := range g.Nodes {
+		st := c.status(i)
+		so := StateObject{
+			GraphNode: n,
+			Inited:    st.Inited,
+			InitedAt:  timeOrNil(st.InitedAt),
+			InitError: errorString(st.InitError),
+			Started:   st.Started,
+			StartedAt: timeOrNil(st.StartedAt),
+			Running:   st.Running,
+			Failed:    st.Failed,
+			FailError: errorString(st.FailError),
+			Stopped:   st.Stopped,
+			StoppedAt: timeOrNil(st.StoppedAt),
+		}
+		if st.Exited {
+			so.ExitedAt, so.ExitError = timeOrNil(st.ExitedAt), errorString(st.ExitError)
+		}
+		if i < len(c.states) {
+			s := c.states[i]
+			so.InitSeconds, so.StartSeconds = s.initTime.Seconds(), s.startTime.Seconds()
+			so.Restarts = s.restarts
+		}
+		if err, ok := health[n.Name]; ok {
+			so.Health = HealthStatusOK
+			if err != nil {
+				so.Health = err.Error()
+			}
+		}
+		doc.Objects[i] = so
+	}
+	return doc
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
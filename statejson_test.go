@@ -0,0 +1,72 @@
+package sdi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestStateJSON(t *testing.T) {
+	ctx := context.Background()
+	var jn journal
+	cs := sdi.New()
+	b := &B{}
+	cs.AddNamed("db", &healthy{err: errors.New("db is down")})
+	cs.AddNamed("api", &journaled{name: "api", journal: &jn})
+	cs.Add(b, &C{})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := cs.StateJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc sdi.StateDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Version != sdi.StateSchemaVersion || doc.State != "started" {
+		t.Errorf("unexpected document header: version %d, state %q", doc.Version, doc.State)
+	}
+	if len(doc.Objects) != 4 {
+		t.Fatalf("expected 4 objects, got %d", len(doc.Objects))
+	}
+	db, api := doc.Objects[0], doc.Objects[1]
+	if db.Name != "db" || db.Health != "db is down" {
+		t.Errorf("expected db to be reported unhealthy, got %+v", db)
+	}
+	if api.Name != "api" || !api.Inited || !api.Started || api.StartedAt == nil || api.StoppedAt != nil {
+		t.Errorf("expected api to be reported started, got %+v", api)
+	}
+	var injected bool
+	for _, e := range doc.Edges {
+		if e.From == 2 && e.To == 3 && e.Field == "CService" {
+			injected = true
+		}
+	}
+	if !injected {
+		t.Errorf("expected edge of injection of C into B, got %+v", doc.Edges)
+	}
+
+	var raw struct {
+		Objects []map[string]interface{} `json:"objects"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"id", "name", "type", "runner", "inited", "init_seconds", "started", "running", "restarts", "failed", "stopped"} {
+		if _, ok := raw.Objects[1][key]; !ok {
+			t.Errorf("expected key %q in object, got %v", key, raw.Objects[1])
+		}
+	}
+}
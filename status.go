@@ -0,0 +1,95 @@
+package sdi
+
+import "time"
+
+// ObjectStatus describes lifecycle state of a containered object, see
+// Status.
+type ObjectStatus struct {
+	// Name is the object name used in reports.
+	Name string
+
+	// Wired reports the container is built by BuildDependencies.
+	Wired bool
+
+	// Inited reports Init of the object succeeded and the object is not
+	// stopped since. InitError is the error of the last Init.
+	Inited    bool
+	InitError error
+	InitedAt  time.Time
+
+	// Started reports Start of the runner succeeded or, for managed and
+	// blocking runners, has been called, and the runner is not stopped
+	// since.
+	Started   bool
+	StartedAt time.Time
+
+	// Running reports Start of the managed or blocking runner has not
+	// returned yet.
+	Running bool
+
+	// Exited reports Start of the managed or blocking runner returned,
+	// ExitError is the error it returned. The runner may be restarted
+	// according to restart policy, see WithSupervision.
+	Exited    bool
+	ExitError error
+	ExitedAt  time.Time
+
+	// Failed reports circuit breaker stopped restarting the runner, see
+	// RestartPolicy, or the runner failed to start or was skipped by
+	// StartRunners, see WithContinueOnStartError. FailError describes
+	// the failure.
+	Failed    bool
+	FailError error
+
+	// Stopped reports the object is stopped by Stop or StopOne.
+	Stopped   bool
+	StoppedAt time.Time
+}
+
+// Status returns lifecycle state of the containered object o, e.g. to
+// answer whether a queue consumer is actually up. It returns zero
+// ObjectStatus if o is not containered.
+func (c *SimpleContainer) Status(o interface{}) ObjectStatus {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	if i := c.indexOf(o); i >= 0 {
+		return c.status(i)
+	}
+	return ObjectStatus{}
+}
+
+// Statuses returns lifecycle state of containered objects in the order
+// they've been added into container.
+func (c *SimpleContainer) Statuses() []ObjectStatus {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	res := make([]ObjectStatus, len(c.objects))
+	for i := range c.objects {
+		res[i] = c.status(i)
+	}
+	return res
+}
+
+// status returns lifecycle state of the object at position i. The caller
+// must hold read lock.
+func (c *SimpleContainer) status(i int) ObjectStatus {
+	st := ObjectStatus{
+		Name:  c.nameOf(i),
+		Wired: c.state != StateCreated,
+	}
+	if i >= len(c.states) {
+		return st
+	}
+	s := c.states[i]
+	st.Inited, st.InitError, st.InitedAt = s.inited, s.initErr, s.initedAt
+	st.Started, st.StartedAt = s.started, s.startedAt
+	st.Running = s.running
+	if _, ok := c.objects[i].(Runner); ok && s.exited {
+		st.Exited, st.ExitError, st.ExitedAt = true, s.lastErr, s.exitedAt
+	}
+	st.Failed, st.FailError = s.failErr != nil, s.failErr
+	st.Stopped, st.StoppedAt = s.stopped, s.stoppedAt
+	return st
+}
@@ -0,0 +1,73 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+func TestStatus(t *testing.T) {
+	ctx := context.Background()
+	var jn journal
+	db := &journaled{name: "db", journal: &jn}
+	consumer := &markedServer{stoppableServer{stop: make(chan struct{})}}
+
+	cs := sdi.New()
+	cs.AddNamed("db", db)
+	cs.AddNamed("consumer", consumer)
+	if s := cs.Status(db); s.Name != "db" || s.Wired {
+		t.Errorf("expected not wired db, got %+v", s)
+	}
+	if s := cs.Status(&A{}); s.Name != "" {
+		t.Errorf("expected zero status of not containered object, got %+v", s)
+	}
+
+	before := time.Now()
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	s := cs.Status(db)
+	if !s.Wired || !s.Inited || !s.Started || s.Running || s.Exited || s.Stopped || s.InitedAt.Before(before) {
+		t.Errorf("expected started db, got %+v", s)
+	}
+
+	consumer.Stop(ctx)
+	if err := cs.Wait(ctx); err == nil {
+		t.Fatal("expected consumer exit error")
+	}
+	s = cs.Status(consumer)
+	if !s.Exited || s.Running || s.ExitError == nil || s.ExitedAt.Before(s.StartedAt) {
+		t.Errorf("expected exited consumer, got %+v", s)
+	}
+
+	if err := cs.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	statuses := cs.Statuses()
+	if len(statuses) != 2 || !statuses[0].Stopped || statuses[0].Inited || statuses[0].StoppedAt.IsZero() {
+		t.Errorf("expected stopped db, got %+v", statuses)
+	}
+}
+
+func TestStatusInitError(t *testing.T) {
+	errDial := errors.New("dial failed")
+	f := &failingJournaled{journaled: journaled{name: "db", journal: &journal{}}, initErr: errDial}
+	cs := sdi.New()
+	cs.Add(f)
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+
+	if s := cs.Status(f); s.Inited || !errors.Is(s.InitError, errDial) {
+		t.Errorf("expected failed Init, got %+v", s)
+	}
+}
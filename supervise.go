@@ -0,0 +1,274 @@
+package sdi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RestartPolicy configures supervision of runners, see WithSupervision.
+type RestartPolicy struct {
+	// MaxRestarts limits number of restarts of a runner.
+	// Negative value means no limit.
+	MaxRestarts int
+
+	// InitialBackoff is the delay before the first restart. Every next
+	// delay is doubled up to MaxBackoff. Defaults are 100ms and 30s.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// FailureThreshold and FailureWindow configure circuit breaker: if
+	// Start of a runner fails FailureThreshold times within FailureWindow,
+	// the runner is not restarted anymore. It's marked failed in Status,
+	// reported unhealthy by Health, CircuitOpened event is emitted and
+	// the error is sent to Done. Zero FailureThreshold turns the circuit
+	// breaker off, zero FailureWindow counts all failures.
+	FailureThreshold int
+	FailureWindow    time.Duration
+}
+
+const (
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+)
+
+// RunnerError is an error returned by Start of a managed runner.
+type RunnerError struct {
+	// Object is the name of the runner.
+	Object string
+
+	// Err is the error returned by Start.
+	Err error
+}
+
+func (e RunnerError) Error() string {
+	return "sdi: " + e.Object + ".Start: " + e.Err.Error()
+}
+
+func (e RunnerError) Unwrap() error {
+	return e.Err
+}
+
+// Errors returns channel receiving errors returned by Start of managed
+// runners, see WithManagedRunners. The channel is buffered by the number
+// of containered objects, errors are dropped if the buffer is full.
+func (c *SimpleContainer) Errors() <-chan RunnerError {
+	return c.errorsChan()
+}
+
+func (c *SimpleContainer) errorsChan() chan RunnerError {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.errc == nil {
+		c.errc = make(chan RunnerError, len(c.objects))
+	}
+	return c.errc
+}
+
+// Done returns channel receiving the first fatal error of managed runners:
+// error returned by Start of a runner which is not restarted anymore
+// because restart policy set by WithSupervision does not allow it.
+// Errors returned after ctx passed to StartRunners is done or the runner
+// is cancelled are not fatal. The channel is never closed.
+func (c *SimpleContainer) Done() <-chan error {
+	return c.doneChan()
+}
+
+func (c *SimpleContainer) doneChan() chan error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.done == nil {
+		c.done = make(chan error, 1)
+	}
+	return c.done
+}
+
+// Wait blocks until all managed runners exit or ctx is done. It returns
+// errors returned by the last Start of each runner as RunnerError joined
+// by errors.Join. If ctx is done first, Wait does not wait for remaining
+// runners and returns error wrapping ctx.Err().
+func (c *SimpleContainer) Wait(ctx context.Context) error {
+	if err := c.waitRunners(ctx); err != nil {
+		return err
+	}
+
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	var errs []error
+	for i := range c.states {
+		if _, ok := c.objects[i].(Runner); ok && c.states[i].lastErr != nil {
+			errs = append(errs, RunnerError{Object: c.nameOf(i), Err: c.states[i].lastErr})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// waitRunners blocks until all managed runners exit or ctx is done.
+func (c *SimpleContainer) waitRunners(ctx context.Context) error {
+	exited := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(exited)
+	}()
+
+	select {
+	case <-exited:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("sdi: Wait abandoned: %w", ctx.Err())
+	}
+}
+
+// startManaged starts each runner in its own goroutine restarting it
+// according to restart policy.
+func (c *SimpleContainer) startManaged(ctx context.Context, so startOptions) error {
+	errc := c.errorsChan()
+	done := c.doneChan()
+	for _, i := range c.startSequence() {
+		s, ok := c.objects[i].(Runner)
+		if !ok || !c.selected(i, so) {
+			continue
+		}
+		if c.notInited(i) {
+			c.advance(i)
+			continue
+		}
+		if c.gated(i) {
+			c.startGated(ctx, i, s)
+			c.advance(i)
+			continue
+		}
+		c.wg.Add(1)
+		go func(i int) {
+			defer c.wg.Done()
+			c.supervise(ctx, i, s, errc, done)
+		}(i)
+		c.advance(i)
+	}
+	return nil
+}
+
+// supervise calls Start of the runner at position i until it returns nil
+// or ctx is done, restarting it with exponential backoff after errors
+// if restart policy allows. Errors are sent to errc, the error after which
+// the runner is not restarted is sent to done if it's empty.
+func (c *SimpleContainer) supervise(ctx context.Context, i int, r Runner, errc chan<- RunnerError, done chan<- error) {
+	ctx, unlabel := c.labeled(ctx, i)
+	defer unlabel()
+
+	p := c.opts.restartPolicy
+
+	backoff := p.InitialBackoff
+	if backoff <= 0 {
+		backoff = defaultInitialBackoff
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	var failures []time.Time
+	for restarts := 0; ; restarts++ {
+		c.setState(i, func(s *objectState) {
+			s.running = true
+			s.started = true
+			s.stopped = false
+			s.restarts = restarts
+			s.exited = false
+			s.startedAt = time.Now()
+		})
+
+		err := c.startObject(ctx, i, r)
+		c.release(i)
+
+		var restart bool
+		c.setState(i, func(s *objectState) {
+			s.running = false
+			s.lastErr = err
+			s.exited = true
+			s.exitedAt = time.Now()
+			restart, s.restart = s.restart, false
+		})
+		if restart && ctx.Err() == nil {
+			continue
+		}
+		if c.stateOf(i).halted {
+			return
+		}
+
+		re := RunnerError{Object: c.nameOf(i), Err: err}
+		if err != nil {
+			select {
+			case errc <- re:
+			default:
+			}
+		}
+
+		if err == nil || ctx.Err() != nil || c.stateOf(i).canceled {
+			return
+		}
+		if p.FailureThreshold > 0 {
+			failures = recentFailures(append(failures, time.Now()), p.FailureWindow)
+			if len(failures) >= p.FailureThreshold {
+				c.openCircuit(i, len(failures), p.FailureWindow, err, done)
+				return
+			}
+		}
+		if p.MaxRestarts >= 0 && restarts >= p.MaxRestarts {
+			select {
+			case done <- re:
+			default:
+			}
+			return
+		}
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// recentFailures returns times of failures within window before now.
+func recentFailures(failures []time.Time, window time.Duration) []time.Time {
+	if window <= 0 {
+		return failures
+	}
+	since := time.Now().Add(-window)
+	for len(failures) > 0 && failures[0].Before(since) {
+		failures = failures[1:]
+	}
+	return failures
+}
+
+// openCircuit marks the runner at position i failed n times within window
+// as failed, emits CircuitOpened event and sends the error to done.
+func (c *SimpleContainer) openCircuit(i, n int, window time.Duration, err error, done chan<- error) {
+	name := c.nameOf(i)
+	if window > 0 {
+		err = fmt.Errorf("sdi: %s.Start failed %d times within %s, not restarted: %w", name, n, window, err)
+	} else {
+		err = fmt.Errorf("sdi: %s.Start failed %d times, not restarted: %w", name, n, err)
+	}
+	c.setState(i, func(s *objectState) {
+		s.failErr = err
+	})
+	c.logf("%v", err)
+	c.emit(Event{Type: CircuitOpened, Object: name, Err: err})
+	select {
+	case done <- RunnerError{Object: name, Err: err}:
+	default:
+	}
+}
@@ -0,0 +1,251 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+// flaky fails Start several times and then blocks until ctx is done.
+type flaky struct {
+	failures int32
+	calls    int32
+}
+
+func (f *flaky) Start(ctx context.Context) error {
+	if atomic.AddInt32(&f.calls, 1) <= f.failures {
+		return errors.New("connection refused")
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition is not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSupervision(t *testing.T) {
+	cs := sdi.New(sdi.WithSupervision(sdi.RestartPolicy{
+		MaxRestarts:    -1,
+		InitialBackoff: time.Millisecond,
+	}))
+	f := flaky{failures: 3}
+	cs.Add(&f)
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, func() bool {
+		oi := cs.Objects()[0]
+		return oi.Running && oi.Restarts == 3
+	})
+
+	cancel()
+	waitFor(t, func() bool {
+		oi := cs.Objects()[0]
+		return !oi.Running && oi.LastError == nil
+	})
+}
+
+func TestSupervisionMaxRestarts(t *testing.T) {
+	cs := sdi.New(sdi.WithSupervision(sdi.RestartPolicy{
+		MaxRestarts:    2,
+		InitialBackoff: time.Millisecond,
+	}))
+	f := flaky{failures: 10}
+	cs.Add(&f)
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+
+	if err := cs.StartRunners(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, func() bool {
+		oi := cs.Objects()[0]
+		return !oi.Running && oi.Restarts == 2 && oi.LastError != nil
+	})
+	time.Sleep(10 * time.Millisecond)
+	if n := atomic.LoadInt32(&f.calls); n != 3 {
+		t.Errorf("expected 3 Start calls, got %d", n)
+	}
+}
+
+type serving struct {
+	err error
+}
+
+func (s *serving) Start(ctx context.Context) error {
+	if s.err != nil {
+		return s.err
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func TestManagedRunners(t *testing.T) {
+	errBind := errors.New("bind: address already in use")
+	cs := sdi.New(sdi.WithManagedRunners())
+	cs.Add(&serving{}, &serving{err: errBind})
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case re := <-cs.Errors():
+		if !errors.Is(re, errBind) || re.Object != "*sdi_test.serving" {
+			t.Errorf("unexpected runner error %v", re)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected runner error")
+	}
+
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		cs.Wait(context.Background())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after cancellation")
+	}
+}
+
+func TestDone(t *testing.T) {
+	cs := sdi.New(sdi.WithSupervision(sdi.RestartPolicy{
+		MaxRestarts:    1,
+		InitialBackoff: time.Millisecond,
+	}))
+	f := flaky{failures: 10}
+	cs.Add(&serving{}, &f)
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-cs.Done():
+		var re sdi.RunnerError
+		if !errors.As(err, &re) || re.Object != "*sdi_test.flaky" {
+			t.Errorf("unexpected error %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected fatal runner error")
+	}
+	if n := atomic.LoadInt32(&f.calls); n != 2 {
+		t.Errorf("expected 2 Start calls, got %d", n)
+	}
+}
+
+func TestRunStopsOnRunnerFailure(t *testing.T) {
+	errBind := errors.New("bind: address already in use")
+	cl := client{}
+	cs := sdi.New(sdi.WithManagedRunners())
+	cs.Add(&cl, &serving{err: errBind})
+
+	err := cs.Run(context.Background())
+	if !errors.Is(err, errBind) {
+		t.Errorf("unexpected error %v", err)
+	}
+	if !cl.stopped {
+		t.Error("expected client to be stopped")
+	}
+}
+
+func TestWait(t *testing.T) {
+	errBind := errors.New("address already in use")
+	cs := sdi.New(sdi.WithManagedRunners())
+	cs.AddNamed("http", &serving{err: errBind})
+	cs.AddNamed("grpc", &serving{})
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	wctx, wcancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer wcancel()
+	if err := cs.Wait(wctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected %v while grpc runs, got %v", context.DeadlineExceeded, err)
+	}
+
+	cancel()
+	err := cs.Wait(context.Background())
+	var re sdi.RunnerError
+	if !errors.As(err, &re) || re.Object != "http" || !errors.Is(err, errBind) {
+		t.Errorf("expected http runner error, got %v", err)
+	}
+}
+
+func TestSupervisionCircuitBreaker(t *testing.T) {
+	var opened []sdi.Event
+	cs := sdi.New(sdi.WithSupervision(sdi.RestartPolicy{
+		MaxRestarts:      -1,
+		InitialBackoff:   time.Millisecond,
+		FailureThreshold: 3,
+		FailureWindow:    time.Minute,
+	}))
+	cs.Subscribe(func(e sdi.Event) {
+		if e.Type == sdi.CircuitOpened {
+			opened = append(opened, e)
+		}
+	})
+	f := &flaky{failures: 10}
+	cs.AddNamed("consumer", f)
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+	if err := cs.StartRunners(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-cs.Done():
+		if !strings.Contains(err.Error(), "consumer.Start failed 3 times within 1m0s, not restarted") {
+			t.Errorf("unexpected error %q", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected fatal error")
+	}
+	if n := atomic.LoadInt32(&f.calls); n != 3 {
+		t.Errorf("expected 3 Start calls, got %d", n)
+	}
+	if s := cs.Status(f); !s.Failed || s.FailError == nil {
+		t.Errorf("expected failed status, got %+v", s)
+	}
+	if err := cs.Health(context.Background())["consumer"]; err == nil {
+		t.Error("expected consumer unhealthy")
+	}
+	if len(opened) != 1 || opened[0].Object != "consumer" {
+		t.Errorf("expected circuit opened event, got %v", opened)
+	}
+}
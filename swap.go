@@ -0,0 +1,162 @@
+package sdi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Swap replaces containered object old with object new at runtime, after
+// BuildDependencies. Fields, slices, maps and setters the old object was
+// injected into are re-pointed to the new one.
+//
+// Dependencies of the new object are injected and, if it implements
+// Initializer, it's initialized before anything is changed, so a failed
+// Init leaves the container intact. After re-pointing the old object is
+// stopped if it implements Stopper and was initialized or started.
+// Runners are not started by Swap.
+//
+// Fields are re-pointed under the container lock, objects reading them
+// concurrently must synchronize access themselves.
+func (c *SimpleContainer) Swap(ctx context.Context, old, new interface{}) error {
+	if err := containerable(new); err != nil {
+		return err
+	}
+	if err := c.begin("Swap", StateBuilt, StateInitialized, StateStarted, StateStopped); err != nil {
+		return err
+	}
+	state := c.State()
+
+	c.mux.Lock()
+	i := c.indexOf(old)
+	if i < 0 {
+		c.mux.Unlock()
+		return c.end(state, fmt.Errorf("sdi: %T is not containered", old))
+	}
+	nt := reflect.TypeOf(new)
+	for _, d := range c.deps {
+		if d.provider != i || d.explicit {
+			continue
+		}
+		if t := targetType(d.target); !nt.AssignableTo(t) {
+			c.mux.Unlock()
+			return c.end(state, fmt.Errorf("sdi: %T is not assignable to %s.%s of type %s", new, c.nameOf(d.consumer), d.field, t))
+		}
+	}
+	deps, missing, err := c.injectInto(i, new)
+	name := c.nameOf(i)
+	c.mux.Unlock()
+	if err != nil {
+		return c.end(state, err)
+	}
+
+	var inited bool
+	if s, ok := new.(Initializer); ok {
+		err := callWithTimeout(c.objectContext(ctx, i), c.initTimeout(s), name+".Init", recovered(name, PhaseInit, s.Init))
+		if err != nil {
+			return c.end(state, &InitError{Object: name, Err: err})
+		}
+		inited = true
+	}
+
+	c.mux.Lock()
+	// injected values of the old object, decorated ones included,
+	// are replaced by values of the new one.
+	olds := make(map[reflect.Type]interface{})
+	for _, d := range c.deps {
+		if d.provider == i && !d.explicit {
+			t := targetType(d.target)
+			olds[t] = c.provide(t, i).Interface()
+		}
+	}
+	c.objects[i] = new
+	c.undecorate(i)
+	kept := c.deps[:0]
+	for _, d := range c.deps {
+		if d.provider == i && !d.explicit {
+			t := targetType(d.target)
+			repoint(d.target, olds[t], c.provide(t, i))
+		}
+		if d.consumer == i && !d.explicit {
+			continue
+		}
+		kept = append(kept, d)
+	}
+	c.deps = append(kept, deps...)
+	keptMissing := c.missing[:0]
+	for _, m := range c.missing {
+		if m.consumer != i {
+			keptMissing = append(keptMissing, m)
+		}
+	}
+	c.missing = append(keptMissing, missing...)
+
+	var prev objectState
+	if i < len(c.states) {
+		prev = c.states[i]
+		c.states[i] = objectState{inited: inited}
+	}
+	c.mux.Unlock()
+
+	if s, ok := old.(Stopper); ok && (prev.inited || prev.started) && !prev.stopped {
+		err = s.Stop(ctx)
+	}
+	if prev.cancel != nil {
+		prev.cancel()
+	}
+	return c.end(state, err)
+}
+
+// injectInto injects dependencies into object o as if it's at position i
+// and returns recorded dependencies and missing fields without storing
+// them. The caller must hold write lock.
+func (c *SimpleContainer) injectInto(i int, o interface{}) ([]dependency, []dependency, error) {
+	saved, nd, nm := c.objects[i], len(c.deps), len(c.missing)
+	c.objects[i] = o
+	c.cache = nil
+	defer func() {
+		c.objects[i] = saved
+		c.cache = nil
+		c.deps = c.deps[:nd]
+		c.missing = c.missing[:nm]
+	}()
+
+	err := c.inject(i)
+	deps := append([]dependency(nil), c.deps[nd:]...)
+	missing := append([]dependency(nil), c.missing[nm:]...)
+	return deps, missing, err
+}
+
+// targetType returns type of objects assignable to the dependency target.
+func targetType(target reflect.Value) reflect.Type {
+	switch {
+	case !target.CanSet():
+		// setter method.
+		return target.Type().In(0)
+	case target.Kind() == reflect.Slice, target.Kind() == reflect.Map:
+		return target.Type().Elem()
+	}
+	return target.Type()
+}
+
+// repoint replaces object old with nv in the dependency target.
+func repoint(target reflect.Value, old interface{}, nv reflect.Value) {
+	switch {
+	case !target.CanSet():
+		target.Call([]reflect.Value{nv})
+	case target.Kind() == reflect.Slice:
+		for k := 0; k < target.Len(); k++ {
+			if e := target.Index(k); e.Interface() == old {
+				e.Set(nv)
+			}
+		}
+	case target.Kind() == reflect.Map:
+		for _, key := range target.MapKeys() {
+			if target.MapIndex(key).Interface() == old {
+				target.SetMapIndex(key, nv)
+			}
+		}
+	default:
+		target.Set(nv)
+	}
+}
@@ -0,0 +1,89 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type Client interface {
+	Call() string
+}
+
+type client struct {
+	name    string
+	inited  bool
+	stopped bool
+	initErr error
+}
+
+func (cl *client) Init(ctx context.Context) error {
+	cl.inited = true
+	return cl.initErr
+}
+
+func (cl *client) Stop(ctx context.Context) error {
+	cl.stopped = true
+	return nil
+}
+
+func (cl *client) Call() string {
+	return cl.name
+}
+
+type gateway struct {
+	Client  Client
+	Clients []Client
+	setter  Client
+}
+
+func (g *gateway) Global() {}
+
+func (g *gateway) SetBackup(cl Client) {
+	g.setter = cl
+}
+
+func TestSwap(t *testing.T) {
+	ctx := context.Background()
+	cs := sdi.New()
+	primary := client{name: "real"}
+	g := gateway{}
+	cs.Add(&primary, &g)
+	cs.BuildDependencies()
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	stub := client{name: "stub"}
+	if err := cs.Swap(ctx, &primary, &stub); err != nil {
+		t.Fatal(err)
+	}
+	if g.Client != &stub || g.Clients[0] != &stub || g.setter != &stub {
+		t.Error("expected all injections to be re-pointed")
+	}
+	if !stub.inited || !primary.stopped {
+		t.Error("expected new object to be initialized and old one stopped")
+	}
+	if o := sdi.MustResolve[Client](cs); o != &stub {
+		t.Error("expected new object to be containered")
+	}
+}
+
+func TestSwapInitFailure(t *testing.T) {
+	ctx := context.Background()
+	cs := sdi.New()
+	primary := client{name: "real"}
+	g := gateway{}
+	cs.Add(&primary, &g)
+	cs.BuildDependencies()
+
+	errInit := errors.New("no backend")
+	if err := cs.Swap(ctx, &primary, &client{initErr: errInit}); !errors.Is(err, errInit) {
+		t.Errorf("expected %v, got %v", errInit, err)
+	}
+	if g.Client != &primary || primary.stopped {
+		t.Error("expected container to be intact")
+	}
+}
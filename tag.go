@@ -0,0 +1,98 @@
+package sdi
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tagName is the struct tag key used by the container.
+const tagName = "sdi"
+
+// parseTag parses comma separated list of key=value pairs. A key without
+// value has empty value.
+func parseTag(tag string) map[string]string {
+	res := make(map[string]string)
+	for _, kv := range strings.Split(tag, ",") {
+		kv = strings.TrimSpace(kv)
+		if kv == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(kv, "=")
+		res[k] = v
+	}
+	return res
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setFromEnv assigns field fs the value of environment variable env.
+// If the variable is not set, the value of tag option default is used
+// if the field is zero. If the tag has required option and
+// the variable is not set, error is returned.
+func setFromEnv(fs reflect.Value, env string, opts map[string]string) error {
+	s, ok := os.LookupEnv(env)
+	if !ok {
+		if _, required := opts["required"]; required {
+			return fmt.Errorf("environment variable %s is not set", env)
+		}
+		def, ok := opts["default"]
+		if !ok || !fs.IsZero() {
+			return nil
+		}
+		s = def
+	}
+
+	if err := setString(fs, s); err != nil {
+		return fmt.Errorf("environment variable %s: %w", env, err)
+	}
+	return nil
+}
+
+// setString parses s into value of the field fs type. Supported types are
+// string, bool, integers, floats and time.Duration.
+func setString(fs reflect.Value, s string) error {
+	if fs.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		fs.SetInt(int64(d))
+		return nil
+	}
+
+	switch fs.Kind() {
+	case reflect.String:
+		fs.SetString(s)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		fs.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(s, 0, fs.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fs.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(s, 0, fs.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fs.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(s, fs.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fs.SetFloat(v)
+	default:
+		return fmt.Errorf("unsupported field type %s", fs.Type())
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package sdi_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+type httpConfig struct {
+	Port    int           `sdi:"env=SDI_TEST_PORT,default=8080"`
+	Host    string        `sdi:"env=SDI_TEST_HOST,default=localhost"`
+	Debug   bool          `sdi:"env=SDI_TEST_DEBUG"`
+	Timeout time.Duration `sdi:"env=SDI_TEST_TIMEOUT,default=5s"`
+	Ratio   float64       `sdi:"env=SDI_TEST_RATIO"`
+}
+
+func (hc *httpConfig) Global() {}
+
+func TestEnvInjection(t *testing.T) {
+	t.Setenv("SDI_TEST_PORT", "9090")
+	t.Setenv("SDI_TEST_DEBUG", "true")
+	t.Setenv("SDI_TEST_RATIO", "0.5")
+
+	cfg := httpConfig{Host: "example.com"}
+	cs := sdi.New()
+	cs.Add(&cfg)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := httpConfig{Port: 9090, Host: "example.com", Debug: true, Timeout: 5 * time.Second, Ratio: 0.5}
+	if cfg != expected {
+		t.Errorf("expected %+v, got %+v", expected, cfg)
+	}
+}
+
+type dbConfig struct {
+	URL  string `sdi:"env=SDI_TEST_DB_URL,required"`
+	Pool int    `sdi:"env=SDI_TEST_DB_POOL"`
+}
+
+func (dc *dbConfig) Global() {}
+
+func TestEnvInjectionErrors(t *testing.T) {
+	t.Setenv("SDI_TEST_DB_POOL", "many")
+
+	cs := sdi.New()
+	cs.Add(&dbConfig{})
+	err := cs.Build()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, s := range []string{"SDI_TEST_DB_URL is not set", "*sdi_test.dbConfig.Pool"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("expected error to contain %q, got %q", s, err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package sdi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// InitTimeouter is the interface that wraps the basic InitTimeout method.
+//
+// InitTimeout returns maximum duration of Init of the object. It overrides
+// the container default set by WithInitTimeout. Zero means no timeout.
+type InitTimeouter interface {
+	InitTimeout() time.Duration
+}
+
+// StartTimeouter is the interface that wraps the basic StartTimeout method.
+//
+// StartTimeout returns maximum duration of Start of the runner. It
+// overrides the container default set by WithStartTimeout. Zero means
+// no timeout.
+type StartTimeouter interface {
+	StartTimeout() time.Duration
+}
+
+// initTimeout returns Init timeout of the object o.
+func (c *SimpleContainer) initTimeout(o interface{}) time.Duration {
+	if it, ok := o.(InitTimeouter); ok {
+		return it.InitTimeout()
+	}
+	return c.opts.initTimeout
+}
+
+// startTimeout returns Start timeout of the runner o.
+func (c *SimpleContainer) startTimeout(o interface{}) time.Duration {
+	if st, ok := o.(StartTimeouter); ok {
+		return st.StartTimeout()
+	}
+	return c.opts.startTimeout
+}
+
+// callWithin calls f with ctx and waits for it at most d. Unlike
+// callWithTimeout, ctx is not cancelled after f returns, so f may keep
+// using it. If f does not return in time, callWithin does not wait for it
+// and returns error wrapping context.DeadlineExceeded. Zero d means no
+// timeout.
+func callWithin(ctx context.Context, d time.Duration, call string, f func(context.Context) error) error {
+	if d <= 0 {
+		return f(ctx)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- f(ctx)
+	}()
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-t.C:
+		return fmt.Errorf("sdi: %s exceeded %s: %w", call, d, context.DeadlineExceeded)
+	}
+}
+
+// callUntilDone calls f with ctx. If ctx is done before f returns,
+// callUntilDone does not wait for it and returns error wrapping ctx.Err().
+func callUntilDone(ctx context.Context, call string, f func(context.Context) error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("sdi: %s abandoned: %w", call, ctx.Err())
+	}
+	if ctx.Done() == nil {
+		return f(ctx)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- f(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("sdi: %s abandoned: %w", call, ctx.Err())
+	}
+}
+
+// callWithTimeout calls f with context cancelled after d. If f does not
+// return in time, callWithTimeout does not wait for it and returns error
+// wrapping context.DeadlineExceeded. Zero d means no timeout.
+func callWithTimeout(ctx context.Context, d time.Duration, call string, f func(context.Context) error) error {
+	if d <= 0 {
+		return f(ctx)
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- f(tctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-tctx.Done():
+		err = tctx.Err()
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		return fmt.Errorf("sdi: %s exceeded %s: %w", call, d, context.DeadlineExceeded)
+	}
+	return err
+}
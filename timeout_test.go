@@ -0,0 +1,110 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+// hanging ignores context and never finishes Init.
+type hanging struct{}
+
+func (h *hanging) Init(ctx context.Context) error {
+	select {}
+}
+
+type slowInit struct {
+	timeout time.Duration
+}
+
+func (s *slowInit) Init(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (s *slowInit) InitTimeout() time.Duration {
+	return s.timeout
+}
+
+func TestInitTimeout(t *testing.T) {
+	cs := sdi.New(sdi.WithInitTimeout(10 * time.Millisecond))
+	cs.Add(&hanging{})
+	cs.BuildDependencies()
+
+	err := cs.InitRequired(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "*sdi_test.hanging.Init exceeded 10ms") {
+		t.Errorf("unexpected error message %q", err)
+	}
+}
+
+func TestInitTimeouter(t *testing.T) {
+	cs := sdi.New(sdi.WithInitTimeout(time.Hour))
+	cs.Add(&slowInit{timeout: 5 * time.Millisecond})
+	cs.BuildDependencies()
+
+	err := cs.InitRequired(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "exceeded 5ms") {
+		t.Errorf("expected per-object timeout error, got %v", err)
+	}
+}
+
+// blockingServer serves synchronously in Start, as if it forgot to run
+// ListenAndServe in a goroutine.
+type blockingServer struct {
+	timeout time.Duration
+}
+
+func (bs *blockingServer) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (bs *blockingServer) StartTimeout() time.Duration {
+	return bs.timeout
+}
+
+// background keeps the context passed to Start.
+type background struct {
+	ctx context.Context
+}
+
+func (b *background) Start(ctx context.Context) error {
+	b.ctx = ctx
+	return nil
+}
+
+func TestStartTimeout(t *testing.T) {
+	b := background{}
+	cs := sdi.New(sdi.WithStartTimeout(time.Hour))
+	cs.AddNamed("api", &blockingServer{timeout: 5 * time.Millisecond})
+	cs.Add(&b)
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+
+	err := cs.StartRunners(context.Background())
+	var se *sdi.StartError
+	if !errors.As(err, &se) || se.Object != "api" || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected start timeout of api, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "api.Start exceeded 5ms") {
+		t.Errorf("unexpected error message %q", err)
+	}
+
+	cs = sdi.New(sdi.WithStartTimeout(time.Hour))
+	cs.Add(&b)
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+	if err := cs.StartRunners(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if b.ctx.Err() != nil {
+		t.Error("context passed to Start must not be cancelled after Start returns")
+	}
+}
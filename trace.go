@@ -0,0 +1,58 @@
+package sdi
+
+import (
+	"os"
+	"reflect"
+)
+
+// TraceEnv is the environment variable turning wiring trace on for all
+// containers, like WithWiringTrace, if it's not empty.
+const TraceEnv = "SDI_TRACE"
+
+// WithWiringTrace makes BuildDependencies log every wiring decision: each
+// field considered, each containered object evaluated as a candidate and
+// why it's rejected, e.g. not assignable or self-reference, fields skipped
+// because they are set already, and what is finally injected. The trace
+// is written to the logger set by WithLogger or to the standard logger.
+// Setting environment variable SDI_TRACE turns the trace on as well.
+func WithWiringTrace() Option {
+	return func(o *options) {
+		o.trace = true
+	}
+}
+
+// traceFromEnv turns wiring trace on if environment variable TraceEnv is
+// set.
+func traceFromEnv(o *options) {
+	if os.Getenv(TraceEnv) != "" {
+		o.trace = true
+	}
+}
+
+// tracef writes wiring trace record.
+func (c *SimpleContainer) tracef(format string, v ...interface{}) {
+	if !c.opts.trace {
+		return
+	}
+	c.printf("sdi: trace: "+format, v...)
+}
+
+// traceCandidates writes why containered objects are rejected as
+// candidates for the field of type ft of the object at position pos.
+// The caller must hold write lock.
+func (c *SimpleContainer) traceCandidates(pos int, ft reflect.Type, field string) {
+	if !c.opts.trace {
+		return
+	}
+	c.tracef("%s.%s: considered, type %s", c.nameOf(pos), field, ft)
+	for i := range c.objects {
+		switch {
+		case i == pos:
+			c.tracef("%s.%s: candidate %s rejected: self-reference", c.nameOf(pos), field, c.nameOf(i))
+		case !c.isAssignable(reflect.TypeOf(c.objects[i]), ft):
+			c.tracef("%s.%s: candidate %s rejected: %T is not assignable", c.nameOf(pos), field, c.nameOf(i), c.objects[i])
+		default:
+			c.tracef("%s.%s: candidate %s accepted", c.nameOf(pos), field, c.nameOf(i))
+		}
+	}
+}
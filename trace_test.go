@@ -0,0 +1,47 @@
+package sdi_test
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestWithWiringTrace(t *testing.T) {
+	var buf bytes.Buffer
+	cs := sdi.New(sdi.WithWiringTrace(), sdi.WithLogger(log.New(&buf, "", 0)))
+	cs.AddNamed("a", &A{})
+	cs.AddNamed("b", &B{CService: &C{}})
+	cs.AddNamed("e", &E{})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{
+		"sdi: trace: b.AService: considered, type sdi_test.AI",
+		"sdi: trace: b.AService: candidate a accepted",
+		"sdi: trace: b.AService: candidate b rejected: self-reference",
+		"sdi: trace: b.AService: candidate e rejected: *sdi_test.E is not assignable",
+		"sdi: trace: b.AService: injected a",
+		"sdi: trace: b.CService: already set, skipped",
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("expected %q in trace:\n%s", s, buf.String())
+		}
+	}
+}
+
+func TestWiringTraceEnv(t *testing.T) {
+	var buf bytes.Buffer
+	t.Setenv(sdi.TraceEnv, "1")
+	cs := sdi.New(sdi.WithLogger(log.New(&buf, "", 0)))
+	cs.Add(&A{}, &B{})
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "sdi: trace:") {
+		t.Errorf("expected trace, got %q", buf.String())
+	}
+}
@@ -0,0 +1,23 @@
+package sdi
+
+import "context"
+
+// Lifecycle phases passed to Tracer. PhaseConstruct is used only by
+// PanicError of constructors registered by Provide.
+const (
+	PhaseInit      = "Init"
+	PhaseStart     = "Start"
+	PhaseConstruct = "Construct"
+)
+
+// Tracer is the interface that wraps the basic StartSpan method.
+//
+// StartSpan is invocated before Init or Start of the containered object
+// named object. Phase is PhaseInit or PhaseStart. Returned context is
+// passed to Init or Start, returned function is called after it with
+// the returned error.
+//
+// Package github.com/axkit/sdi/sdiotel provides OpenTelemetry implementation.
+type Tracer interface {
+	StartSpan(ctx context.Context, phase, object string) (context.Context, func(error))
+}
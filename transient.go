@@ -0,0 +1,87 @@
+package sdi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// transient is a factory registered by AddTransient.
+type transient struct {
+	factory reflect.Value
+	typ     reflect.Type
+}
+
+// AddTransient registers factories of transient objects. A factory is
+// a function without parameters returning the object, e.g.
+// func() *Parser. Unlike containered objects, which are singletons, every
+// field injected by BuildDependencies and every Resolve call gets a new
+// object created by the factory.
+//
+// Nil exported interface, pointer and function fields of a new object
+// pointing to a struct are set to objects resolved the same way as by
+// Resolve, other transient objects included. Transient objects are neither
+// initialized nor started or stopped by the container.
+//
+// AddTransient panics if a factory has wrong signature or
+// BuildDependencies has been called already.
+func (c *SimpleContainer) AddTransient(factory ...interface{}) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for _, f := range factory {
+		v := reflect.ValueOf(f)
+		if v.Kind() != reflect.Func || v.IsNil() || v.Type().NumIn() != 0 || v.Type().NumOut() != 1 {
+			panic(fmt.Sprintf("sdi: %T is not a factory function", f))
+		}
+		if c.built {
+			panic(fmt.Sprintf("sdi: %T added after BuildDependencies", f))
+		}
+		c.transients = append(c.transients, transient{factory: v, typ: v.Type().Out(0)})
+	}
+}
+
+// newTransient returns new object created by the last registered factory
+// of objects assignable to type t. Types of transient objects being
+// created are listed in creating to break cyclic dependencies.
+func (c *SimpleContainer) newTransient(t reflect.Type, creating []reflect.Type) (interface{}, bool) {
+	for k := len(c.transients) - 1; k >= 0; k-- {
+		tr := c.transients[k]
+		if !tr.typ.AssignableTo(t) {
+			continue
+		}
+		for _, ct := range creating {
+			if ct == tr.typ {
+				return nil, false
+			}
+		}
+
+		v := tr.factory.Call(nil)[0]
+		if !v.IsValid() || (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+			return nil, false
+		}
+		if v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Struct {
+			c.wireTransient(v.Elem(), append(creating, tr.typ))
+		}
+		return v.Interface(), true
+	}
+	return nil, false
+}
+
+// wireTransient sets nil exported interface, pointer and function fields
+// of the struct sv.
+func (c *SimpleContainer) wireTransient(sv reflect.Value, creating []reflect.Type) {
+	for f := 0; f < sv.NumField(); f++ {
+		fs := sv.Field(f)
+		switch fs.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Func:
+		default:
+			continue
+		}
+		if !fs.CanSet() || !fs.IsNil() {
+			continue
+		}
+		if o, err := c.lookup(fs.Type(), creating); err == nil {
+			fs.Set(reflect.ValueOf(o))
+		}
+	}
+}
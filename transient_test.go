@@ -0,0 +1,59 @@
+package sdi_test
+
+import (
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type Parser interface {
+	Parse(string) int
+}
+
+type parser struct {
+	Dict  Lookuper
+	calls int
+}
+
+func (p *parser) Parse(s string) int {
+	p.calls++
+	return p.calls
+}
+
+type importer struct {
+	First  Parser
+	Second Parser
+}
+
+func (im *importer) Global() {}
+
+func TestAddTransient(t *testing.T) {
+	im := importer{}
+	dict := dictionary{}
+	cs := sdi.New()
+	cs.Add(&im, dict)
+	cs.AddTransient(func() *parser { return &parser{} })
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	if im.First == nil || im.Second == nil || im.First == im.Second {
+		t.Fatal("expected distinct parsers to be injected")
+	}
+	if im.First.(*parser).Dict == nil {
+		t.Error("expected transient object to be wired")
+	}
+
+	p1 := sdi.MustResolve[Parser](cs)
+	p2 := sdi.MustResolve[Parser](cs)
+	if p1 == p2 || p1.Parse("") != 1 || p2.Parse("") != 1 {
+		t.Error("expected Resolve to return new objects")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	sdi.New().AddTransient(func(string) *parser { return nil })
+}
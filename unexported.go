@@ -0,0 +1,33 @@
+package sdi
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// InjectUnexported makes BuildDependencies inject into unexported
+// interface fields of objects too, instead of exposing them by Privater:
+//
+//	type service struct {
+//		store Store
+//	}
+//
+//	c.Add(&service{}, sdi.InjectUnexported())
+//
+// Unexported fields are set through package unsafe, bypassing the rules
+// of the language which protect them. Tags, policies, Swap and AddLate
+// apply to them the same way as to exported fields.
+func InjectUnexported() AddOption {
+	return func(r *registration) {
+		r.unexported = true
+	}
+}
+
+// unexportedField returns settable value of the unexported interface
+// field fs of the object at position pos added with InjectUnexported.
+func (c *SimpleContainer) unexportedField(pos int, sf reflect.StructField, fs reflect.Value) (reflect.Value, bool) {
+	if !c.regs[pos].unexported || sf.IsExported() || sf.Anonymous || sf.Type.Kind() != reflect.Interface || !fs.CanAddr() {
+		return reflect.Value{}, false
+	}
+	return reflect.NewAt(sf.Type, unsafe.Pointer(fs.UnsafeAddr())).Elem(), true
+}
@@ -0,0 +1,70 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+type hiddenDeps struct {
+	age    AI
+	gender CI `sdi:"unresolved=strict"`
+	count  int
+}
+
+func (h *hiddenDeps) Init(ctx context.Context) error { return nil }
+
+func TestInjectUnexported(t *testing.T) {
+	a, h, plain := &A{}, &hiddenDeps{}, &hiddenDeps{}
+	cs := sdi.New()
+	cs.Add(h, sdi.InjectUnexported())
+	cs.Add(a, &C{}, plain)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if h.age != a || h.gender == nil {
+		t.Errorf("expected unexported fields to be injected, got %+v", h)
+	}
+	if plain.age != nil || plain.gender != nil {
+		t.Error("expected unexported fields of object added without option not to be injected")
+	}
+
+	a2 := &A{}
+	if err := cs.Swap(context.Background(), a, a2); err != nil {
+		t.Fatal(err)
+	}
+	if h.age != a2 {
+		t.Errorf("expected swapped object to be injected into unexported field, got %v", h.age)
+	}
+}
+
+func TestInjectUnexportedPolicy(t *testing.T) {
+	cs := sdi.New()
+	cs.Add(&hiddenDeps{}, sdi.InjectUnexported())
+	if err := cs.Build(); !errors.Is(err, sdi.ErrUnresolvedDependency) {
+		t.Errorf("expected %v, got %v", sdi.ErrUnresolvedDependency, err)
+	}
+}
+
+func TestInjectUnexportedAddLate(t *testing.T) {
+	h, plain := &hiddenDeps{}, &hiddenDeps{}
+	cs := sdi.New()
+	cs.Add(h, sdi.InjectUnexported())
+	cs.Add(&C{}, plain)
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &A{}
+	if err := cs.AddLate(context.Background(), a); err != nil {
+		t.Fatal(err)
+	}
+	if h.age != a {
+		t.Errorf("expected object added late to be injected into unexported field, got %v", h.age)
+	}
+	if plain.age != nil {
+		t.Error("expected unexported field of object added without option not to be filled")
+	}
+}
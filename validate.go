@@ -0,0 +1,38 @@
+package sdi
+
+import "fmt"
+
+// Validate performs wiring analysis of containered objects without
+// calling Init or Start and without modifying the objects. It returns
+// errors Build would return in strict mode, see WithStrictMode:
+// unresolved fields not tagged with other policy, ambiguities, cyclic
+// DependsOn declarations and AfterInject failures. If the container is created
+// with WithParallelInit, cyclic injections are reported as well.
+// Objects not implementing lifecycle interfaces are rejected by Add.
+//
+// Wiring is performed on a clone of the container, see Clone, therefore
+// Validate must be called before BuildDependencies, otherwise error
+// wrapping ErrInvalidState is returned.
+func (c *SimpleContainer) Validate() error {
+	c.mux.RLock()
+	built := c.built
+	c.mux.RUnlock()
+	if built {
+		return fmt.Errorf("%w: Validate called after BuildDependencies", ErrInvalidState)
+	}
+
+	clone := c.Clone()
+	clone.opts.unresolvedPolicy = FailOnUnresolved
+	clone.opts.logger = nil
+	clone.opts.slog = nil
+	clone.subs.fns = nil
+	if err := clone.Build(); err != nil {
+		return err
+	}
+	if c.opts.parallelInit {
+		if _, err := clone.levels(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
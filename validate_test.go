@@ -0,0 +1,51 @@
+package sdi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/axkit/sdi"
+)
+
+func TestValidate(t *testing.T) {
+	tr := translator{}
+	cs := sdi.New()
+	cs.Add(dictionary{}, &tr)
+	if err := cs.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Dict != nil {
+		t.Error("Validate must not inject dependencies")
+	}
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Dict == nil {
+		t.Error("expected BuildDependencies to inject dependencies after Validate")
+	}
+	if err := cs.Validate(); !errors.Is(err, sdi.ErrInvalidState) {
+		t.Errorf("expected ErrInvalidState, got %v", err)
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	cs := sdi.New()
+	cs.Add(&translator{})
+	if err := cs.Validate(); !errors.Is(err, sdi.ErrUnresolvedDependency) {
+		t.Errorf("expected ErrUnresolvedDependency, got %v", err)
+	}
+
+	cs = sdi.New()
+	cs.Add(dictionary{}, dictionary{}, &translator{})
+	if err := cs.Validate(); !errors.Is(err, sdi.ErrAmbiguous) {
+		t.Errorf("expected ErrAmbiguous, got %v", err)
+	}
+
+	a := &dependent{}
+	cs = sdi.New()
+	cs.Add(a, &dependent{deps: []interface{}{a}})
+	a.deps = []interface{}{&dependent{}}
+	if err := cs.Validate(); err == nil {
+		t.Error("expected error for dependency on not containered object")
+	}
+}
@@ -0,0 +1,126 @@
+package sdi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+)
+
+// Precondition is an external resource, e.g. a database or a message
+// broker, which must be available before Init of objects waiting for it.
+type Precondition struct {
+	// Name describes the resource in logs and errors.
+	Name string
+
+	// Check returns nil if the resource is available.
+	Check func(ctx context.Context) error
+}
+
+// Preconditioner is the interface that wraps the basic Preconditions
+// method.
+//
+// Preconditions returns resources which must be available before Init of
+// the object, see WaitFor.
+type Preconditioner interface {
+	Preconditions() []Precondition
+}
+
+// WaitFor makes InitRequired wait until preconditions p are satisfied
+// before calling Init of added objects:
+//
+//	c.Add(repo, sdi.WaitFor(sdi.TCPReachable("db:5432")))
+//
+// Preconditions are checked one after another and retried with backoff
+// according to the policy set by WithWaitPolicy, by default until the
+// context passed to InitRequired is done. Preconditions returned by
+// Preconditioner are checked after them.
+func WaitFor(p ...Precondition) AddOption {
+	return func(r *registration) {
+		r.preconditions = append(r.preconditions[:len(r.preconditions):len(r.preconditions)], p...)
+	}
+}
+
+// WithWaitPolicy sets retry policy of preconditions, see WaitFor.
+func WithWaitPolicy(p RetryPolicy) Option {
+	return func(o *options) {
+		o.waitPolicy = &p
+	}
+}
+
+// TCPReachable returns precondition satisfied when TCP connection to
+// address addr can be established.
+func TCPReachable(addr string) Precondition {
+	return Precondition{
+		Name: "tcp " + addr,
+		Check: func(ctx context.Context) error {
+			var d net.Dialer
+			conn, err := d.DialContext(ctx, "tcp", addr)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		},
+	}
+}
+
+// HTTPOK returns precondition satisfied when GET request of url returns
+// status 200.
+func HTTPOK(url string) Precondition {
+	return Precondition{
+		Name: "http " + url,
+		Check: func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return err
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("status %s", resp.Status)
+			}
+			return nil
+		},
+	}
+}
+
+// FileExists returns precondition satisfied when file path exists, e.g.
+// a secret or a socket mounted by another container.
+func FileExists(path string) Precondition {
+	return Precondition{
+		Name: "file " + path,
+		Check: func(context.Context) error {
+			_, err := os.Stat(path)
+			return err
+		},
+	}
+}
+
+// waitPreconditions waits until preconditions of the object o at position
+// i are satisfied.
+func (c *SimpleContainer) waitPreconditions(ctx context.Context, i int, o interface{}) error {
+	pcs := c.regs[i].preconditions
+	if pr, ok := o.(Preconditioner); ok {
+		pcs = append(pcs[:len(pcs):len(pcs)], pr.Preconditions()...)
+	}
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	p := RetryPolicy{MaxRetries: -1}
+	if c.opts.waitPolicy != nil {
+		p = *c.opts.waitPolicy
+	}
+	name := c.nameOf(i)
+	for _, pc := range pcs {
+		if err := c.callWithRetry(ctx, p, name+" precondition "+pc.Name, pc.Check); err != nil {
+			return fmt.Errorf("precondition %s: %w", pc.Name, errors.Join(err, ctx.Err()))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+type broker struct {
+	journaled
+	addr string
+}
+
+func (b *broker) Preconditions() []sdi.Precondition {
+	return []sdi.Precondition{sdi.TCPReachable(b.addr)}
+}
+
+func TestWaitFor(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	secret := filepath.Join(t.TempDir(), "secret")
+	time.AfterFunc(20*time.Millisecond, func() {
+		os.WriteFile(secret, nil, 0o600)
+	})
+
+	var jn journal
+	cs := sdi.New(sdi.WithWaitPolicy(sdi.RetryPolicy{MaxRetries: -1, InitialBackoff: 5 * time.Millisecond}))
+	cs.Add(&journaled{name: "repo", journal: &jn}, sdi.WaitFor(sdi.FileExists(secret), sdi.HTTPOK(srv.URL)))
+	cs.Add(&broker{journaled: journaled{name: "broker", journal: &jn}, addr: ln.Addr().String()})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(jn.calls) != 2 {
+		t.Errorf("expected both objects initialized, got %v", jn.calls)
+	}
+}
+
+func TestWaitForUnavailable(t *testing.T) {
+	var jn journal
+	cs := sdi.New(sdi.WithWaitPolicy(sdi.RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond}))
+	missing := filepath.Join(t.TempDir(), "missing")
+	cs.Add(&journaled{name: "repo", journal: &jn}, sdi.WaitFor(sdi.FileExists(missing)))
+
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	err := cs.InitRequired(context.Background())
+	var ie *sdi.InitError
+	if !errors.As(err, &ie) || !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected InitError wrapping os.ErrNotExist, got %v", err)
+	}
+	if len(jn.calls) != 0 {
+		t.Errorf("expected Init not to be called, got %v", jn.calls)
+	}
+}
+
+func TestWaitForContextDone(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	var jn journal
+	cs := sdi.New()
+	cs.Add(&journaled{name: "api", journal: &jn}, sdi.WaitFor(sdi.HTTPOK(srv.URL)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := cs.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.InitRequired(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package sdi
+
+import (
+	"context"
+	"time"
+)
+
+// LivenessChecker is the interface that wraps the basic Liveness method.
+//
+// Liveness reports whether the started runner is still doing its work,
+// e.g. the loop spawned by Start is alive. A nil error means alive.
+type LivenessChecker interface {
+	Liveness(ctx context.Context) error
+}
+
+// WatchdogPolicy configures the watchdog, see WithWatchdog.
+type WatchdogPolicy struct {
+	// Interval between liveness checks.
+	Interval time.Duration
+
+	// Timeout of a Liveness call, not responding in time is a failure.
+	// Zero means Interval.
+	Timeout time.Duration
+
+	// Restart makes the watchdog restart supervised runners failed
+	// liveness check, see Restart.
+	Restart bool
+}
+
+// WithWatchdog makes StartRunners start a watchdog calling Liveness of
+// started runners implementing LivenessChecker every p.Interval until
+// Stop. A failed check emits LivenessFailed event and makes Health report
+// the object unhealthy until a successful check. LivenessPassed event is
+// emitted after each round of checks none of which failed.
+func WithWatchdog(p WatchdogPolicy) Option {
+	return func(o *options) {
+		o.watchdog = p
+	}
+}
+
+// startWatchdog starts the watchdog if it's configured.
+func (c *SimpleContainer) startWatchdog(ctx context.Context) {
+	p := c.opts.watchdog
+	if p.Interval <= 0 {
+		return
+	}
+	if p.Timeout <= 0 {
+		p.Timeout = p.Interval
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	c.mux.Lock()
+	c.stopWatchdog = cancel
+	c.mux.Unlock()
+
+	go func() {
+		t := time.NewTicker(p.Interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				c.checkLiveness(ctx, p)
+			}
+		}
+	}()
+}
+
+// cancelWatchdog stops the watchdog started by startWatchdog.
+func (c *SimpleContainer) cancelWatchdog() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.stopWatchdog != nil {
+		c.stopWatchdog()
+		c.stopWatchdog = nil
+	}
+}
+
+// checkLiveness calls Liveness of started runners implementing
+// LivenessChecker.
+func (c *SimpleContainer) checkLiveness(ctx context.Context, p WatchdogPolicy) {
+	alive := true
+	for _, i := range c.startSequence() {
+		lc, ok := c.objects[i].(LivenessChecker)
+		if !ok || !c.stateOf(i).started {
+			continue
+		}
+
+		name := c.nameOf(i)
+		err := callWithTimeout(ctx, p.Timeout, name+".Liveness", lc.Liveness)
+		if ctx.Err() != nil {
+			return
+		}
+		c.setState(i, func(s *objectState) {
+			s.liveErr = err
+		})
+		if err == nil {
+			continue
+		}
+
+		alive = false
+		c.emit(Event{Type: LivenessFailed, Object: name, Err: err})
+		if p.Restart {
+			c.restart(i)
+		}
+	}
+	if alive {
+		c.emit(Event{Type: LivenessPassed})
+	}
+}
+
+// Restart cancels the context passed to Start of the supervised runner o
+// and restarts it immediately regardless of restart policy, see
+// WithSupervision. Restart returns false if o is not containered, not
+// started or the container does not manage runners.
+func (c *SimpleContainer) Restart(o interface{}) bool {
+	c.mux.RLock()
+	i := c.indexOf(o)
+	c.mux.RUnlock()
+
+	return i >= 0 && c.restart(i)
+}
+
+// restart restarts the supervised runner at position i.
+func (c *SimpleContainer) restart(i int) bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if !c.opts.managed || i >= len(c.states) || c.states[i].cancel == nil {
+		return false
+	}
+	c.states[i].restart = true
+	c.states[i].cancel()
+	return true
+}
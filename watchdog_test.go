@@ -0,0 +1,93 @@
+package sdi_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/axkit/sdi"
+)
+
+// worker runs a loop which can die silently.
+type worker struct {
+	starts int32
+	dead   int32
+}
+
+func (w *worker) Start(ctx context.Context) error {
+	atomic.AddInt32(&w.starts, 1)
+	atomic.StoreInt32(&w.dead, 0)
+	<-ctx.Done()
+	return nil
+}
+
+func (w *worker) Liveness(ctx context.Context) error {
+	if atomic.LoadInt32(&w.dead) == 1 {
+		return errors.New("loop exited")
+	}
+	return nil
+}
+
+func TestWatchdog(t *testing.T) {
+	var (
+		mux    sync.Mutex
+		failed []string
+	)
+	w := worker{}
+	cs := sdi.New(sdi.WithSupervision(sdi.RestartPolicy{}), sdi.WithWatchdog(sdi.WatchdogPolicy{
+		Interval: time.Millisecond,
+		Restart:  true,
+	}))
+	cs.Subscribe(func(e sdi.Event) {
+		if e.Type == sdi.LivenessFailed {
+			mux.Lock()
+			failed = append(failed, e.Object)
+			mux.Unlock()
+		}
+	})
+	cs.Add(&w)
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := cs.StartRunners(ctx); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return atomic.LoadInt32(&w.starts) == 1 })
+
+	atomic.StoreInt32(&w.dead, 1)
+	waitFor(t, func() bool { return atomic.LoadInt32(&w.starts) == 2 })
+
+	mux.Lock()
+	if len(failed) == 0 || failed[0] != "*sdi_test.worker" {
+		t.Errorf("expected liveness failure event, got %v", failed)
+	}
+	mux.Unlock()
+
+	waitFor(t, func() bool { return cs.Health(ctx)["*sdi_test.worker"] == nil })
+	cs.Stop(context.Background())
+}
+
+func TestWatchdogHealth(t *testing.T) {
+	w := worker{}
+	cs := sdi.New(sdi.WithManagedRunners(), sdi.WithWatchdog(sdi.WatchdogPolicy{Interval: time.Millisecond}))
+	cs.Add(&w)
+	cs.BuildDependencies()
+	cs.InitRequired(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cs.StartRunners(ctx)
+	waitFor(t, func() bool { return atomic.LoadInt32(&w.starts) == 1 })
+
+	atomic.StoreInt32(&w.dead, 1)
+	waitFor(t, func() bool { return cs.Health(ctx)["*sdi_test.worker"] != nil })
+	if n := atomic.LoadInt32(&w.starts); n != 1 {
+		t.Errorf("expected runner not to be restarted, got %d starts", n)
+	}
+	cs.Stop(context.Background())
+}